@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"booking/config"
 	"booking/container"
+	"booking/internal/apikey"
 	"booking/internal/booking"
+	"booking/internal/calendar"
 	"booking/internal/category"
+	"booking/internal/currency"
 	"booking/internal/facility"
+	"booking/internal/notification"
+	"booking/internal/organization"
+	"booking/internal/role"
+	"booking/internal/session"
 	"booking/internal/space"
 	spacefacility "booking/internal/space_facility"
 	"booking/internal/user"
+	"booking/internal/webhook"
+	"booking/pkg/middleware"
+	"booking/pkg/storage"
 	"booking/routes"
 
 	"github.com/labstack/echo/v4"
@@ -31,16 +42,57 @@ func main() {
 	userHandler := ctn.Get(container.UserHandlerDefName).(*user.UserHandler)
 	categoryHandler := ctn.Get(container.CategoryHandlerDefName).(*category.CategoryHandler)
 	spaceHandler := ctn.Get(container.SpaceHandlerDefName).(*space.SpaceHandler)
+	photoHandler := ctn.Get(container.PhotoHandlerDefName).(*space.PhotoHandler)
 	facilityHandler := ctn.Get(container.FacilityHandlerDefName).(*facility.FacilityHandler)
 	spaceFacilityHandler := ctn.Get(container.SpaceFacilityHandlerDefName).(*spacefacility.SpaceFacilityHandler)
 	bookingHandler := ctn.Get(container.BookingHandlerDefName).(*booking.BookingHandler)
+	currencyHandler := ctn.Get(container.CurrencyHandlerDefName).(*currency.CurrencyHandler)
+	calendarHandler := ctn.Get(container.CalendarHandlerDefName).(*calendar.CalendarHandler)
+	notificationHandler := ctn.Get(container.NotificationHandlerDefName).(*notification.NotificationHandler)
+	webhookHandler := ctn.Get(container.WebhookHandlerDefName).(*webhook.WebhookHandler)
+	organizationHandler := ctn.Get(container.OrganizationHandlerDefName).(*organization.OrganizationHandler)
+	roleHandler := ctn.Get(container.RoleHandlerDefName).(*role.RoleHandler)
+	sessionHandler := ctn.Get(container.SessionHandlerDefName).(*session.SessionHandler)
+	oidcHandler := ctn.Get(container.OIDCHandlerDefName).(*user.OIDCHandler)
+	adminHandler := ctn.Get(container.AdminHandlerDefName).(*user.AdminHandler)
+	apiKeyHandler := ctn.Get(container.APIKeyHandlerDefName).(*apikey.APIKeyHandler)
 
 	// Get middleware
 	authMiddleware := ctn.Get(container.AuthMiddlewareDefName).(echo.MiddlewareFunc)
-	adminMiddleware := ctn.Get(container.AdminAuthMiddlewareDefName).(echo.MiddlewareFunc)
+	requirePermission := ctn.Get(container.PermissionMiddlewareDefName).(middleware.PermissionFunc)
+	spaceOwnerMiddleware := ctn.Get(container.OwnerMiddlewareDefName).(echo.MiddlewareFunc)
+	tenantMiddleware := ctn.Get(container.TenantMiddlewareDefName).(echo.MiddlewareFunc)
+	platformMiddleware := ctn.Get(container.PlatformMiddlewareDefName).(echo.MiddlewareFunc)
+
+	// Sajikan file upload jika memakai storage lokal
+	if local, ok := ctn.Get(container.StorageDefName).(*storage.LocalStorage); ok {
+		e.Static("/uploads", local.Dir())
+	}
 
 	// Setup routes
-	routes.SetupRoutes(e, userHandler, categoryHandler, spaceHandler, facilityHandler, spaceFacilityHandler, bookingHandler, authMiddleware, adminMiddleware)
+	routes.SetupRoutes(e, userHandler, categoryHandler, spaceHandler, photoHandler, facilityHandler, spaceFacilityHandler, bookingHandler, currencyHandler, calendarHandler, notificationHandler, webhookHandler, organizationHandler, roleHandler, sessionHandler, oidcHandler, adminHandler, apiKeyHandler, authMiddleware, requirePermission, spaceOwnerMiddleware, tenantMiddleware, platformMiddleware)
+
+	// Key penanda tangan access token harus sudah ada sebelum server menerima request
+	keyRotator := ctn.Get(container.KeyRotatorDefName).(*session.KeyRotator)
+	if err := keyRotator.Refresh(context.Background()); err != nil {
+		log.Fatal("Cannot load token signing keys:", err)
+	}
+	// Daftar pencabutan harus tersinkron sebelum menerima request agar token yang sudah
+	// dicabut tidak diterima
+	revocations := ctn.Get(container.RevocationListDefName).(*session.RevocationList)
+	if err := revocations.Sync(context.Background()); err != nil {
+		log.Fatal("Cannot load token revocation list:", err)
+	}
+
+	// Start background workers
+	go keyRotator.Start(context.Background())
+	go revocations.Start(context.Background())
+	importer := ctn.Get(container.CalendarImporterDefName).(*calendar.Importer)
+	go importer.Start(context.Background())
+	dispatcher := ctn.Get(container.NotificationDispatcherDefName).(*notification.Dispatcher)
+	go dispatcher.Start(context.Background())
+	webhookDispatcher := ctn.Get(container.WebhookDispatcherDefName).(*webhook.Dispatcher)
+	go webhookDispatcher.Start(context.Background())
 
 	// Get config and start server
 	cfg := ctn.Get(container.ConfigDefName).(config.Config)
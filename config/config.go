@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -12,11 +14,69 @@ type Config struct {
 	DBName     string `mapstructure:"DB_NAME"`
 	JWTSecret  string `mapstructure:"JWT_SECRET"`
 	ServerPort string `mapstructure:"SERVER_PORT"`
+	AppURL     string `mapstructure:"APP_URL"`
+
+	// Session configuration (masa berlaku access token dan refresh token)
+	AccessTokenTTL  time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+
+	// Access token signing (HS256, RS256 atau EdDSA; key asimetris dirotasi otomatis)
+	JWTAlgorithm   string        `mapstructure:"JWT_ALGORITHM"`
+	JWTKeyRotation time.Duration `mapstructure:"JWT_KEY_ROTATION"`
+	JWTKeyGrace    time.Duration `mapstructure:"JWT_KEY_GRACE"`
+	// Interval sinkronisasi daftar pencabutan access token dari Redis
+	AuthRevocationSyncInterval time.Duration `mapstructure:"AUTH_REVOCATION_SYNC_INTERVAL"`
+
+	// Login protection (batas gagal login per email dan per IP, lama akun dikunci)
+	LoginMaxFailures     int           `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginIPMaxFailures   int           `mapstructure:"LOGIN_IP_MAX_FAILURES"`
+	LoginFailureWindow   time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	LoginLockoutDuration time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+
+	// OpenID Connect configuration (file JSON berisi daftar provider login)
+	OIDCProvidersFile string `mapstructure:"OIDC_PROVIDERS_FILE"`
 
 	// Redis configuration
 	RedisAddr     string `mapstructure:"REDIS_ADDR"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB       int    `mapstructure:"REDIS_DB"`
+
+	// Currency configuration
+	ExchangeRateFile string `mapstructure:"EXCHANGE_RATE_FILE"`
+
+	// Calendar configuration
+	CalendarSyncInterval time.Duration `mapstructure:"CALENDAR_SYNC_INTERVAL"`
+
+	// Mail configuration
+	MailDriver   string `mapstructure:"MAIL_DRIVER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailFileDir  string `mapstructure:"MAIL_FILE_DIR"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+
+	// Notification configuration
+	NotificationDispatchInterval time.Duration `mapstructure:"NOTIFICATION_DISPATCH_INTERVAL"`
+
+	// Storage configuration (foto space)
+	StorageDriver    string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalDir  string `mapstructure:"STORAGE_LOCAL_DIR"`
+	StoragePublicURL string `mapstructure:"STORAGE_PUBLIC_URL"`
+	S3Endpoint       string `mapstructure:"S3_ENDPOINT"`
+	S3Region         string `mapstructure:"S3_REGION"`
+	S3Bucket         string `mapstructure:"S3_BUCKET"`
+	S3AccessKey      string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey      string `mapstructure:"S3_SECRET_KEY"`
+	PhotoMaxSizeMB   int    `mapstructure:"PHOTO_MAX_SIZE_MB"`
+
+	// Webhook configuration
+	WebhookDispatchInterval time.Duration `mapstructure:"WEBHOOK_DISPATCH_INTERVAL"`
+
+	// Multi-tenant configuration
+	BaseDomain          string `mapstructure:"BASE_DOMAIN"`
+	DefaultOrganization string `mapstructure:"DEFAULT_ORGANIZATION"`
+	PlatformAPIKey      string `mapstructure:"PLATFORM_API_KEY"`
 }
 
 func LoadConfig() (config Config, err error) {
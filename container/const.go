@@ -1,28 +1,67 @@
 package container
 
 const (
-	ConfigDefName              string = "config"
-	DBDefName                  string = "db"
-	LoggerDefName              string = "logger"
-	EchoDefName                string = "echo"
-	ValidatorDefName           string = "validator"
-	RedisClientDefName         string = "redisClient"
-	AuthMiddlewareDefName      string = "authMiddleware"
-	AdminAuthMiddlewareDefName string = "adminAuthMiddleware"
+	ConfigDefName               string = "config"
+	DBDefName                   string = "db"
+	LoggerDefName               string = "logger"
+	EchoDefName                 string = "echo"
+	ValidatorDefName            string = "validator"
+	RedisClientDefName          string = "redisClient"
+	MailerDefName               string = "mailer"
+	StorageDefName              string = "storage"
+	LoginGuardDefName           string = "loginGuard"
+	KeySetDefName               string = "jwtKeySet"
+	AuthMiddlewareDefName       string = "authMiddleware"
+	PermissionMiddlewareDefName string = "permissionMiddleware"
+	OwnerMiddlewareDefName      string = "spaceOwnerMiddleware"
+	TenantMiddlewareDefName     string = "tenantMiddleware"
+	PlatformMiddlewareDefName   string = "platformMiddleware"
 
 	//Service
 	UserServiceDefName          string = "user.service"
 	CategoryServiceDefName      string = "category.service"
 	SpaceServiceDefName         string = "space.service"
+	PhotoServiceDefName         string = "photo.service"
 	SpaceFacilityServiceDefName string = "space_facility.service"
 	FacilityServiceDefName      string = "facility.service"
 	BookingServiceDefName       string = "booking.service"
+	CurrencyServiceDefName      string = "currency.service"
+	CalendarServiceDefName      string = "calendar.service"
+	NotificationServiceDefName  string = "notification.service"
+	WebhookServiceDefName       string = "webhook.service"
+	OrganizationServiceDefName  string = "organization.service"
+	RoleServiceDefName          string = "role.service"
+	SessionServiceDefName       string = "session.service"
+	VerificationServiceDefName  string = "verification.service"
+	PasswordServiceDefName      string = "password.service"
+	TwoFactorServiceDefName     string = "two_factor.service"
+	OIDCServiceDefName          string = "oidc.service"
+	APIKeyServiceDefName        string = "api_key.service"
+	AdminServiceDefName         string = "admin.service"
 
 	//Handler
 	UserHandlerDefName          string = "user.handler"
+	OIDCHandlerDefName          string = "oidc.handler"
+	AdminHandlerDefName         string = "admin.handler"
 	CategoryHandlerDefName      string = "category.handler"
 	SpaceHandlerDefName         string = "space.handler"
+	PhotoHandlerDefName         string = "photo.handler"
 	SpaceFacilityHandlerDefName string = "space_facility.handler"
 	FacilityHandlerDefName      string = "facility.handler"
 	BookingHandlerDefName       string = "booking.handler"
+	CurrencyHandlerDefName      string = "currency.handler"
+	CalendarHandlerDefName      string = "calendar.handler"
+	NotificationHandlerDefName  string = "notification.handler"
+	WebhookHandlerDefName       string = "webhook.handler"
+	OrganizationHandlerDefName  string = "organization.handler"
+	RoleHandlerDefName          string = "role.handler"
+	SessionHandlerDefName       string = "session.handler"
+	APIKeyHandlerDefName        string = "api_key.handler"
+
+	//Worker
+	CalendarImporterDefName       string = "calendar.importer"
+	NotificationDispatcherDefName string = "notification.dispatcher"
+	WebhookDispatcherDefName      string = "webhook.dispatcher"
+	KeyRotatorDefName             string = "session.keyRotator"
+	RevocationListDefName         string = "session.revocationList"
 )
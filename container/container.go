@@ -1,17 +1,33 @@
 package container
 
 import (
+	"context"
+	"strings"
+
 	"booking/config"
+	"booking/internal/apikey"
 	"booking/internal/booking"
+	"booking/internal/calendar"
 	"booking/internal/category"
+	"booking/internal/currency"
 	"booking/internal/facility"
+	"booking/internal/notification"
+	"booking/internal/organization"
+	"booking/internal/role"
+	"booking/internal/session"
 	"booking/internal/space"
 	spacefacility "booking/internal/space_facility"
 	"booking/internal/user"
+	"booking/internal/webhook"
 	"booking/pkg/database"
+	"booking/pkg/httpclient"
+	"booking/pkg/jwt"
 	"booking/pkg/logger"
+	"booking/pkg/mailer"
 	"booking/pkg/middleware"
+	"booking/pkg/oidc"
 	"booking/pkg/redis"
+	"booking/pkg/storage"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -52,14 +68,54 @@ func NewContainer() (di.Container, error) {
 				return redis.NewRedisClient(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB), nil
 			},
 		},
+		{
+			Name: MailerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				return mailer.New(cfg.MailDriver, cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom, cfg.MailFileDir)
+			},
+		},
+		{
+			Name: StorageDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				publicURL := cfg.StoragePublicURL
+				if publicURL == "" && (cfg.StorageDriver == "" || cfg.StorageDriver == "local") {
+					publicURL = strings.TrimRight(cfg.AppURL, "/") + "/uploads"
+				}
+				return storage.New(storage.Options{
+					Driver:      cfg.StorageDriver,
+					LocalDir:    cfg.StorageLocalDir,
+					PublicURL:   publicURL,
+					S3Endpoint:  cfg.S3Endpoint,
+					S3Region:    cfg.S3Region,
+					S3Bucket:    cfg.S3Bucket,
+					S3AccessKey: cfg.S3AccessKey,
+					S3SecretKey: cfg.S3SecretKey,
+				})
+			},
+		},
 		{
 			Name: UserServiceDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
 				cfg := ctn.Get(ConfigDefName).(config.Config)
 				db := ctn.Get(DBDefName).(*gorm.DB)
 				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				guard := ctn.Get(LoginGuardDefName).(*user.LoginGuard)
+				return user.NewUserService(db, logger, guard, cfg.AppURL), nil
+			},
+		},
+		{
+			Name: LoginGuardDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
 				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
-				return user.NewUserService(db, cfg.JWTSecret, logger, redisClient), nil
+				return user.NewLoginGuard(redisClient, user.LoginGuardConfig{
+					MaxFailures:   cfg.LoginMaxFailures,
+					IPMaxFailures: cfg.LoginIPMaxFailures,
+					Window:        cfg.LoginFailureWindow,
+					Lockout:       cfg.LoginLockoutDuration,
+				}), nil
 			},
 		},
 		{
@@ -73,7 +129,62 @@ func NewContainer() (di.Container, error) {
 			Name: UserHandlerDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
 				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
-				return user.NewUserHandler(userService), nil
+				roleService := ctn.Get(RoleServiceDefName).(role.RoleServiceInterface)
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				verificationService := ctn.Get(VerificationServiceDefName).(user.VerificationServiceInterface)
+				passwordService := ctn.Get(PasswordServiceDefName).(user.PasswordServiceInterface)
+				twoFactorService := ctn.Get(TwoFactorServiceDefName).(user.TwoFactorServiceInterface)
+				return user.NewUserHandler(userService, roleService, sessionService, verificationService, passwordService, twoFactorService), nil
+			},
+		},
+		{
+			Name: VerificationServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				mailer := ctn.Get(MailerDefName).(mailer.Mailer)
+				return user.NewVerificationService(db, logger, mailer, cfg.JWTSecret, cfg.AppURL), nil
+			},
+		},
+		{
+			Name: PasswordServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				mailer := ctn.Get(MailerDefName).(mailer.Mailer)
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				return user.NewPasswordService(db, logger, mailer, sessionService, cfg.JWTSecret, cfg.AppURL), nil
+			},
+		},
+		{
+			Name: AdminServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				passwordService := ctn.Get(PasswordServiceDefName).(user.PasswordServiceInterface)
+				return user.NewAdminService(db, logger, sessionService, passwordService), nil
+			},
+		},
+		{
+			Name: AdminHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				adminService := ctn.Get(AdminServiceDefName).(user.AdminServiceInterface)
+				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
+				roleService := ctn.Get(RoleServiceDefName).(role.RoleServiceInterface)
+				return user.NewAdminHandler(adminService, userService, roleService), nil
+			},
+		},
+		{
+			Name: TwoFactorServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				guard := ctn.Get(LoginGuardDefName).(*user.LoginGuard)
+				return user.NewTwoFactorService(db, logger, guard, cfg.JWTSecret), nil
 			},
 		},
 		{
@@ -86,15 +197,40 @@ func NewContainer() (di.Container, error) {
 		{
 			Name: AuthMiddlewareDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
-				cfg := ctn.Get(ConfigDefName).(config.Config)
 				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
-				return middleware.AuthMiddleware(userService, cfg.JWTSecret), nil
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				apiKeyService := ctn.Get(APIKeyServiceDefName).(apikey.APIKeyServiceInterface)
+				keys := ctn.Get(KeySetDefName).(*jwt.KeySet)
+				return middleware.AuthMiddleware(userService, sessionService, apiKeyService, keys), nil
 			},
 		},
 		{
-			Name: AdminAuthMiddlewareDefName,
+			Name: PermissionMiddlewareDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
-				return middleware.AdminMiddleware(), nil
+				roleService := ctn.Get(RoleServiceDefName).(role.RoleServiceInterface)
+				return middleware.NewPermissionFunc(roleService), nil
+			},
+		},
+		{
+			Name: OwnerMiddlewareDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				spaceService := ctn.Get(SpaceServiceDefName).(space.SpaceServiceInterface)
+				return middleware.SpaceOwnerMiddleware(spaceService), nil
+			},
+		},
+		{
+			Name: TenantMiddlewareDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				organizationService := ctn.Get(OrganizationServiceDefName).(organization.OrganizationServiceInterface)
+				return middleware.TenantMiddleware(organizationService, cfg.BaseDomain, cfg.DefaultOrganization), nil
+			},
+		},
+		{
+			Name: PlatformMiddlewareDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				return middleware.PlatformMiddleware(cfg.PlatformAPIKey), nil
 			},
 		},
 		{
@@ -116,7 +252,31 @@ func NewContainer() (di.Container, error) {
 			Name: SpaceServiceDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
 				db := ctn.Get(DBDefName).(*gorm.DB)
-				return space.NewSpaceService(db), nil
+				webhookService := ctn.Get(WebhookServiceDefName).(webhook.WebhookServiceInterface)
+				storage := ctn.Get(StorageDefName).(storage.Storage)
+				return space.NewSpaceService(db, webhookService, storage), nil
+			},
+		},
+		{
+			Name: PhotoServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				storage := ctn.Get(StorageDefName).(storage.Storage)
+				spaceService := ctn.Get(SpaceServiceDefName).(space.SpaceServiceInterface)
+				maxSizeMB := cfg.PhotoMaxSizeMB
+				if maxSizeMB <= 0 {
+					maxSizeMB = 10
+				}
+				return space.NewPhotoService(db, logger, storage, spaceService, int64(maxSizeMB)<<20), nil
+			},
+		},
+		{
+			Name: PhotoHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				photoService := ctn.Get(PhotoServiceDefName).(space.PhotoServiceInterface)
+				return space.NewPhotoHandler(photoService), nil
 			},
 		},
 		{
@@ -124,7 +284,10 @@ func NewContainer() (di.Container, error) {
 			Build: func(ctn di.Container) (interface{}, error) {
 				spaceService := ctn.Get(SpaceServiceDefName).(space.SpaceServiceInterface)
 				categoryService := ctn.Get(CategoryServiceDefName).(category.CategoryServiceInterface)
-				return space.NewSpaceHandler(spaceService, categoryService), nil
+				currencyService := ctn.Get(CurrencyServiceDefName).(currency.CurrencyServiceInterface)
+				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
+				roleService := ctn.Get(RoleServiceDefName).(role.RoleServiceInterface)
+				return space.NewSpaceHandler(spaceService, categoryService, currencyService, userService, roleService), nil
 			},
 		},
 		{
@@ -164,7 +327,11 @@ func NewContainer() (di.Container, error) {
 				logger := ctn.Get(LoggerDefName).(logger.Logger)
 				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
 				spaceService := ctn.Get(SpaceServiceDefName).(space.SpaceServiceInterface)
-				return booking.NewBookingService(db, logger, userService, spaceService), nil
+				currencyService := ctn.Get(CurrencyServiceDefName).(currency.CurrencyServiceInterface)
+				calendarService := ctn.Get(CalendarServiceDefName).(calendar.CalendarServiceInterface)
+				notificationService := ctn.Get(NotificationServiceDefName).(notification.NotificationServiceInterface)
+				webhookService := ctn.Get(WebhookServiceDefName).(webhook.WebhookServiceInterface)
+				return booking.NewBookingService(db, logger, userService, spaceService, currencyService, calendarService, notificationService, webhookService), nil
 			},
 		},
 		{
@@ -175,6 +342,231 @@ func NewContainer() (di.Container, error) {
 				return booking.NewBookingHandler(bookingService, logger), nil
 			},
 		},
+		{
+			Name: CurrencyServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				currencyService := currency.NewCurrencyService(db, logger)
+				// Muat nilai tukar awal dari file jika dikonfigurasi
+				if cfg.ExchangeRateFile != "" {
+					if _, err := currencyService.LoadFromFile(context.Background(), cfg.ExchangeRateFile); err != nil {
+						return nil, err
+					}
+				}
+				return currencyService, nil
+			},
+		},
+		{
+			Name: CurrencyHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				currencyService := ctn.Get(CurrencyServiceDefName).(currency.CurrencyServiceInterface)
+				return currency.NewCurrencyHandler(currencyService), nil
+			},
+		},
+		{
+			Name: CalendarServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				spaceService := ctn.Get(SpaceServiceDefName).(space.SpaceServiceInterface)
+				return calendar.NewCalendarService(db, logger, spaceService, httpclient.NewPublic(calendar.FetchTimeout)), nil
+			},
+		},
+		{
+			Name: CalendarImporterDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				calendarService := ctn.Get(CalendarServiceDefName).(calendar.CalendarServiceInterface)
+				return calendar.NewImporter(calendarService, logger, cfg.CalendarSyncInterval), nil
+			},
+		},
+		{
+			Name: CalendarHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				calendarService := ctn.Get(CalendarServiceDefName).(calendar.CalendarServiceInterface)
+				return calendar.NewCalendarHandler(calendarService, cfg.AppURL), nil
+			},
+		},
+		{
+			Name: NotificationServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				mailer := ctn.Get(MailerDefName).(mailer.Mailer)
+				return notification.NewNotificationService(db, logger, mailer), nil
+			},
+		},
+		{
+			Name: NotificationDispatcherDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				notificationService := ctn.Get(NotificationServiceDefName).(notification.NotificationServiceInterface)
+				return notification.NewDispatcher(notificationService, logger, cfg.NotificationDispatchInterval), nil
+			},
+		},
+		{
+			Name: NotificationHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				notificationService := ctn.Get(NotificationServiceDefName).(notification.NotificationServiceInterface)
+				return notification.NewNotificationHandler(notificationService), nil
+			},
+		},
+		{
+			Name: WebhookServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				return webhook.NewWebhookService(db, logger, httpclient.NewPublic(webhook.SendTimeout)), nil
+			},
+		},
+		{
+			Name: WebhookDispatcherDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				webhookService := ctn.Get(WebhookServiceDefName).(webhook.WebhookServiceInterface)
+				return webhook.NewDispatcher(webhookService, logger, cfg.WebhookDispatchInterval), nil
+			},
+		},
+		{
+			Name: WebhookHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				webhookService := ctn.Get(WebhookServiceDefName).(webhook.WebhookServiceInterface)
+				return webhook.NewWebhookHandler(webhookService), nil
+			},
+		},
+		{
+			Name: OrganizationServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				return organization.NewOrganizationService(db), nil
+			},
+		},
+		{
+			Name: OrganizationHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				organizationService := ctn.Get(OrganizationServiceDefName).(organization.OrganizationServiceInterface)
+				return organization.NewOrganizationHandler(organizationService), nil
+			},
+		},
+		{
+			Name: RoleServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				return role.NewRoleService(db, sessionService), nil
+			},
+		},
+		{
+			Name: RoleHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				roleService := ctn.Get(RoleServiceDefName).(role.RoleServiceInterface)
+				return role.NewRoleHandler(roleService), nil
+			},
+		},
+		{
+			Name: SessionServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				keys := ctn.Get(KeySetDefName).(*jwt.KeySet)
+				revocations := ctn.Get(RevocationListDefName).(*session.RevocationList)
+				return session.NewSessionService(db, logger, keys, revocations, cfg.AccessTokenTTL, cfg.RefreshTokenTTL), nil
+			},
+		},
+		{
+			Name: RevocationListDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				return session.NewRevocationList(redisClient, logger, cfg.AccessTokenTTL, cfg.AuthRevocationSyncInterval), nil
+			},
+		},
+		{
+			Name: KeySetDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				// Tanpa JWT_ALGORITHM access token tetap ditandatangani HS256 dengan JWT_SECRET
+				if cfg.JWTAlgorithm == "" || cfg.JWTAlgorithm == jwt.AlgHS256 {
+					return jwt.NewHMACKeySet(cfg.JWTSecret, cfg.AppURL), nil
+				}
+				return jwt.NewKeySet(cfg.JWTAlgorithm, cfg.AppURL)
+			},
+		},
+		{
+			Name: KeyRotatorDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				keys := ctn.Get(KeySetDefName).(*jwt.KeySet)
+				return session.NewKeyRotator(db, logger, keys, cfg.JWTSecret, cfg.JWTKeyRotation, cfg.JWTKeyGrace, cfg.AccessTokenTTL), nil
+			},
+		},
+		{
+			Name: OIDCServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+
+				var providers []*oidc.Provider
+				if cfg.OIDCProvidersFile != "" {
+					configs, err := oidc.LoadConfigs(cfg.OIDCProvidersFile)
+					if err != nil {
+						return nil, err
+					}
+					for _, providerCfg := range configs {
+						if providerCfg.RedirectURL == "" {
+							providerCfg.RedirectURL = strings.TrimRight(cfg.AppURL, "/") + "/auth/oidc/" + providerCfg.Name + "/callback"
+						}
+						providers = append(providers, oidc.NewProvider(providerCfg, nil))
+					}
+				}
+				return user.NewOIDCService(db, redisClient, logger, sessionService, providers), nil
+			},
+		},
+		{
+			Name: OIDCHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				oidcService := ctn.Get(OIDCServiceDefName).(user.OIDCServiceInterface)
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				twoFactorService := ctn.Get(TwoFactorServiceDefName).(user.TwoFactorServiceInterface)
+				return user.NewOIDCHandler(oidcService, sessionService, twoFactorService), nil
+			},
+		},
+		{
+			Name: SessionHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				sessionService := ctn.Get(SessionServiceDefName).(session.SessionServiceInterface)
+				return session.NewSessionHandler(sessionService), nil
+			},
+		},
+		{
+			Name: APIKeyServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				roleService := ctn.Get(RoleServiceDefName).(role.RoleServiceInterface)
+				return apikey.NewAPIKeyService(db, logger, roleService), nil
+			},
+		},
+		{
+			Name: APIKeyHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				apiKeyService := ctn.Get(APIKeyServiceDefName).(apikey.APIKeyServiceInterface)
+				return apikey.NewAPIKeyHandler(apiKeyService), nil
+			},
+		},
 	}
 
 	if err := builder.Add(defs...); err != nil {
@@ -0,0 +1,107 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/apikey/model"
+	userModel "booking/internal/user/model"
+	"booking/pkg/response"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	apiKeyService APIKeyServiceInterface
+}
+
+func NewAPIKeyHandler(apiKeyService APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Create membuat API key untuk user yang login; nilai key hanya ditampilkan sekali
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	user, ok := c.Get("principal").(*userModel.Principal)
+	if !ok || user == nil {
+		return response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	var input model.APIKeyInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	key, err := h.apiKeyService.Create(c.Request().Context(), user.UserID.String(), user.Role, input)
+	if err != nil {
+		return h.errorResponse(c, "failed to create api key", err)
+	}
+
+	return response.Success(c, http.StatusCreated, "API key created successfully; store it now, it will not be shown again", key)
+}
+
+// GetAll menampilkan API key milik user yang login
+func (h *APIKeyHandler) GetAll(c echo.Context) error {
+	user, ok := c.Get("principal").(*userModel.Principal)
+	if !ok || user == nil {
+		return response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	return h.list(c, user.UserID.String())
+}
+
+// Revoke mencabut API key milik user yang login
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	user, ok := c.Get("principal").(*userModel.Principal)
+	if !ok || user == nil {
+		return response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	return h.revoke(c, user.UserID.String(), c.Param("id"))
+}
+
+// GetByUser menampilkan API key milik user lain (permission user:manage)
+func (h *APIKeyHandler) GetByUser(c echo.Context) error {
+	return h.list(c, c.Param("id"))
+}
+
+// RevokeForUser mencabut API key milik user lain (permission user:manage)
+func (h *APIKeyHandler) RevokeForUser(c echo.Context) error {
+	return h.revoke(c, c.Param("id"), c.Param("keyId"))
+}
+
+func (h *APIKeyHandler) list(c echo.Context, userID string) error {
+	keys, err := h.apiKeyService.GetByUser(c.Request().Context(), userID)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get api keys", err)
+	}
+
+	result := make([]model.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		result = append(result, keys[i].ToResponse())
+	}
+	return response.Success(c, http.StatusOK, "API keys retrieved successfully", result)
+}
+
+func (h *APIKeyHandler) revoke(c echo.Context, userID, keyID string) error {
+	if err := h.apiKeyService.Revoke(c.Request().Context(), userID, keyID); err != nil {
+		return h.errorResponse(c, "failed to revoke api key", err)
+	}
+
+	return response.Success(c, http.StatusOK, "API key revoked successfully", nil)
+}
+
+func (h *APIKeyHandler) errorResponse(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, ErrAPIKeyNotFound):
+		return response.NotFound(c, err.Error(), err)
+	case errors.Is(err, ErrScopeNotAllowed):
+		return response.Forbidden(c, err.Error(), err)
+	case errors.Is(err, ErrTooManyAPIKeys):
+		return response.Conflict(c, err.Error(), err, nil)
+	default:
+		return response.BadRequest(c, message, err)
+	}
+}
@@ -0,0 +1,154 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/apikey/model"
+	"booking/internal/role"
+	"booking/pkg/logger"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrInvalidAPIKey   = errors.New("invalid, revoked or expired api key")
+	ErrScopeNotAllowed = errors.New("api key scopes exceed your permissions")
+	ErrTooManyAPIKeys  = errors.New("too many active api keys")
+)
+
+// APIKeyServiceInterface mendefinisikan kontrak untuk APIKeyService
+type APIKeyServiceInterface interface {
+	Create(ctx context.Context, userID string, userRole constants.Role, input model.APIKeyInput) (*model.CreatedAPIKey, error)
+	GetByUser(ctx context.Context, userID string) ([]model.APIKey, error)
+	Revoke(ctx context.Context, userID string, keyID string) error
+	Authenticate(ctx context.Context, rawKey string, ipAddress string) (*model.APIKey, error)
+}
+
+type APIKeyService struct {
+	db          *gorm.DB
+	logger      logger.Logger
+	roleService role.RoleServiceInterface
+}
+
+func NewAPIKeyService(db *gorm.DB, logger logger.Logger, roleService role.RoleServiceInterface) *APIKeyService {
+	return &APIKeyService{
+		db:          db,
+		logger:      logger,
+		roleService: roleService,
+	}
+}
+
+// Create membuat API key untuk user; scope tidak boleh melebihi permission role user
+func (s *APIKeyService) Create(ctx context.Context, userID string, userRole constants.Role, input model.APIKeyInput) (*model.CreatedAPIKey, error) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key, raw, err := model.NewAPIKey(ownerID, input, now)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := s.roleService.Permissions(ctx, userRole)
+	if err != nil {
+		return nil, err
+	}
+	if !granted.Covers(key.ScopeSet()) {
+		return nil, ErrScopeNotAllowed
+	}
+
+	var active int64
+	err = s.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", ownerID, now).
+		Count(&active).Error
+	if err != nil {
+		return nil, err
+	}
+	if active >= model.MaxActivePerUser {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	if err := s.db.WithContext(ctx).Create(key).Error; err != nil {
+		return nil, err
+	}
+
+	return &model.CreatedAPIKey{
+		APIKeyResponse: key.ToResponse(),
+		Key:            raw,
+	}, nil
+}
+
+// GetByUser menampilkan API key milik user yang belum dicabut, termasuk yang sudah kedaluwarsa
+func (s *APIKeyService) GetByUser(ctx context.Context, userID string) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Revoke mencabut API key milik user; key yang sudah dicabut dianggap tidak ada
+func (s *APIKeyService) Revoke(ctx context.Context, userID string, keyID string) error {
+	if _, err := uuid.Parse(keyID); err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	result := s.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate mencari API key dari nilai aslinya dan mencatat pemakaian terakhirnya
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string, ipAddress string) (*model.APIKey, error) {
+	if !model.IsAPIKey(rawKey) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	var key model.APIKey
+	if err := s.db.WithContext(ctx).Where("key_hash = ?", model.HashKey(rawKey)).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.NeedsTouch(ipAddress, now) {
+		key.Touch(ipAddress, now)
+		err := s.db.WithContext(ctx).Model(&key).UpdateColumns(map[string]interface{}{
+			"last_used_at": key.LastUsedAt,
+			"last_used_ip": key.LastUsedIP,
+		}).Error
+		// Gagal mencatat pemakaian tidak boleh menolak request
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"api_key_id": key.ID,
+				"error":      err.Error(),
+			}).Warn("Gagal mencatat pemakaian API key")
+		}
+	}
+	return &key, nil
+}
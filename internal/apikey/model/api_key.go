@@ -0,0 +1,165 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	roleModel "booking/internal/role/model"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// KeyPrefix menandai bearer token sebagai API key sehingga bisa dibedakan dari JWT
+	KeyPrefix = "bk_"
+	// MaxActivePerUser membatasi jumlah API key aktif milik satu user
+	MaxActivePerUser = 25
+
+	keyBytes        = 32
+	displayLen      = len(KeyPrefix) + 8
+	touchInterval   = time.Minute
+	maxIPAddressLen = 45
+)
+
+// APIKey adalah kredensial jangka panjang untuk klien mesin (script, integrasi internal).
+// Hanya hash key yang disimpan; nilai aslinya ditampilkan sekali saat dibuat. Scopes
+// membatasi permission role pemilik, bukan menambahnya.
+type APIKey struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:char(36);not null;index"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:char(36);not null;index"`
+	Name           string     `json:"name" gorm:"size:100;not null"`
+	Prefix         string     `json:"prefix" gorm:"size:16;not null"`
+	KeyHash        string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	Scopes         string     `json:"-" gorm:"type:text;not null"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP     string     `json:"last_used_ip,omitempty" gorm:"size:45"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// DTO: Create API key input; expires_at kosong berarti tidak kedaluwarsa
+type APIKeyInput struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	APIKey
+	Scopes []string `json:"scopes"`
+}
+
+// CreatedAPIKey dikembalikan sekali saat key dibuat, berisi nilai key yang asli
+type CreatedAPIKey struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// NewAPIKey membuat API key baru beserta nilai aslinya
+func NewAPIKey(userID uuid.UUID, input APIKeyInput, now time.Time) (*APIKey, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", errors.New("name is required")
+	}
+	scopes, err := roleModel.NormalizePermissions(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if scopes == "" {
+		return nil, "", errors.New("at least one scope is required")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return nil, "", errors.New("expires_at must be in the future")
+	}
+
+	buf := make([]byte, keyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	raw := KeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	return &APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:displayLen],
+		KeyHash:   HashKey(raw),
+		Scopes:    scopes,
+		ExpiresAt: input.ExpiresAt,
+	}, raw, nil
+}
+
+// IsAPIKey mengecek apakah bearer token berbentuk API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, KeyPrefix)
+}
+
+// HashKey menghitung hash SHA-256 (hex) dari API key
+func HashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active mengecek apakah key belum dicabut dan belum kedaluwarsa
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+func (k *APIKey) Revoke(now time.Time) {
+	if k.RevokedAt == nil {
+		k.RevokedAt = &now
+	}
+}
+
+// NeedsTouch mengecek apakah last_used perlu diperbarui; dibatasi sekali per menit agar
+// setiap request tidak menulis ke database
+func (k *APIKey) NeedsTouch(ipAddress string, now time.Time) bool {
+	if k.LastUsedAt == nil || k.LastUsedIP != truncate(ipAddress, maxIPAddressLen) {
+		return true
+	}
+	return now.Sub(*k.LastUsedAt) >= touchInterval
+}
+
+// Touch mencatat waktu dan IP terakhir key dipakai
+func (k *APIKey) Touch(ipAddress string, now time.Time) {
+	k.LastUsedAt = &now
+	k.LastUsedIP = truncate(ipAddress, maxIPAddressLen)
+}
+
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k *APIKey) ScopeSet() roleModel.PermissionSet {
+	set := roleModel.PermissionSet{}
+	for _, p := range k.ScopeList() {
+		set[constants.Permission(p)] = true
+	}
+	return set
+}
+
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		APIKey: *k,
+		Scopes: k.ScopeList(),
+	}
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
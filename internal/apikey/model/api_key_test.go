@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+}
+
+func TestAPIKeySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
+
+func (s *APIKeyTestSuite) TestNewAPIKey() {
+	now := time.Now()
+	key, raw, err := NewAPIKey(uuid.New(), APIKeyInput{
+		Name:   " deploy script ",
+		Scopes: []string{"space:write", "booking:manage", "space:write"},
+	}, now)
+	s.Require().NoError(err)
+
+	s.True(IsAPIKey(raw))
+	s.True(strings.HasPrefix(raw, key.Prefix))
+	s.Equal(HashKey(raw), key.KeyHash)
+	s.Equal("deploy script", key.Name)
+	s.Equal([]string{"booking:manage", "space:write"}, key.ScopeList())
+	s.True(key.ScopeSet().Has(constants.PermissionSpaceWrite))
+	s.True(key.Active(now.Add(365 * 24 * time.Hour)))
+
+	key.Revoke(now)
+	s.False(key.Active(now))
+}
+
+func (s *APIKeyTestSuite) TestNewAPIKeyRejectsInvalidInput() {
+	past := time.Now().Add(-time.Hour)
+	tests := map[string]APIKeyInput{
+		"unknown scope": {Name: "ci", Scopes: []string{"space:delete"}},
+		"empty scope":   {Name: "ci", Scopes: []string{}},
+		"blank name":    {Name: "  ", Scopes: []string{"space:write"}},
+		"expired":       {Name: "ci", Scopes: []string{"space:write"}, ExpiresAt: &past},
+	}
+	for name, input := range tests {
+		_, _, err := NewAPIKey(uuid.New(), input, time.Now())
+		s.Error(err, name)
+	}
+}
+
+func (s *APIKeyTestSuite) TestExpiryAndTouch() {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	key, _, err := NewAPIKey(uuid.New(), APIKeyInput{Name: "ci", Scopes: []string{"*"}, ExpiresAt: &expiresAt}, now)
+	s.Require().NoError(err)
+	s.True(key.Active(now))
+	s.False(key.Active(expiresAt))
+
+	s.True(key.NeedsTouch("10.0.0.1", now))
+	key.Touch("10.0.0.1", now)
+	s.False(key.NeedsTouch("10.0.0.1", now.Add(30*time.Second)))
+	s.True(key.NeedsTouch("10.0.0.2", now.Add(30*time.Second)))
+	s.True(key.NeedsTouch("10.0.0.1", now.Add(time.Minute)))
+}
@@ -4,6 +4,8 @@ import (
 	"booking/internal/booking/model"
 	"booking/pkg/logger"
 	"booking/pkg/response"
+	errs "booking/shared/errors"
+	"errors"
 	"net/http"
 	"time"
 
@@ -36,21 +38,11 @@ func (h *BookingHandler) Create(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, "invalid request body", err)
 	}
 
-	// Parse dates
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	startDate, endDate, err := parseStayDates(req.StartDate, req.EndDate)
 	if err != nil {
-		return response.Error(c, http.StatusBadRequest, "invalid start_date format. Use YYYY-MM-DD", err)
+		return response.Error(c, http.StatusBadRequest, err.Error(), err)
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
-	if err != nil {
-		return response.Error(c, http.StatusBadRequest, "invalid end_date format. Use YYYY-MM-DD", err)
-	}
-
-	// Set time to check-in (14:00) and check-out (12:00)
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 14, 0, 0, 0, time.Local)
-	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 12, 0, 0, 0, time.Local)
-
 	// Get user ID from context
 	userIDStr, ok := c.Get("user_id").(string)
 	if !ok {
@@ -67,6 +59,7 @@ func (h *BookingHandler) Create(c echo.Context) error {
 		SpaceID:   req.SpaceID,
 		StartDate: startDate,
 		EndDate:   endDate,
+		Currency:  c.QueryParam("currency"),
 	}
 
 	// Validate input
@@ -93,12 +86,47 @@ func (h *BookingHandler) Create(c echo.Context) error {
 			"space_id": input.SpaceID,
 			"error":    err.Error(),
 		}).Error(c.Request().Context(), "failed to create booking")
+		if errors.Is(err, errs.ErrUnsupportedCurrency) {
+			return response.Error(c, http.StatusBadRequest, err.Error(), err)
+		}
+		if errors.Is(err, errs.ErrEmailNotVerified) {
+			return response.Forbidden(c, "please verify your email address before booking", err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err.Error(), err)
 	}
 
 	return response.Success(c, http.StatusCreated, "booking created successfully", booking.ToResponse())
 }
 
+// Quote menghitung harga booking tanpa menyimpannya; currency opsional (default IDR)
+func (h *BookingHandler) Quote(c echo.Context) error {
+	spaceID, err := uuid.Parse(c.QueryParam("space_id"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid space_id", err)
+	}
+
+	startDate, endDate, err := parseStayDates(c.QueryParam("start_date"), c.QueryParam("end_date"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), err)
+	}
+
+	if startDate.After(endDate) {
+		return response.Error(c, http.StatusBadRequest, "start_date must be before end_date", nil)
+	}
+
+	quote, err := h.service.Quote(c.Request().Context(), model.QuoteInput{
+		SpaceID:   spaceID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Currency:  c.QueryParam("currency"),
+	})
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), err)
+	}
+
+	return response.Success(c, http.StatusOK, "quote calculated successfully", quote)
+}
+
 func (h *BookingHandler) GetByID(c echo.Context) error {
 	// Get user ID from context
 	userIDStr, ok := c.Get("user_id").(string)
@@ -186,3 +214,91 @@ func (h *BookingHandler) Cancel(c echo.Context) error {
 
 	return response.Success(c, http.StatusOK, "booking cancelled successfully", nil)
 }
+
+// GetForOwner menampilkan booking pada space milik host; :id opsional untuk satu space
+func (h *BookingHandler) GetForOwner(c echo.Context) error {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	ownerID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, "invalid user id", err)
+	}
+
+	bookings, err := h.service.GetForOwner(c.Request().Context(), ownerID, c.Param("id"))
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"owner_id": ownerID,
+			"error":    err.Error(),
+		}).Error(c.Request().Context(), "failed to get host bookings")
+		return response.Error(c, http.StatusInternalServerError, "failed to get bookings", err)
+	}
+
+	responseBookings := make([]model.BookingResponse, 0, len(bookings))
+	for _, booking := range bookings {
+		responseBookings = append(responseBookings, booking.ToResponse())
+	}
+
+	return response.Success(c, http.StatusOK, "bookings retrieved successfully", responseBookings)
+}
+
+// CancelForOwner membatalkan booking pada space milik host
+func (h *BookingHandler) CancelForOwner(c echo.Context) error {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	ownerID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, "invalid user id", err)
+	}
+
+	bookingID := c.Param("id")
+	booking, err := h.service.CancelForOwner(c.Request().Context(), bookingID, ownerID)
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"booking_id": bookingID,
+			"owner_id":   ownerID,
+			"error":      err.Error(),
+		}).Error(c.Request().Context(), "failed to cancel booking as host")
+		return response.Error(c, http.StatusBadRequest, err.Error(), err)
+	}
+
+	return response.Success(c, http.StatusOK, "booking cancelled successfully", booking.ToResponse())
+}
+
+// parseStayDates mem-parsing tanggal YYYY-MM-DD lalu mengatur jam check-in (14:00) dan check-out (12:00)
+func parseStayDates(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start_date format. Use YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end_date format. Use YYYY-MM-DD")
+	}
+
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 14, 0, 0, 0, time.Local)
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 12, 0, 0, 0, time.Local)
+	return startDate, endDate, nil
+}
+
+// MarkPaid menandai booking sudah dibayar (admin)
+func (h *BookingHandler) MarkPaid(c echo.Context) error {
+	bookingID := c.Param("id")
+
+	booking, err := h.service.MarkPaid(c.Request().Context(), bookingID)
+	if err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"booking_id": bookingID,
+			"error":      err.Error(),
+		}).Error(c.Request().Context(), "failed to mark booking as paid")
+		return response.Error(c, http.StatusBadRequest, err.Error(), err)
+	}
+
+	return response.Success(c, http.StatusOK, "booking marked as paid", booking.ToResponse())
+}
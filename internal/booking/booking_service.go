@@ -2,9 +2,16 @@ package booking
 
 import (
 	"booking/internal/booking/model"
+	"booking/internal/calendar"
+	"booking/internal/currency"
+	"booking/internal/notification"
+	notificationModel "booking/internal/notification/model"
 	"booking/internal/space"
 	"booking/internal/user"
+	"booking/internal/webhook"
 	"booking/pkg/logger"
+	"booking/shared/constants"
+	errs "booking/shared/errors"
 	"context"
 	"errors"
 	"time"
@@ -16,73 +23,67 @@ import (
 
 type BookingServiceInterface interface {
 	Create(ctx context.Context, input model.CreateBookingInput) (*model.Booking, error)
+	Quote(ctx context.Context, input model.QuoteInput) (*model.Quote, error)
 	GetByID(ctx context.Context, id string) (*model.Booking, error)
 	GetAll(ctx context.Context, userID uuid.UUID) ([]model.Booking, error)
 	Cancel(ctx context.Context, bookingID string, userID uuid.UUID) error
+	GetForOwner(ctx context.Context, ownerID uuid.UUID, spaceID string) ([]model.Booking, error)
+	CancelForOwner(ctx context.Context, bookingID string, ownerID uuid.UUID) (*model.Booking, error)
+	MarkPaid(ctx context.Context, bookingID string) (*model.Booking, error)
 }
 
 type BookingService struct {
-	db           *gorm.DB
-	logger       logger.Logger
-	userService  user.UserServiceInterface
-	spaceService space.SpaceServiceInterface
+	db                  *gorm.DB
+	logger              logger.Logger
+	userService         user.UserServiceInterface
+	spaceService        space.SpaceServiceInterface
+	currencyService     currency.CurrencyServiceInterface
+	calendarService     calendar.CalendarServiceInterface
+	notificationService notification.NotificationServiceInterface
+	webhookService      webhook.WebhookServiceInterface
 }
 
-func NewBookingService(db *gorm.DB, logger logger.Logger, userService user.UserServiceInterface, spaceService space.SpaceServiceInterface) *BookingService {
+func NewBookingService(db *gorm.DB, logger logger.Logger, userService user.UserServiceInterface, spaceService space.SpaceServiceInterface, currencyService currency.CurrencyServiceInterface, calendarService calendar.CalendarServiceInterface, notificationService notification.NotificationServiceInterface, webhookService webhook.WebhookServiceInterface) *BookingService {
 	return &BookingService{
-		db:           db,
-		logger:       logger,
-		userService:  userService,
-		spaceService: spaceService,
+		db:                  db,
+		logger:              logger,
+		userService:         userService,
+		spaceService:        spaceService,
+		currencyService:     currencyService,
+		calendarService:     calendarService,
+		notificationService: notificationService,
+		webhookService:      webhookService,
 	}
 }
 
 func (s *BookingService) Create(ctx context.Context, input model.CreateBookingInput) (*model.Booking, error) {
 	// Validasi user exists
-	if _, err := s.userService.GetUserByID(ctx, input.UserID.String()); err != nil {
+	user, err := s.userService.GetUserByID(ctx, input.UserID.String())
+	if err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"user_id": input.UserID,
 			"error":   err.Error(),
 		}).Error(ctx, "failed to get user")
 		return nil, errors.New("user not found")
 	}
-
-	// Validasi space exists
-	space, err := s.spaceService.GetByID(input.SpaceID.String())
-	if err != nil {
-		s.logger.WithFields(logrus.Fields{
-			"space_id": input.SpaceID,
-			"error":    err.Error(),
-		}).Error(ctx, "failed to get space")
-		return nil, errors.New("space not found")
-	}
-
-	// Validasi space is active
-	if !space.IsActive {
-		return nil, errors.New("space is not active")
-	}
-
-	// Validasi tanggal tidak boleh lebih kecil dari hari ini
-	today := time.Now().Truncate(24 * time.Hour)
-	if input.StartDate.Before(today) {
-		return nil, errors.New("start date must be today or later")
+	if !user.IsEmailVerified() {
+		return nil, errs.ErrEmailNotVerified
 	}
 
-	// Hitung durasi booking dalam hari
-	startDate := time.Date(input.StartDate.Year(), input.StartDate.Month(), input.StartDate.Day(), 0, 0, 0, 0, time.Local)
-	endDate := time.Date(input.EndDate.Year(), input.EndDate.Month(), input.EndDate.Day(), 0, 0, 0, 0, time.Local)
-	duration := endDate.Sub(startDate).Hours() / 24
-
-	if duration < 1 {
-		return nil, errors.New("minimum booking duration is 1 day")
+	// Hitung harga (sekaligus validasi space dan tanggal)
+	quote, err := s.Quote(ctx, model.QuoteInput{
+		SpaceID:   input.SpaceID,
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		Currency:  input.Currency,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Hitung total harga
-	totalPrice := space.PricePerNight * float64(duration)
-
 	// Cek apakah ada booking yang overlap
 	var count int64
-	err = s.db.Model(&model.Booking{}).
+	err = s.db.WithContext(ctx).Model(&model.Booking{}).
 		Where("space_id = ? AND status != ? AND ((start_date <= ? AND end_date > ?) OR (start_date < ? AND end_date >= ?) OR (start_date >= ? AND start_date < ?))",
 			input.SpaceID,
 			"cancelled",
@@ -103,8 +104,36 @@ func (s *BookingService) Create(ctx context.Context, input model.CreateBookingIn
 		return nil, errors.New("space is already booked for the selected dates")
 	}
 
+	// Cek apakah tanggal bertabrakan dengan blackout
+	blocked, err := s.spaceService.HasBlackout(ctx, input.SpaceID, input.StartDate, input.EndDate)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"space_id": input.SpaceID,
+			"error":    err.Error(),
+		}).Error(ctx, "failed to check space blackout")
+		return nil, errors.New("failed to check booking availability")
+	}
+
+	if blocked {
+		return nil, errors.New("space is not available for the selected dates")
+	}
+
+	// Cek apakah tanggal sudah terisi booking dari platform lain
+	blocked, err = s.calendarService.HasExternalBlock(ctx, input.SpaceID, input.StartDate, input.EndDate)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"space_id": input.SpaceID,
+			"error":    err.Error(),
+		}).Error(ctx, "failed to check external calendar blocks")
+		return nil, errors.New("failed to check booking availability")
+	}
+
+	if blocked {
+		return nil, errors.New("space is already booked for the selected dates")
+	}
+
 	// Buat booking baru
-	booking, err := model.NewBooking(input, totalPrice)
+	booking, err := model.NewBooking(input, quote)
 	if err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"user_id":  input.UserID,
@@ -114,8 +143,17 @@ func (s *BookingService) Create(ctx context.Context, input model.CreateBookingIn
 		return nil, err
 	}
 
-	// Simpan ke database
-	if err := s.db.WithContext(ctx).Create(booking).Error; err != nil {
+	// Simpan ke database bersama notifikasi di outbox dalam satu transaksi
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(booking).Error; err != nil {
+			return err
+		}
+		if err := s.notificationService.EnqueueBooking(tx, constants.EventBookingCreated, user, bookingMessageData(booking, quote.SpaceName)); err != nil {
+			return err
+		}
+		return s.webhookService.Publish(tx, constants.EventBookingCreated, booking.ToResponse())
+	})
+	if err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"user_id":  input.UserID,
 			"space_id": input.SpaceID,
@@ -127,6 +165,61 @@ func (s *BookingService) Create(ctx context.Context, input model.CreateBookingIn
 	return booking, nil
 }
 
+// Quote menghitung harga booking dalam IDR beserta konversinya ke mata uang tampilan
+func (s *BookingService) Quote(ctx context.Context, input model.QuoteInput) (*model.Quote, error) {
+	// Validasi space exists
+	space, err := s.spaceService.GetByID(ctx, input.SpaceID.String())
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"space_id": input.SpaceID,
+			"error":    err.Error(),
+		}).Error(ctx, "failed to get space")
+		return nil, errors.New("space not found")
+	}
+
+	// Validasi space is active
+	if !space.IsActive {
+		return nil, errors.New("space is not active")
+	}
+
+	// Validasi tanggal tidak boleh lebih kecil dari hari ini
+	today := time.Now().Truncate(24 * time.Hour)
+	if input.StartDate.Before(today) {
+		return nil, errors.New("start date must be today or later")
+	}
+
+	// Hitung durasi booking dalam hari
+	startDate := time.Date(input.StartDate.Year(), input.StartDate.Month(), input.StartDate.Day(), 0, 0, 0, 0, time.Local)
+	endDate := time.Date(input.EndDate.Year(), input.EndDate.Month(), input.EndDate.Day(), 0, 0, 0, 0, time.Local)
+	duration := endDate.Sub(startDate).Hours() / 24
+
+	if duration < 1 {
+		return nil, errors.New("minimum booking duration is 1 day")
+	}
+
+	// Hitung total harga
+	totalPrice := space.PricePerNight * float64(duration)
+
+	// Konversi ke mata uang yang diminta
+	conversion, err := s.currencyService.Convert(ctx, totalPrice, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Quote{
+		SpaceID:       input.SpaceID,
+		SpaceName:     space.Name,
+		StartDate:     input.StartDate.Format("2006-01-02 15:04:05"),
+		EndDate:       input.EndDate.Format("2006-01-02 15:04:05"),
+		Nights:        int(duration),
+		PricePerNight: space.PricePerNight,
+		TotalPrice:    totalPrice,
+		Currency:      conversion.Currency,
+		ExchangeRate:  conversion.Rate,
+		ChargedAmount: conversion.Amount,
+	}, nil
+}
+
 func (s *BookingService) GetByID(ctx context.Context, id string) (*model.Booking, error) {
 	var booking model.Booking
 	if err := s.db.WithContext(ctx).First(&booking, "id = ?", id).Error; err != nil {
@@ -166,10 +259,120 @@ func (s *BookingService) Cancel(ctx context.Context, bookingID string, userID uu
 	}
 
 	// Update booking status
-	booking.Status = "cancelled"
-	if err := s.db.WithContext(ctx).Save(&booking).Error; err != nil {
+	if err := booking.UpdateStatus(constants.BookingStatusCancelled); err != nil {
+		return err
+	}
+
+	return s.saveWithNotification(ctx, &booking, constants.EventBookingCancelled)
+}
+
+// GetForOwner mengambil booking untuk space milik host; spaceID opsional untuk satu space saja
+func (s *BookingService) GetForOwner(ctx context.Context, ownerID uuid.UUID, spaceID string) ([]model.Booking, error) {
+	query := s.db.WithContext(ctx).
+		Joins("JOIN spaces ON spaces.id = bookings.space_id").
+		Where("spaces.owner_id = ?", ownerID)
+	if spaceID != "" {
+		query = query.Where("bookings.space_id = ?", spaceID)
+	}
+
+	var bookings []model.Booking
+	if err := query.Order("bookings.start_date DESC").Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// CancelForOwner membatalkan booking pada space milik host
+func (s *BookingService) CancelForOwner(ctx context.Context, bookingID string, ownerID uuid.UUID) (*model.Booking, error) {
+	booking, err := s.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	space, err := s.spaceService.GetByID(ctx, booking.SpaceID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !space.IsOwnedBy(ownerID) {
+		return nil, errors.New("you are not authorized to cancel this booking")
+	}
+
+	if booking.Status == string(constants.BookingStatusCancelled) {
+		return nil, errors.New("booking is already cancelled")
+	}
+	if err := booking.UpdateStatus(constants.BookingStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveWithNotification(ctx, booking, constants.EventBookingCancelled); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// MarkPaid menandai booking sudah dibayar dan mengirim konfirmasi ke user
+func (s *BookingService) MarkPaid(ctx context.Context, bookingID string) (*model.Booking, error) {
+	booking, err := s.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if booking.Status != string(constants.BookingStatusPending) {
+		return nil, errors.New("only pending bookings can be marked as paid")
+	}
+
+	if err := booking.UpdateStatus(constants.BookingStatusPaid); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveWithNotification(ctx, booking, constants.EventBookingPaid); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// saveWithNotification menyimpan perubahan booking, pesan outbox dan delivery webhook dalam satu transaksi
+func (s *BookingService) saveWithNotification(ctx context.Context, booking *model.Booking, event constants.EventType) error {
+	user, err := s.userService.GetUserByID(ctx, booking.UserID.String())
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	var spaceName string
+	if space, err := s.spaceService.GetByID(ctx, booking.SpaceID.String()); err == nil {
+		spaceName = space.Name
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(booking).Error; err != nil {
+			return err
+		}
+		if err := s.notificationService.EnqueueBooking(tx, event, user, bookingMessageData(booking, spaceName)); err != nil {
+			return err
+		}
+		return s.webhookService.Publish(tx, event, booking.ToResponse())
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"booking_id": booking.ID,
+			"event":      event,
+			"error":      err.Error(),
+		}).Error(ctx, "failed to update booking")
 		return err
 	}
 
 	return nil
 }
+
+func bookingMessageData(booking *model.Booking, spaceName string) notificationModel.BookingMessageData {
+	return notificationModel.BookingMessageData{
+		SpaceName:     spaceName,
+		BookingID:     booking.ID.String(),
+		StartDate:     booking.StartDate,
+		EndDate:       booking.EndDate,
+		TotalPrice:    booking.TotalPrice,
+		Currency:      booking.Currency,
+		ChargedAmount: booking.ChargedAmount,
+	}
+}
@@ -9,15 +9,19 @@ import (
 )
 
 type Booking struct {
-	ID         uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
-	UserID     uuid.UUID `json:"user_id" gorm:"type:char(36);not null"`
-	SpaceID    uuid.UUID `json:"space_id" gorm:"type:char(36);not null"`
-	StartDate  time.Time `json:"start_date" gorm:"not null"`
-	EndDate    time.Time `json:"end_date" gorm:"not null"`
-	TotalPrice float64   `json:"total_price" gorm:"not null"`
-	Status     string    `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
-	CreatedAt  time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt  time.Time `json:"updated_at" gorm:"not null"`
+	ID             uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:char(36);not null;index"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:char(36);not null"`
+	SpaceID        uuid.UUID `json:"space_id" gorm:"type:char(36);not null"`
+	StartDate      time.Time `json:"start_date" gorm:"not null"`
+	EndDate        time.Time `json:"end_date" gorm:"not null"`
+	TotalPrice     float64   `json:"total_price" gorm:"not null"`
+	Currency       string    `json:"currency" gorm:"type:char(3);not null;default:'IDR'"`
+	ExchangeRate   float64   `json:"exchange_rate" gorm:"type:decimal(18,6);not null;default:1"`
+	ChargedAmount  float64   `json:"charged_amount" gorm:"type:decimal(12,2);not null;default:0"`
+	Status         string    `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
 }
 
 type CreateBookingInput struct {
@@ -25,46 +29,82 @@ type CreateBookingInput struct {
 	SpaceID   uuid.UUID `json:"space_id"`
 	StartDate time.Time `json:"start_date"`
 	EndDate   time.Time `json:"end_date"`
+	Currency  string    `json:"currency"`
+}
+
+type QuoteInput struct {
+	SpaceID   uuid.UUID `json:"space_id"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Currency  string    `json:"currency"`
+}
+
+// Quote adalah rincian harga sebuah booking; TotalPrice selalu dalam IDR,
+// ChargedAmount dalam Currency dengan ExchangeRate yang dipakai
+type Quote struct {
+	SpaceID       uuid.UUID `json:"space_id"`
+	SpaceName     string    `json:"space_name"`
+	StartDate     string    `json:"start_date"`
+	EndDate       string    `json:"end_date"`
+	Nights        int       `json:"nights"`
+	PricePerNight float64   `json:"price_per_night"`
+	TotalPrice    float64   `json:"total_price"`
+	Currency      string    `json:"currency"`
+	ExchangeRate  float64   `json:"exchange_rate"`
+	ChargedAmount float64   `json:"charged_amount"`
 }
 
 type BookingResponse struct {
-	ID         uuid.UUID `json:"id"`
-	UserID     uuid.UUID `json:"user_id"`
-	SpaceID    uuid.UUID `json:"space_id"`
-	StartDate  string    `json:"start_date"`
-	EndDate    string    `json:"end_date"`
-	TotalPrice float64   `json:"total_price"`
-	Status     string    `json:"status"`
-	CreatedAt  string    `json:"created_at"`
-	UpdatedAt  string    `json:"updated_at"`
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"user_id"`
+	SpaceID       uuid.UUID `json:"space_id"`
+	StartDate     string    `json:"start_date"`
+	EndDate       string    `json:"end_date"`
+	TotalPrice    float64   `json:"total_price"`
+	Currency      string    `json:"currency"`
+	ExchangeRate  float64   `json:"exchange_rate"`
+	ChargedAmount float64   `json:"charged_amount"`
+	Status        string    `json:"status"`
+	CreatedAt     string    `json:"created_at"`
+	UpdatedAt     string    `json:"updated_at"`
 }
 
-func NewBooking(input CreateBookingInput, totalPrice float64) (*Booking, error) {
+func NewBooking(input CreateBookingInput, quote *Quote) (*Booking, error) {
+	if quote == nil {
+		return nil, errors.New("quote is required")
+	}
+
 	now := time.Now()
 	return &Booking{
-		ID:         uuid.New(),
-		UserID:     input.UserID,
-		SpaceID:    input.SpaceID,
-		StartDate:  input.StartDate,
-		EndDate:    input.EndDate,
-		TotalPrice: totalPrice,
-		Status:     "pending",
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		ID:            uuid.New(),
+		UserID:        input.UserID,
+		SpaceID:       input.SpaceID,
+		StartDate:     input.StartDate,
+		EndDate:       input.EndDate,
+		TotalPrice:    quote.TotalPrice,
+		Currency:      quote.Currency,
+		ExchangeRate:  quote.ExchangeRate,
+		ChargedAmount: quote.ChargedAmount,
+		Status:        "pending",
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}, nil
 }
 
 func (b *Booking) ToResponse() BookingResponse {
 	return BookingResponse{
-		ID:         b.ID,
-		UserID:     b.UserID,
-		SpaceID:    b.SpaceID,
-		StartDate:  b.StartDate.Format("2006-01-02 15:04:05"),
-		EndDate:    b.EndDate.Format("2006-01-02 15:04:05"),
-		TotalPrice: b.TotalPrice,
-		Status:     b.Status,
-		CreatedAt:  b.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:  b.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:            b.ID,
+		UserID:        b.UserID,
+		SpaceID:       b.SpaceID,
+		StartDate:     b.StartDate.Format("2006-01-02 15:04:05"),
+		EndDate:       b.EndDate.Format("2006-01-02 15:04:05"),
+		TotalPrice:    b.TotalPrice,
+		Currency:      b.Currency,
+		ExchangeRate:  b.ExchangeRate,
+		ChargedAmount: b.ChargedAmount,
+		Status:        b.Status,
+		CreatedAt:     b.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     b.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
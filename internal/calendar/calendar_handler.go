@@ -0,0 +1,209 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"booking/internal/calendar/model"
+	"booking/internal/space"
+	"booking/pkg/ics"
+	"booking/pkg/response"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarHandler struct {
+	calendarService CalendarServiceInterface
+	appURL          string
+}
+
+func NewCalendarHandler(calendarService CalendarServiceInterface, appURL string) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+		appURL:          strings.TrimRight(appURL, "/"),
+	}
+}
+
+// SpaceFeed melayani GET /spaces/:id/calendar.ics?token=
+func (h *CalendarHandler) SpaceFeed(c echo.Context) error {
+	cal, err := h.calendarService.SpaceCalendar(c.Request().Context(), c.Param("id"), c.QueryParam("token"))
+	if err != nil {
+		return h.feedError(c, err)
+	}
+	return h.writeCalendar(c, cal)
+}
+
+// UserFeed melayani GET /users/:id/calendar.ics?token=
+func (h *CalendarHandler) UserFeed(c echo.Context) error {
+	cal, err := h.calendarService.UserCalendar(c.Request().Context(), c.Param("id"), c.QueryParam("token"))
+	if err != nil {
+		return h.feedError(c, err)
+	}
+	return h.writeCalendar(c, cal)
+}
+
+func (h *CalendarHandler) GetSpaceFeed(c echo.Context) error {
+	feed, err := h.calendarService.GetSpaceFeed(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "failed to get space calendar feed", err)
+	}
+	return response.Success(c, http.StatusOK, "Calendar feed retrieved successfully", h.toResponse(feed))
+}
+
+func (h *CalendarHandler) RotateSpaceFeed(c echo.Context) error {
+	feed, err := h.calendarService.RotateSpaceFeed(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "failed to rotate space calendar feed", err)
+	}
+	return response.Success(c, http.StatusOK, "Calendar feed rotated successfully", h.toResponse(feed))
+}
+
+func (h *CalendarHandler) GetMyFeed(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	feed, err := h.calendarService.GetUserFeed(c.Request().Context(), userID)
+	if err != nil {
+		return response.BadRequest(c, "failed to get calendar feed", err)
+	}
+	return response.Success(c, http.StatusOK, "Calendar feed retrieved successfully", h.toResponse(feed))
+}
+
+func (h *CalendarHandler) RotateMyFeed(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	feed, err := h.calendarService.RotateUserFeed(c.Request().Context(), userID)
+	if err != nil {
+		return response.BadRequest(c, "failed to rotate calendar feed", err)
+	}
+	return response.Success(c, http.StatusOK, "Calendar feed rotated successfully", h.toResponse(feed))
+}
+
+func (h *CalendarHandler) GetExternalCalendars(c echo.Context) error {
+	calendars, err := h.calendarService.GetExternalCalendars(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, space.ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.InternalServerError(c, "failed to get external calendars", err)
+	}
+	return response.Success(c, http.StatusOK, "External calendars retrieved successfully", calendars)
+}
+
+func (h *CalendarHandler) CreateExternalCalendar(c echo.Context) error {
+	var input model.CreateExternalCalendarInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	calendar, err := h.calendarService.CreateExternalCalendar(c.Request().Context(), c.Param("id"), input)
+	if err != nil {
+		return response.BadRequest(c, "failed to register external calendar", err)
+	}
+	return response.Success(c, http.StatusCreated, "External calendar registered successfully", calendar)
+}
+
+// UploadExternalCalendar menerima multipart field "file" (ICS) dan "name"
+func (h *CalendarHandler) UploadExternalCalendar(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "file is required", err)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.BadRequest(c, "failed to open file", err)
+	}
+	defer file.Close()
+
+	name := c.FormValue("name")
+	if name == "" {
+		name = fileHeader.Filename
+	}
+
+	result, err := h.calendarService.UploadExternalCalendar(c.Request().Context(), c.Param("id"), c.Param("calendarId"), name, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, space.ErrSpaceNotFound):
+			return response.NotFound(c, "space not found", err)
+		case errors.Is(err, ErrExternalCalendarNotFound):
+			return response.NotFound(c, "external calendar not found", err)
+		}
+		return response.BadRequest(c, "failed to import calendar file", err)
+	}
+	return response.Success(c, http.StatusOK, "Calendar file imported successfully", result)
+}
+
+func (h *CalendarHandler) SyncExternalCalendar(c echo.Context) error {
+	result, err := h.calendarService.SyncExternalCalendar(c.Request().Context(), c.Param("id"), c.Param("calendarId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, space.ErrSpaceNotFound):
+			return response.NotFound(c, "space not found", err)
+		case errors.Is(err, ErrExternalCalendarNotFound):
+			return response.NotFound(c, "external calendar not found", err)
+		}
+		return response.BadRequest(c, "failed to sync external calendar", err)
+	}
+	return response.Success(c, http.StatusOK, "External calendar synced successfully", result)
+}
+
+func (h *CalendarHandler) DeleteExternalCalendar(c echo.Context) error {
+	if err := h.calendarService.DeleteExternalCalendar(c.Request().Context(), c.Param("id"), c.Param("calendarId")); err != nil {
+		switch {
+		case errors.Is(err, space.ErrSpaceNotFound):
+			return response.NotFound(c, "space not found", err)
+		case errors.Is(err, ErrExternalCalendarNotFound):
+			return response.NotFound(c, "external calendar not found", err)
+		}
+		return response.InternalServerError(c, "failed to delete external calendar", err)
+	}
+	return response.Success(c, http.StatusOK, "External calendar deleted successfully", nil)
+}
+
+func (h *CalendarHandler) GetExternalBlocks(c echo.Context) error {
+	blocks, err := h.calendarService.GetExternalBlocks(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, space.ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.InternalServerError(c, "failed to get external blocks", err)
+	}
+	return response.Success(c, http.StatusOK, "External blocks retrieved successfully", blocks)
+}
+
+func (h *CalendarHandler) writeCalendar(c echo.Context, cal *ics.Calendar) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="calendar.ics"`)
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", cal.Bytes())
+}
+
+func (h *CalendarHandler) feedError(c echo.Context, err error) error {
+	if errors.Is(err, ErrInvalidFeedToken) {
+		return response.Unauthorized(c, "invalid calendar feed token", nil)
+	}
+	return response.InternalServerError(c, "failed to generate calendar", err)
+}
+
+func (h *CalendarHandler) toResponse(feed *model.CalendarFeed) model.FeedResponse {
+	var path string
+	switch feed.OwnerType {
+	case model.FeedOwnerSpace:
+		path = fmt.Sprintf("/spaces/%s/calendar.ics", feed.OwnerID)
+	case model.FeedOwnerUser:
+		path = fmt.Sprintf("/users/%s/calendar.ics", feed.OwnerID)
+	}
+
+	return model.FeedResponse{
+		OwnerType: feed.OwnerType,
+		OwnerID:   feed.OwnerID,
+		URL:       h.appURL + path + "?token=" + feed.Token,
+	}
+}
@@ -0,0 +1,333 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"booking/internal/calendar/model"
+	"booking/pkg/ics"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// maxCalendarSize membatasi ukuran file/URL ICS yang diimpor
+const maxCalendarSize = 5 << 20
+
+var (
+	ErrExternalCalendarNotFound = errors.New("external calendar not found")
+	// ErrCalendarFetchFailed menggantikan error asli saat kalender gagal diambil atau dibaca;
+	// error asli (status, alamat, isi respons) hanya dicatat di log agar URL kalender tidak
+	// bisa dipakai untuk memetakan jaringan internal
+	ErrCalendarFetchFailed = errors.New("failed to fetch external calendar")
+)
+
+func (s *CalendarService) CreateExternalCalendar(ctx context.Context, spaceID string, input model.CreateExternalCalendarInput) (*model.ExternalCalendar, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := model.NewExternalCalendar(space.ID, input.Name, model.ExternalSourceURL, input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(calendar).Error; err != nil {
+		return nil, err
+	}
+
+	// Sinkronisasi awal; kegagalan dicatat di LastError dan dicoba lagi oleh importer
+	if _, err := s.syncFromURL(ctx, calendar); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"external_calendar_id": calendar.ID,
+			"error":                err.Error(),
+		}).Warn("Sinkronisasi awal kalender eksternal gagal")
+	}
+
+	return calendar, nil
+}
+
+// UploadExternalCalendar mengimpor file ICS. Jika calendarID kosong, kalender upload baru dibuat;
+// jika diisi, block kalender tersebut diganti dengan isi file.
+func (s *CalendarService) UploadExternalCalendar(ctx context.Context, spaceID string, calendarID string, name string, r io.Reader) (*model.SyncResult, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := ics.Parse(io.LimitReader(r, maxCalendarSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var calendar *model.ExternalCalendar
+	if calendarID == "" {
+		calendar, err = model.NewExternalCalendar(space.ID, name, model.ExternalSourceUpload, "")
+		if err != nil {
+			return nil, err
+		}
+		if err := s.db.WithContext(ctx).Create(calendar).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		calendar, err = s.getExternalCalendar(ctx, space.ID, calendarID)
+		if err != nil {
+			return nil, err
+		}
+		if calendar.Source != model.ExternalSourceUpload {
+			return nil, errors.New("calendar is synced from a URL and cannot be uploaded")
+		}
+	}
+
+	return s.applyEvents(ctx, calendar, cal.Events)
+}
+
+// GetExternalCalendars mengambil kalender eksternal space. Tabel external_calendars dan
+// external_blocks tidak punya organization_id, sehingga space selalu dicek lebih dulu lewat
+// query yang dibatasi tenant.
+func (s *CalendarService) GetExternalCalendars(ctx context.Context, spaceID string) ([]model.ExternalCalendar, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendars []model.ExternalCalendar
+	if err := s.db.WithContext(ctx).Where("space_id = ?", space.ID).Order("created_at").Find(&calendars).Error; err != nil {
+		return nil, err
+	}
+	return calendars, nil
+}
+
+func (s *CalendarService) GetExternalBlocks(ctx context.Context, spaceID string) ([]model.ExternalBlock, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []model.ExternalBlock
+	if err := s.db.WithContext(ctx).
+		Where("space_id = ? AND end_date >= ?", space.ID, time.Now()).
+		Order("start_date").
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (s *CalendarService) DeleteExternalCalendar(ctx context.Context, spaceID string, calendarID string) error {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	calendar, err := s.getExternalCalendar(ctx, space.ID, calendarID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ExternalBlock{}, "external_calendar_id = ?", calendar.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(calendar).Error
+	})
+}
+
+func (s *CalendarService) SyncExternalCalendar(ctx context.Context, spaceID string, calendarID string) (*model.SyncResult, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.getExternalCalendar(ctx, space.ID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar.Source != model.ExternalSourceURL {
+		return nil, errors.New("only URL calendars can be synced")
+	}
+	return s.syncFromURL(ctx, calendar)
+}
+
+// SyncAll menyinkronkan semua kalender eksternal bersumber URL; dipanggil oleh Importer
+func (s *CalendarService) SyncAll(ctx context.Context) error {
+	// kalender milik space yang sudah dihapus tidak disinkronkan lagi
+	var calendars []model.ExternalCalendar
+	if err := s.db.WithContext(ctx).
+		Joins("JOIN spaces ON spaces.id = external_calendars.space_id AND spaces.deleted_at IS NULL").
+		Where("external_calendars.source = ?", model.ExternalSourceURL).
+		Find(&calendars).Error; err != nil {
+		return err
+	}
+
+	var failed int
+	for i := range calendars {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := s.syncFromURL(ctx, &calendars[i]); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d external calendars failed to sync", failed, len(calendars))
+	}
+	return nil
+}
+
+// HasExternalBlock mengecek apakah rentang tanggal bertabrakan dengan block hasil impor
+func (s *CalendarService) HasExternalBlock(ctx context.Context, spaceID uuid.UUID, startDate, endDate time.Time) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&model.ExternalBlock{}).
+		Where("space_id = ? AND start_date < ? AND end_date > ?", spaceID, endDate, startDate).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// getExternalCalendar mengambil kalender milik space; spaceID harus sudah dicek lewat spaceService
+func (s *CalendarService) getExternalCalendar(ctx context.Context, spaceID uuid.UUID, calendarID string) (*model.ExternalCalendar, error) {
+	var calendar model.ExternalCalendar
+	if err := s.db.WithContext(ctx).First(&calendar, "id = ? AND space_id = ?", calendarID, spaceID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExternalCalendarNotFound
+		}
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (s *CalendarService) syncFromURL(ctx context.Context, calendar *model.ExternalCalendar) (*model.SyncResult, error) {
+	cal, err := fetchCalendar(ctx, s.httpClient, calendar.URL)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"external_calendar_id": calendar.ID,
+			"url":                  calendar.URL,
+			"error":                err.Error(),
+		}).Error("Gagal mengambil kalender eksternal")
+		calendar.LastError = ErrCalendarFetchFailed.Error()
+		s.db.WithContext(ctx).Model(calendar).Update("last_error", calendar.LastError)
+		return nil, ErrCalendarFetchFailed
+	}
+
+	return s.applyEvents(ctx, calendar, cal.Events)
+}
+
+// applyEvents menyimpan VEVENT secara idempoten berdasarkan UID: event baru dibuat,
+// yang berubah diperbarui, dan yang hilang atau CANCELLED dihapus
+func (s *CalendarService) applyEvents(ctx context.Context, calendar *model.ExternalCalendar, events []ics.Event) (*model.SyncResult, error) {
+	result := &model.SyncResult{ExternalCalendarID: calendar.ID}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []model.ExternalBlock
+		if err := tx.Where("external_calendar_id = ?", calendar.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		plan := planSync(calendar, existing, events)
+		for _, block := range plan.create {
+			if err := tx.Create(block).Error; err != nil {
+				return err
+			}
+		}
+		for _, block := range plan.update {
+			if err := tx.Save(block).Error; err != nil {
+				return err
+			}
+		}
+		if len(plan.remove) > 0 {
+			if err := tx.Delete(&model.ExternalBlock{}, "id IN ?", plan.remove).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		calendar.LastSyncedAt = &now
+		calendar.LastError = ""
+		if err := tx.Model(calendar).Updates(map[string]interface{}{
+			"last_synced_at": calendar.LastSyncedAt,
+			"last_error":     "",
+		}).Error; err != nil {
+			return err
+		}
+
+		result.Created = len(plan.create)
+		result.Updated = len(plan.update)
+		result.Removed = len(plan.remove)
+		return nil
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"external_calendar_id": calendar.ID,
+			"error":                err.Error(),
+		}).Error("Gagal menyimpan block kalender eksternal")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type syncPlan struct {
+	create []*model.ExternalBlock
+	update []*model.ExternalBlock
+	remove []uuid.UUID
+}
+
+func planSync(calendar *model.ExternalCalendar, existing []model.ExternalBlock, events []ics.Event) syncPlan {
+	byUID := make(map[string]*model.ExternalBlock, len(existing))
+	for i := range existing {
+		byUID[existing[i].UID] = &existing[i]
+	}
+
+	var plan syncPlan
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		// UID ganda (misalnya override event berulang) hanya diambil yang pertama
+		if event.Status == ics.StatusCancelled || seen[event.UID] {
+			continue
+		}
+		seen[event.UID] = true
+
+		if block, ok := byUID[event.UID]; ok {
+			if block.Apply(event) {
+				plan.update = append(plan.update, block)
+			}
+			continue
+		}
+		plan.create = append(plan.create, model.NewExternalBlock(calendar, event))
+	}
+
+	for _, block := range existing {
+		if !seen[block.UID] {
+			plan.remove = append(plan.remove, block.ID)
+		}
+	}
+	return plan
+}
+
+func fetchCalendar(ctx context.Context, client *http.Client, url string) (*ics.Calendar, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching calendar", resp.StatusCode)
+	}
+
+	return ics.Parse(io.LimitReader(resp.Body, maxCalendarSize))
+}
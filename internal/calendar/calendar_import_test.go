@@ -0,0 +1,136 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"booking/internal/calendar/model"
+	"booking/pkg/ics"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const channelICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Channel//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:res-1@channel\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20261226\r\n" +
+	"SUMMARY:Reserved\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:res-2@channel\r\n" +
+	"DTSTART;VALUE=DATE:20261228\r\n" +
+	"DTEND;VALUE=DATE:20261230\r\n" +
+	"SUMMARY:Reserved\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+type CalendarImportTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	calendar *model.ExternalCalendar
+}
+
+func TestCalendarImportSuite(t *testing.T) {
+	suite.Run(t, new(CalendarImportTestSuite))
+}
+
+func (s *CalendarImportTestSuite) SetupTest() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/channel.ics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(channelICS))
+	})
+	mux.HandleFunc("/broken.ics", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+	s.server = httptest.NewServer(mux)
+
+	calendar, err := model.NewExternalCalendar(uuid.New(), "Channel", model.ExternalSourceURL, "https://channel.example.com/channel.ics")
+	s.Require().NoError(err)
+	// server test berjalan di loopback yang ditolak NewExternalCalendar
+	calendar.URL = s.server.URL + "/channel.ics"
+	s.calendar = calendar
+}
+
+func (s *CalendarImportTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *CalendarImportTestSuite) TestFetchCalendar() {
+	cal, err := fetchCalendar(context.Background(), s.server.Client(), s.calendar.URL)
+	s.Require().NoError(err)
+	s.Len(cal.Events, 2)
+
+	_, err = fetchCalendar(context.Background(), s.server.Client(), s.server.URL+"/broken.ics")
+	s.Error(err)
+}
+
+func (s *CalendarImportTestSuite) TestExternalCalendarRejectsInternalAddress() {
+	for _, url := range []string{"http://127.0.0.1/cal.ics", "http://169.254.169.254/latest", "https://[fd00::1]/cal.ics"} {
+		_, err := model.NewExternalCalendar(uuid.New(), "Channel", model.ExternalSourceURL, url)
+		s.Error(err, url)
+	}
+}
+
+func (s *CalendarImportTestSuite) TestPlanSyncInitialImport() {
+	cal, err := fetchCalendar(context.Background(), s.server.Client(), s.calendar.URL)
+	s.Require().NoError(err)
+
+	plan := planSync(s.calendar, nil, cal.Events)
+	s.Len(plan.create, 2)
+	s.Empty(plan.update)
+	s.Empty(plan.remove)
+
+	block := plan.create[0]
+	s.Equal(s.calendar.SpaceID, block.SpaceID)
+	s.Equal("res-1@channel", block.UID)
+	s.Equal(time.Date(2026, 12, 24, 14, 0, 0, 0, time.Local), block.StartDate)
+	s.Equal(time.Date(2026, 12, 26, 12, 0, 0, 0, time.Local), block.EndDate)
+}
+
+func (s *CalendarImportTestSuite) TestPlanSyncIsIdempotent() {
+	cal, err := fetchCalendar(context.Background(), s.server.Client(), s.calendar.URL)
+	s.Require().NoError(err)
+
+	var existing []model.ExternalBlock
+	for _, block := range planSync(s.calendar, nil, cal.Events).create {
+		existing = append(existing, *block)
+	}
+
+	plan := planSync(s.calendar, existing, cal.Events)
+	s.Empty(plan.create)
+	s.Empty(plan.update)
+	s.Empty(plan.remove)
+}
+
+func (s *CalendarImportTestSuite) TestPlanSyncUpdatesAndRemovals() {
+	cal, err := fetchCalendar(context.Background(), s.server.Client(), s.calendar.URL)
+	s.Require().NoError(err)
+
+	var existing []model.ExternalBlock
+	for _, block := range planSync(s.calendar, nil, cal.Events).create {
+		existing = append(existing, *block)
+	}
+
+	// res-1 digeser sehari, res-2 dibatalkan, res-3 baru
+	events := []ics.Event{
+		{UID: "res-1@channel", AllDay: true, Start: time.Date(2026, 12, 25, 0, 0, 0, 0, time.Local), End: time.Date(2026, 12, 27, 0, 0, 0, 0, time.Local)},
+		{UID: "res-2@channel", AllDay: true, Status: ics.StatusCancelled, Start: time.Date(2026, 12, 28, 0, 0, 0, 0, time.Local), End: time.Date(2026, 12, 30, 0, 0, 0, 0, time.Local)},
+		{UID: "res-3@channel", AllDay: true, Start: time.Date(2027, 1, 2, 0, 0, 0, 0, time.Local), End: time.Date(2027, 1, 4, 0, 0, 0, 0, time.Local)},
+	}
+
+	plan := planSync(s.calendar, existing, events)
+	s.Require().Len(plan.create, 1)
+	s.Equal("res-3@channel", plan.create[0].UID)
+	s.Require().Len(plan.update, 1)
+	s.Equal(existing[0].ID, plan.update[0].ID)
+	s.Equal(time.Date(2026, 12, 25, 14, 0, 0, 0, time.Local), plan.update[0].StartDate)
+	s.Equal([]uuid.UUID{existing[1].ID}, plan.remove)
+}
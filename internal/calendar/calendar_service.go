@@ -0,0 +1,295 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	bookingModel "booking/internal/booking/model"
+	"booking/internal/calendar/model"
+	"booking/internal/space"
+	spaceModel "booking/internal/space/model"
+	"booking/pkg/ics"
+	"booking/pkg/logger"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// FetchTimeout adalah batas waktu mengambil satu kalender eksternal
+	FetchTimeout = 30 * time.Second
+
+	prodID = "-//go-booking//Booking Calendar//ID"
+	// pastWindow menentukan seberapa jauh ke belakang event di feed space masih ditampilkan
+	pastWindow = 30 * 24 * time.Hour
+)
+
+var ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+
+// CalendarServiceInterface mendefinisikan kontrak untuk CalendarService
+type CalendarServiceInterface interface {
+	GetSpaceFeed(ctx context.Context, spaceID string) (*model.CalendarFeed, error)
+	RotateSpaceFeed(ctx context.Context, spaceID string) (*model.CalendarFeed, error)
+	GetUserFeed(ctx context.Context, userID string) (*model.CalendarFeed, error)
+	RotateUserFeed(ctx context.Context, userID string) (*model.CalendarFeed, error)
+	SpaceCalendar(ctx context.Context, spaceID string, token string) (*ics.Calendar, error)
+	UserCalendar(ctx context.Context, userID string, token string) (*ics.Calendar, error)
+
+	CreateExternalCalendar(ctx context.Context, spaceID string, input model.CreateExternalCalendarInput) (*model.ExternalCalendar, error)
+	UploadExternalCalendar(ctx context.Context, spaceID string, calendarID string, name string, r io.Reader) (*model.SyncResult, error)
+	GetExternalCalendars(ctx context.Context, spaceID string) ([]model.ExternalCalendar, error)
+	GetExternalBlocks(ctx context.Context, spaceID string) ([]model.ExternalBlock, error)
+	DeleteExternalCalendar(ctx context.Context, spaceID string, calendarID string) error
+	SyncExternalCalendar(ctx context.Context, spaceID string, calendarID string) (*model.SyncResult, error)
+	SyncAll(ctx context.Context) error
+	HasExternalBlock(ctx context.Context, spaceID uuid.UUID, startDate, endDate time.Time) (bool, error)
+}
+
+type CalendarService struct {
+	db           *gorm.DB
+	logger       logger.Logger
+	spaceService space.SpaceServiceInterface
+	httpClient   *http.Client
+}
+
+// NewCalendarService menerima httpClient untuk mengambil kalender eksternal; di produksi
+// gunakan httpclient.NewPublic agar URL tidak bisa diarahkan ke alamat internal
+func NewCalendarService(db *gorm.DB, logger logger.Logger, spaceService space.SpaceServiceInterface, httpClient *http.Client) *CalendarService {
+	return &CalendarService{
+		db:           db,
+		logger:       logger,
+		spaceService: spaceService,
+		httpClient:   httpClient,
+	}
+}
+
+func (s *CalendarService) GetSpaceFeed(ctx context.Context, spaceID string) (*model.CalendarFeed, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.getOrCreateFeed(ctx, model.FeedOwnerSpace, space.ID)
+}
+
+func (s *CalendarService) RotateSpaceFeed(ctx context.Context, spaceID string) (*model.CalendarFeed, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.rotateFeed(ctx, model.FeedOwnerSpace, space.ID)
+}
+
+func (s *CalendarService) GetUserFeed(ctx context.Context, userID string) (*model.CalendarFeed, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return s.getOrCreateFeed(ctx, model.FeedOwnerUser, id)
+}
+
+func (s *CalendarService) RotateUserFeed(ctx context.Context, userID string) (*model.CalendarFeed, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return s.rotateFeed(ctx, model.FeedOwnerUser, id)
+}
+
+// SpaceCalendar berisi booking yang sudah dibayar dan blackout milik space
+func (s *CalendarService) SpaceCalendar(ctx context.Context, spaceID string, token string) (*ics.Calendar, error) {
+	id, err := uuid.Parse(spaceID)
+	if err != nil {
+		return nil, ErrInvalidFeedToken
+	}
+	if err := s.verifyToken(ctx, model.FeedOwnerSpace, id, token); err != nil {
+		return nil, err
+	}
+
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-pastWindow)
+
+	var bookings []bookingModel.Booking
+	if err := s.db.WithContext(ctx).
+		Where("space_id = ? AND status = ? AND end_date >= ?", id, constants.BookingStatusPaid, since).
+		Order("start_date").
+		Find(&bookings).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"space_id": id,
+			"error":    err.Error(),
+		}).Error("Gagal mengambil booking untuk feed kalender")
+		return nil, err
+	}
+
+	var blackouts []spaceModel.Blackout
+	if err := s.db.WithContext(ctx).
+		Where("space_id = ? AND end_date >= ?", id, since).
+		Order("start_date").
+		Find(&blackouts).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"space_id": id,
+			"error":    err.Error(),
+		}).Error("Gagal mengambil blackout untuk feed kalender")
+		return nil, err
+	}
+
+	cal := &ics.Calendar{
+		ProdID: prodID,
+		Name:   space.Name,
+	}
+	for _, b := range bookings {
+		cal.Events = append(cal.Events, ics.Event{
+			UID:          fmt.Sprintf("booking-%s@go-booking", b.ID),
+			Summary:      "Reserved",
+			Description:  "Booking " + b.ID.String(),
+			Status:       ics.StatusConfirmed,
+			Start:        b.StartDate,
+			End:          b.EndDate,
+			LastModified: b.UpdatedAt,
+		})
+	}
+	for _, bo := range blackouts {
+		summary := "Blocked"
+		if bo.Reason != "" {
+			summary += ": " + bo.Reason
+		}
+		cal.Events = append(cal.Events, ics.Event{
+			UID:          fmt.Sprintf("blackout-%s@go-booking", bo.ID),
+			Summary:      summary,
+			Status:       ics.StatusConfirmed,
+			Start:        bo.StartDate,
+			End:          bo.EndDate,
+			AllDay:       true,
+			LastModified: bo.UpdatedAt,
+		})
+	}
+
+	return cal, nil
+}
+
+// UserCalendar berisi booking user yang belum lewat dan tidak dibatalkan
+func (s *CalendarService) UserCalendar(ctx context.Context, userID string, token string) (*ics.Calendar, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvalidFeedToken
+	}
+	if err := s.verifyToken(ctx, model.FeedOwnerUser, id, token); err != nil {
+		return nil, err
+	}
+
+	var bookings []bookingModel.Booking
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ? AND status != ? AND end_date >= ?", id, constants.BookingStatusCancelled, time.Now()).
+		Order("start_date").
+		Find(&bookings).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": id,
+			"error":   err.Error(),
+		}).Error("Gagal mengambil booking untuk feed kalender user")
+		return nil, err
+	}
+
+	spaceIDs := make([]uuid.UUID, 0, len(bookings))
+	for _, b := range bookings {
+		spaceIDs = append(spaceIDs, b.SpaceID)
+	}
+	spaceNames := make(map[uuid.UUID]string)
+	if len(spaceIDs) > 0 {
+		var spaces []spaceModel.Space
+		if err := s.db.WithContext(ctx).Where("id IN ?", spaceIDs).Find(&spaces).Error; err != nil {
+			return nil, err
+		}
+		for _, sp := range spaces {
+			spaceNames[sp.ID] = sp.Name
+		}
+	}
+
+	cal := &ics.Calendar{
+		ProdID: prodID,
+		Name:   "My stays",
+	}
+	for _, b := range bookings {
+		status := ics.StatusTentative
+		if b.Status == string(constants.BookingStatusPaid) {
+			status = ics.StatusConfirmed
+		}
+		cal.Events = append(cal.Events, ics.Event{
+			UID:          fmt.Sprintf("booking-%s@go-booking", b.ID),
+			Summary:      "Stay at " + spaceNames[b.SpaceID],
+			Description:  "Booking " + b.ID.String() + " (" + b.Status + ")",
+			Location:     spaceNames[b.SpaceID],
+			Status:       status,
+			Start:        b.StartDate,
+			End:          b.EndDate,
+			LastModified: b.UpdatedAt,
+		})
+	}
+
+	return cal, nil
+}
+
+func (s *CalendarService) verifyToken(ctx context.Context, ownerType model.FeedOwner, ownerID uuid.UUID, token string) error {
+	var feed model.CalendarFeed
+	if err := s.db.WithContext(ctx).
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidFeedToken
+		}
+		return err
+	}
+	if !feed.Matches(token) {
+		return ErrInvalidFeedToken
+	}
+	return nil
+}
+
+func (s *CalendarService) getOrCreateFeed(ctx context.Context, ownerType model.FeedOwner, ownerID uuid.UUID) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	err := s.db.WithContext(ctx).
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		First(&feed).Error
+	if err == nil {
+		return &feed, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	newFeed, err := model.NewCalendarFeed(ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(newFeed).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"owner_type": ownerType,
+			"owner_id":   ownerID,
+			"error":      err.Error(),
+		}).Error("Gagal membuat feed kalender")
+		return nil, err
+	}
+	return newFeed, nil
+}
+
+func (s *CalendarService) rotateFeed(ctx context.Context, ownerType model.FeedOwner, ownerID uuid.UUID) (*model.CalendarFeed, error) {
+	feed, err := s.getOrCreateFeed(ctx, ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := feed.Rotate(); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(feed).Error; err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
@@ -0,0 +1,53 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"booking/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Importer menjalankan sinkronisasi kalender eksternal secara berkala
+type Importer struct {
+	calendarService CalendarServiceInterface
+	logger          logger.Logger
+	interval        time.Duration
+}
+
+func NewImporter(calendarService CalendarServiceInterface, logger logger.Logger, interval time.Duration) *Importer {
+	return &Importer{
+		calendarService: calendarService,
+		logger:          logger,
+		interval:        interval,
+	}
+}
+
+// Start berjalan sampai ctx dibatalkan; interval <= 0 menonaktifkan importer
+func (i *Importer) Start(ctx context.Context) {
+	if i.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	i.run(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.run(ctx)
+		}
+	}
+}
+
+func (i *Importer) run(ctx context.Context) {
+	if err := i.calendarService.SyncAll(ctx); err != nil {
+		i.logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Warn("Sinkronisasi kalender eksternal tidak sepenuhnya berhasil")
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedOwner string
+
+const (
+	FeedOwnerSpace FeedOwner = "space"
+	FeedOwnerUser  FeedOwner = "user"
+
+	feedTokenBytes = 32
+)
+
+// CalendarFeed menyimpan token rahasia untuk URL feed ICS milik space atau user
+type CalendarFeed struct {
+	ID        uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	OwnerType FeedOwner `json:"owner_type" gorm:"type:varchar(10);not null;uniqueIndex:idx_calendar_feed_owner"`
+	OwnerID   uuid.UUID `json:"owner_id" gorm:"type:char(36);not null;uniqueIndex:idx_calendar_feed_owner"`
+	Token     string    `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FeedResponse berisi URL feed yang bisa didaftarkan di Google/Outlook
+type FeedResponse struct {
+	OwnerType FeedOwner `json:"owner_type"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+	URL       string    `json:"url"`
+}
+
+func NewCalendarFeed(ownerType FeedOwner, ownerID uuid.UUID) (*CalendarFeed, error) {
+	if ownerType != FeedOwnerSpace && ownerType != FeedOwnerUser {
+		return nil, errors.New("invalid feed owner type")
+	}
+	if ownerID == uuid.Nil {
+		return nil, errors.New("feed owner id is required")
+	}
+
+	feed := &CalendarFeed{
+		ID:        uuid.New(),
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+	}
+	if err := feed.Rotate(); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Rotate mengganti token sehingga URL feed lama tidak berlaku lagi
+func (f *CalendarFeed) Rotate() error {
+	buf := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	f.Token = hex.EncodeToString(buf)
+	return nil
+}
+
+// Matches membandingkan token dengan constant-time compare
+func (f *CalendarFeed) Matches(token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(f.Token), []byte(token)) == 1
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"booking/pkg/httpclient"
+	"booking/pkg/ics"
+
+	"github.com/google/uuid"
+)
+
+type ExternalSource string
+
+const (
+	ExternalSourceURL    ExternalSource = "url"
+	ExternalSourceUpload ExternalSource = "upload"
+)
+
+// ExternalCalendar adalah kalender dari platform lain (Airbnb, Booking.com, dll) untuk satu space
+type ExternalCalendar struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:char(36);primary_key"`
+	SpaceID      uuid.UUID      `json:"space_id" gorm:"type:char(36);not null;index"`
+	Name         string         `json:"name" gorm:"size:100;not null"`
+	Source       ExternalSource `json:"source" gorm:"type:varchar(10);not null"`
+	URL          string         `json:"url,omitempty" gorm:"size:2048"`
+	LastSyncedAt *time.Time     `json:"last_synced_at"`
+	LastError    string         `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// ExternalBlock adalah satu VEVENT hasil impor; dianggap tidak tersedia saat cek overlap booking
+type ExternalBlock struct {
+	ID                 uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	ExternalCalendarID uuid.UUID `json:"external_calendar_id" gorm:"type:char(36);not null;uniqueIndex:idx_external_block_uid"`
+	SpaceID            uuid.UUID `json:"space_id" gorm:"type:char(36);not null;index"`
+	UID                string    `json:"uid" gorm:"size:255;not null;uniqueIndex:idx_external_block_uid"`
+	Summary            string    `json:"summary" gorm:"size:255"`
+	StartDate          time.Time `json:"start_date" gorm:"not null"`
+	EndDate            time.Time `json:"end_date" gorm:"not null"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// DTO: Register external calendar URL input
+type CreateExternalCalendarInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+	URL  string `json:"url" validate:"required,url"`
+}
+
+// SyncResult merangkum hasil satu kali sinkronisasi
+type SyncResult struct {
+	ExternalCalendarID uuid.UUID `json:"external_calendar_id"`
+	Created            int       `json:"created"`
+	Updated            int       `json:"updated"`
+	Removed            int       `json:"removed"`
+}
+
+func NewExternalCalendar(spaceID uuid.UUID, name string, source ExternalSource, rawURL string) (*ExternalCalendar, error) {
+	if name == "" {
+		return nil, errors.New("calendar name is required")
+	}
+
+	switch source {
+	case ExternalSourceURL:
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			return nil, errors.New("calendar url must be an http(s) URL")
+		}
+		// alamat IP langsung ditolak lebih awal; hostname dicek lagi saat kalender diambil
+		if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !httpclient.IsPublic(addr) {
+			return nil, errors.New("calendar url must not point to an internal address")
+		}
+	case ExternalSourceUpload:
+		rawURL = ""
+	default:
+		return nil, errors.New("invalid calendar source")
+	}
+
+	return &ExternalCalendar{
+		ID:      uuid.New(),
+		SpaceID: spaceID,
+		Name:    name,
+		Source:  source,
+		URL:     rawURL,
+	}, nil
+}
+
+// NewExternalBlock mengubah VEVENT menjadi block. Event sepanjang hari memakai
+// jam check-in (14:00) dan check-out (12:00) agar konsisten dengan booking.
+func NewExternalBlock(calendar *ExternalCalendar, event ics.Event) *ExternalBlock {
+	block := &ExternalBlock{
+		ID:                 uuid.New(),
+		ExternalCalendarID: calendar.ID,
+		SpaceID:            calendar.SpaceID,
+	}
+	block.Apply(event)
+	return block
+}
+
+// Apply memperbarui block dari VEVENT dan mengembalikan true jika ada perubahan
+func (b *ExternalBlock) Apply(event ics.Event) bool {
+	start, end := event.Start, event.End
+	if event.AllDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 14, 0, 0, 0, time.Local)
+		end = time.Date(end.Year(), end.Month(), end.Day(), 12, 0, 0, 0, time.Local)
+	}
+
+	summary := event.Summary
+	if runes := []rune(summary); len(runes) > 255 {
+		summary = string(runes[:255])
+	}
+
+	changed := b.UID != event.UID || b.Summary != summary || !b.StartDate.Equal(start) || !b.EndDate.Equal(end)
+	b.UID = event.UID
+	b.Summary = summary
+	b.StartDate = start
+	b.EndDate = end
+	return changed
+}
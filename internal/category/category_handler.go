@@ -1,12 +1,15 @@
 package category
 
 import (
+	"errors"
 	"net/http"
 
 	categoryModel "booking/internal/category/model"
+	spaceModel "booking/internal/space/model"
 	userModel "booking/internal/user/model"
 	"booking/pkg/response"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -21,7 +24,7 @@ func NewCategoryHandler(categoryService CategoryServiceInterface) *CategoryHandl
 }
 
 func (h *CategoryHandler) Create(c echo.Context) error {
-	user, exists := c.Get("user").(*userModel.User)
+	user, exists := c.Get("principal").(*userModel.Principal)
 	if !exists {
 		return response.Unauthorized(c, "unauthorized", nil)
 	}
@@ -35,7 +38,7 @@ func (h *CategoryHandler) Create(c echo.Context) error {
 		return response.BadRequest(c, "validation error", err)
 	}
 
-	category, err := h.categoryService.Create(input, user)
+	category, err := h.categoryService.Create(c.Request().Context(), input, user.UserID)
 	if err != nil {
 		return response.BadRequest(c, "failed to create category", err)
 	}
@@ -44,7 +47,7 @@ func (h *CategoryHandler) Create(c echo.Context) error {
 }
 
 func (h *CategoryHandler) GetAll(c echo.Context) error {
-	categories, err := h.categoryService.GetAll()
+	categories, err := h.categoryService.GetAll(c.Request().Context())
 	if err != nil {
 		return response.Error(c, http.StatusInternalServerError, "failed to get categories", err)
 	}
@@ -52,10 +55,46 @@ func (h *CategoryHandler) GetAll(c echo.Context) error {
 	return response.Success(c, http.StatusOK, "Categories retrieved successfully", categories)
 }
 
+// GetTree menampilkan semua kategori dalam bentuk pohon (dipakai juga katalog publik)
+func (h *CategoryHandler) GetTree(c echo.Context) error {
+	tree, err := h.categoryService.GetTree(c.Request().Context())
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, "failed to get categories", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Categories retrieved successfully", tree)
+}
+
+// GetBySlug menampilkan kategori beserta sub-kategorinya berdasarkan slug
+func (h *CategoryHandler) GetBySlug(c echo.Context) error {
+	category, err := h.categoryService.GetBySlug(c.Request().Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return response.NotFound(c, "category not found", err)
+		}
+		return response.Error(c, http.StatusInternalServerError, "failed to get category", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Category retrieved successfully", category)
+}
+
+// GetSpaces menampilkan space pada kategori dan semua sub-kategorinya
+func (h *CategoryHandler) GetSpaces(c echo.Context) error {
+	spaces, err := h.categoryService.GetSpaces(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return response.NotFound(c, "category not found", err)
+		}
+		return response.Error(c, http.StatusInternalServerError, "failed to get category spaces", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Spaces retrieved successfully", spaces)
+}
+
 func (h *CategoryHandler) GetByID(c echo.Context) error {
 	id := c.Param("id")
 
-	category, err := h.categoryService.GetByID(id)
+	category, err := h.categoryService.GetByID(c.Request().Context(), id)
 	if err != nil {
 		return response.BadRequest(c, "category not found", err)
 	}
@@ -64,11 +103,6 @@ func (h *CategoryHandler) GetByID(c echo.Context) error {
 }
 
 func (h *CategoryHandler) Update(c echo.Context) error {
-	user, exists := c.Get("user").(*userModel.User)
-	if !exists {
-		return response.Unauthorized(c, "unauthorized", nil)
-	}
-
 	id := c.Param("id")
 
 	var input categoryModel.CreateCategoryInput
@@ -76,7 +110,7 @@ func (h *CategoryHandler) Update(c echo.Context) error {
 		return response.BadRequest(c, "invalid request payload", err)
 	}
 
-	category, err := h.categoryService.Update(id, input, user)
+	category, err := h.categoryService.Update(c.Request().Context(), id, input)
 	if err != nil {
 		return response.BadRequest(c, "failed to update category", err)
 	}
@@ -84,17 +118,54 @@ func (h *CategoryHandler) Update(c echo.Context) error {
 	return response.Success(c, http.StatusOK, "Category updated successfully", category)
 }
 
+// Delete menghapus kategori; query reassign_to memindahkan space yang masih memakai
+// kategori ini ke kategori lain sebelum dihapus
 func (h *CategoryHandler) Delete(c echo.Context) error {
-	user, exists := c.Get("user").(*userModel.User)
-	if !exists {
-		return response.Unauthorized(c, "unauthorized", nil)
-	}
-
 	id := c.Param("id")
 
-	if err := h.categoryService.Delete(id, user); err != nil {
+	var reassignTo *uuid.UUID
+	if raw := c.QueryParam("reassign_to"); raw != "" {
+		target, err := uuid.Parse(raw)
+		if err != nil {
+			return response.BadRequest(c, "invalid reassign_to category ID", err)
+		}
+		reassignTo = &target
+	}
+
+	if err := h.categoryService.Delete(c.Request().Context(), id, reassignTo); err != nil {
+		var dependents *spaceModel.DependentSpacesError
+		switch {
+		case errors.As(err, &dependents):
+			return response.Conflict(c, err.Error(), err, dependents.Spaces)
+		case errors.Is(err, ErrCategoryNotFound):
+			return response.NotFound(c, "category not found", err)
+		case errors.Is(err, ErrCategoryHasChildren):
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		}
 		return response.BadRequest(c, "failed to delete category", err)
 	}
 
 	return response.Success(c, http.StatusOK, "Category deleted successfully", nil)
 }
+
+// GetDeleted menampilkan kategori yang sudah dihapus dan masih bisa di-restore
+func (h *CategoryHandler) GetDeleted(c echo.Context) error {
+	categories, err := h.categoryService.GetDeleted(c.Request().Context())
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, "failed to get deleted categories", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Deleted categories retrieved successfully", categories)
+}
+
+func (h *CategoryHandler) Restore(c echo.Context) error {
+	category, err := h.categoryService.Restore(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return response.NotFound(c, "deleted category not found", err)
+		}
+		return response.BadRequest(c, "failed to restore category", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Category restored successfully", category)
+}
@@ -1,22 +1,37 @@
 package category
 
 import (
+	"context"
 	"errors"
 	"strings"
 
 	categoryModel "booking/internal/category/model"
-	userModel "booking/internal/user/model"
+	spaceModel "booking/internal/space/model"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryHasChildren   = errors.New("category still has sub-categories")
+	ErrInvalidReassignTarget = errors.New("spaces must be reassigned to another existing category")
 )
 
 // CategoryServiceInterface mendefinisikan kontrak untuk CategoryService
 type CategoryServiceInterface interface {
-	Create(input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error)
-	GetAll() ([]categoryModel.Category, error)
-	GetByID(id string) (*categoryModel.Category, error)
-	Update(id string, input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error)
-	Delete(id string, user *userModel.User) error
+	Create(ctx context.Context, input categoryModel.CreateCategoryInput, userID uuid.UUID) (*categoryModel.Category, error)
+	GetAll(ctx context.Context) ([]categoryModel.Category, error)
+	GetTree(ctx context.Context) ([]*categoryModel.CategoryNode, error)
+	GetByID(ctx context.Context, id string) (*categoryModel.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*categoryModel.CategoryNode, error)
+	SubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	GetSpaces(ctx context.Context, id string) ([]spaceModel.Space, error)
+	Update(ctx context.Context, id string, input categoryModel.CreateCategoryInput) (*categoryModel.Category, error)
+	Delete(ctx context.Context, id string, reassignTo *uuid.UUID) error
+	GetDeleted(ctx context.Context) ([]categoryModel.Category, error)
+	Restore(ctx context.Context, id string) (*categoryModel.Category, error)
 }
 
 type CategoryService struct {
@@ -29,7 +44,7 @@ func NewCategoryService(db *gorm.DB) *CategoryService {
 	}
 }
 
-func (s *CategoryService) Create(input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error) {
+func (s *CategoryService) Create(ctx context.Context, input categoryModel.CreateCategoryInput, userID uuid.UUID) (*categoryModel.Category, error) {
 	// Membersihkan spasi di awal dan akhir
 	input.Name = strings.TrimSpace(input.Name)
 	input.Description = strings.TrimSpace(input.Description)
@@ -38,59 +53,289 @@ func (s *CategoryService) Create(input categoryModel.CreateCategoryInput, user *
 	input.Name = strings.Join(strings.Fields(input.Name), " ")
 	input.Description = strings.Join(strings.Fields(input.Description), " ")
 
-	category, err := categoryModel.NewCategory(input, user.ID)
+	category, err := categoryModel.NewCategory(input, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.db.Create(category).Error; err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tree, err := s.lockTree(tx)
+		if err != nil {
+			return err
+		}
+
+		if category.Depth, err = tree.DepthUnder(uuid.Nil, category.ParentID); err != nil {
+			return err
+		}
+		if category.Slug, err = s.uniqueSlug(tx, category.Name, category.ID); err != nil {
+			return err
+		}
+		return tx.Create(category).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return category, nil
 }
 
-func (s *CategoryService) GetAll() ([]categoryModel.Category, error) {
+func (s *CategoryService) GetAll(ctx context.Context) ([]categoryModel.Category, error) {
 	var categories []categoryModel.Category
-	if err := s.db.Find(&categories).Error; err != nil {
+	if err := s.db.WithContext(ctx).Order("depth, name").Find(&categories).Error; err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
-func (s *CategoryService) GetByID(id string) (*categoryModel.Category, error) {
+// GetTree mengambil semua kategori dalam bentuk pohon
+func (s *CategoryService) GetTree(ctx context.Context) ([]*categoryModel.CategoryNode, error) {
+	tree, err := s.loadTree(s.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return tree.Nodes(), nil
+}
+
+func (s *CategoryService) GetByID(ctx context.Context, id string) (*categoryModel.Category, error) {
 	var category categoryModel.Category
-	if err := s.db.First(&category, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&category, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
 		return nil, err
 	}
 	return &category, nil
 }
 
-func (s *CategoryService) Update(id string, input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error) {
-	if !user.IsAdmin() {
-		return nil, errors.New("only admin can update category")
+// GetBySlug mengambil kategori berdasarkan slug beserta seluruh turunannya
+func (s *CategoryService) GetBySlug(ctx context.Context, slug string) (*categoryModel.CategoryNode, error) {
+	var category categoryModel.Category
+	if err := s.db.WithContext(ctx).Select("id").First(&category, "slug = ?", strings.ToLower(slug)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
 	}
 
-	category, err := s.GetByID(id)
+	tree, err := s.loadTree(s.db.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
+	node := tree.Node(category.ID)
+	if node == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return node, nil
+}
 
+// SubtreeIDs mengembalikan id kategori beserta semua turunannya
+func (s *CategoryService) SubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	tree, err := s.loadTree(s.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	ids := tree.SubtreeIDs(id)
+	if len(ids) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+	return ids, nil
+}
+
+// GetSpaces mengambil space pada kategori dan semua sub-kategorinya
+func (s *CategoryService) GetSpaces(ctx context.Context, id string) ([]spaceModel.Space, error) {
+	categoryID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	ids, err := s.SubtreeIDs(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	spaces := []spaceModel.Space{}
+	if err := s.db.WithContext(ctx).Where("category_id IN ?", ids).Order("name").Find(&spaces).Error; err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+// Update mengubah kategori; slug dibuat ulang jika nama berubah, dan depth seluruh
+// turunan ikut disesuaikan jika kategori dipindah ke parent lain
+func (s *CategoryService) Update(ctx context.Context, id string, input categoryModel.CreateCategoryInput) (*categoryModel.Category, error) {
+	category, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := category.Name
 	if err := category.Update(input); err != nil {
 		return nil, err
 	}
 
-	if err := s.db.Save(category).Error; err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tree, err := s.lockTree(tx)
+		if err != nil {
+			return err
+		}
+
+		changed, err := tree.Move(category.ID, input.ParentID)
+		if err != nil {
+			return err
+		}
+		category.ParentID = input.ParentID
+		if moved := tree.Get(category.ID); moved != nil {
+			category.Depth = moved.Depth
+		}
+
+		if category.Name != oldName {
+			if category.Slug, err = s.uniqueSlug(tx, category.Name, category.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		for _, c := range changed {
+			if c.ID == category.ID {
+				continue
+			}
+			if err := tx.Model(c).UpdateColumn("depth", c.Depth).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return category, nil
 }
 
-func (s *CategoryService) Delete(id string, user *userModel.User) error {
-	if !user.IsAdmin() {
-		return errors.New("only admin can delete category")
+// Delete melakukan soft delete kategori. Space yang masih memakai kategori ini dipindah
+// ke reassignTo dalam transaksi yang sama; tanpa reassignTo penghapusan ditolak dengan
+// DependentSpacesError yang berisi daftar space tersebut
+func (s *CategoryService) Delete(ctx context.Context, id string, reassignTo *uuid.UUID) error {
+	category, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tree, err := s.lockTree(tx)
+		if err != nil {
+			return err
+		}
+		node := tree.Node(category.ID)
+		if node == nil {
+			return ErrCategoryNotFound
+		}
+		if len(node.Children) > 0 {
+			return ErrCategoryHasChildren
+		}
+
+		var dependents []spaceModel.SpaceRef
+		if err := tx.Model(&spaceModel.Space{}).Where("category_id = ?", category.ID).Order("name").Find(&dependents).Error; err != nil {
+			return err
+		}
+
+		if reassignTo != nil {
+			if *reassignTo == category.ID || tree.Get(*reassignTo) == nil {
+				return ErrInvalidReassignTarget
+			}
+			// space yang sudah di-soft delete ikut dipindah agar tetap bisa di-restore
+			err := tx.Unscoped().Model(&spaceModel.Space{}).
+				Where("category_id = ?", category.ID).
+				Update("category_id", *reassignTo).Error
+			if err != nil {
+				return err
+			}
+		} else if len(dependents) > 0 {
+			return &spaceModel.DependentSpacesError{Resource: "category", Spaces: dependents}
+		}
+
+		return tx.Delete(category).Error
+	})
+}
+
+// GetDeleted mengambil kategori yang sudah di-soft delete
+func (s *CategoryService) GetDeleted(ctx context.Context) ([]categoryModel.Category, error) {
+	categories := []categoryModel.Category{}
+	if err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&categories).Error; err != nil {
+		return nil, err
 	}
+	return categories, nil
+}
 
-	return s.db.Delete(&categoryModel.Category{}, "id = ?", id).Error
+// Restore mengembalikan kategori yang sudah di-soft delete. Jika parent-nya sudah
+// terhapus atau pohon tidak lagi muat, kategori dikembalikan sebagai root
+func (s *CategoryService) Restore(ctx context.Context, id string) (*categoryModel.Category, error) {
+	var category categoryModel.Category
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tree, err := s.lockTree(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&category, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+
+		depth, err := tree.DepthUnder(category.ID, category.ParentID)
+		if err != nil {
+			category.ParentID = nil
+			depth = 0
+		}
+		category.Depth = depth
+		category.DeletedAt = gorm.DeletedAt{}
+
+		return tx.Unscoped().Model(&category).Updates(map[string]interface{}{
+			"parent_id":  category.ParentID,
+			"depth":      category.Depth,
+			"deleted_at": nil,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// loadTree memuat struktur pohon (tanpa deskripsi) milik organisasi aktif
+func (s *CategoryService) loadTree(db *gorm.DB) (*categoryModel.Tree, error) {
+	var categories []categoryModel.Category
+	if err := db.Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categoryModel.NewTree(categories), nil
+}
+
+// lockTree memuat pohon sambil mengunci baris kategori sehingga perubahan pohon yang
+// berjalan bersamaan tidak bisa membentuk siklus
+func (s *CategoryService) lockTree(tx *gorm.DB) (*categoryModel.Tree, error) {
+	return s.loadTree(tx.Clauses(clause.Locking{Strength: "UPDATE"}))
+}
+
+// uniqueSlug membuat slug dari nama dan menambahkan akhiran -2, -3, ... jika sudah dipakai
+func (s *CategoryService) uniqueSlug(tx *gorm.DB, name string, excludeID uuid.UUID) (string, error) {
+	base := categoryModel.GenerateSlug(name)
+
+	// kategori yang di-soft delete tetap memegang slug-nya di unique index
+	var slugs []string
+	err := tx.Unscoped().Model(&categoryModel.Category{}).
+		Where("(slug = ? OR slug LIKE ?) AND id <> ?", base, base+"-%", excludeID).
+		Pluck("slug", &slugs).Error
+	if err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		taken[slug] = true
+	}
+	return categoryModel.NextSlug(base, taken), nil
 }
@@ -2,35 +2,37 @@ package model
 
 import (
 	"errors"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// MaxDepth adalah jumlah level maksimal pohon kategori (root = level 1)
+const MaxDepth = 3
+
 type Category struct {
-	ID          uuid.UUID `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
-	Name        string    `json:"name"`
-	Slug        string    `json:"slug"`
-	Description string    `json:"description"`
-	CreatedBy   uuid.UUID `json:"created_by" gorm:"type:char(36)"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uuid.UUID      `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
+	OrganizationID uuid.UUID      `json:"organization_id" gorm:"type:char(36);not null;index;uniqueIndex:idx_categories_org_slug,priority:1"`
+	ParentID       *uuid.UUID     `json:"parent_id" gorm:"type:char(36);index"`
+	Depth          int            `json:"depth" gorm:"not null;default:0"` // 0 untuk root
+	Name           string         `json:"name"`
+	Slug           string         `json:"slug" gorm:"size:191;not null;uniqueIndex:idx_categories_org_slug,priority:2"`
+	Description    string         `json:"description"`
+	CreatedBy      uuid.UUID      `json:"created_by" gorm:"type:char(36)"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 type CreateCategoryInput struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-}
-
-func generateSlug(name string) string {
-	// Mengubah nama menjadi huruf kecil
-	slug := strings.ToLower(name)
-	// Mengganti spasi dengan tanda strip
-	slug = strings.ReplaceAll(slug, " ", "-")
-	return slug
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"`
 }
 
+// NewCategory membuat kategori baru; Slug dan Depth diisi oleh service
+// karena bergantung pada kategori lain
 func NewCategory(input CreateCategoryInput, userID uuid.UUID) (*Category, error) {
 	if input.Name == "" {
 		return nil, errors.New("category name is required")
@@ -39,7 +41,7 @@ func NewCategory(input CreateCategoryInput, userID uuid.UUID) (*Category, error)
 	return &Category{
 		ID:          uuid.New(),
 		Name:        input.Name,
-		Slug:        generateSlug(input.Name),
+		ParentID:    input.ParentID,
 		Description: input.Description,
 		CreatedBy:   userID,
 	}, nil
@@ -51,7 +53,6 @@ func (c *Category) Update(input CreateCategoryInput) error {
 	}
 
 	c.Name = input.Name
-	c.Slug = generateSlug(input.Name)
 	c.Description = input.Description
 	return nil
 }
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type CategoryTestSuite struct {
+	suite.Suite
+}
+
+func TestCategorySuite(t *testing.T) {
+	suite.Run(t, new(CategoryTestSuite))
+}
+
+func (s *CategoryTestSuite) TestGenerateSlug() {
+	tests := map[string]string{
+		"Meeting Room":              "meeting-room",
+		"meeting room":              "meeting-room",
+		"  Meeting   Room!! ":       "meeting-room",
+		"Café & Co-working":         "cafe-and-co-working",
+		"Straße / Große Räume":      "strasse-grosse-raume",
+		"Ruang Rapat (Lantai 2)":    "ruang-rapat-lantai-2",
+		"?!":                        "category",
+		"日本語":                       "category",
+		"Villa   -- Pantai -- Kuta": "villa-pantai-kuta",
+	}
+	for name, expected := range tests {
+		s.Equal(expected, GenerateSlug(name), name)
+	}
+
+	long := GenerateSlug(strings.Repeat("a", 150))
+	s.Len(long, maxSlugLength)
+}
+
+func (s *CategoryTestSuite) TestNextSlug() {
+	s.Equal("room", NextSlug("room", map[string]bool{}))
+	s.Equal("room-2", NextSlug("room", map[string]bool{"room": true}))
+	s.Equal("room-4", NextSlug("room", map[string]bool{"room": true, "room-2": true, "room-3": true}))
+}
+
+// buildTree: root -> child -> grandchild, dan other sebagai root kedua
+func (s *CategoryTestSuite) buildTree() (*Tree, Category, Category, Category, Category) {
+	root := Category{ID: uuid.New(), Name: "root"}
+	child := Category{ID: uuid.New(), Name: "child", ParentID: &root.ID, Depth: 1}
+	grandchild := Category{ID: uuid.New(), Name: "grandchild", ParentID: &child.ID, Depth: 2}
+	other := Category{ID: uuid.New(), Name: "other"}
+	return NewTree([]Category{root, child, grandchild, other}), root, child, grandchild, other
+}
+
+func (s *CategoryTestSuite) TestTreeNodesAndSubtree() {
+	tree, root, child, grandchild, other := s.buildTree()
+
+	nodes := tree.Nodes()
+	s.Require().Len(nodes, 2)
+	s.Equal(root.ID, nodes[0].ID)
+	s.Require().Len(nodes[0].Children, 1)
+	s.Equal(child.ID, nodes[0].Children[0].ID)
+	s.Equal(other.ID, nodes[1].ID)
+	s.NotNil(nodes[1].Children)
+
+	s.ElementsMatch([]uuid.UUID{root.ID, child.ID, grandchild.ID}, tree.SubtreeIDs(root.ID))
+	s.ElementsMatch([]uuid.UUID{child.ID, grandchild.ID}, tree.Node(child.ID).IDs())
+	s.Nil(tree.SubtreeIDs(uuid.New()))
+}
+
+func (s *CategoryTestSuite) TestDepthLimit() {
+	tree, root, child, grandchild, other := s.buildTree()
+
+	depth, err := tree.DepthUnder(uuid.Nil, &child.ID)
+	s.NoError(err)
+	s.Equal(2, depth)
+
+	_, err = tree.DepthUnder(uuid.Nil, &grandchild.ID)
+	s.ErrorIs(err, ErrCategoryTooDeep)
+
+	// memindahkan subtree setinggi 3 level di bawah root lain melebihi batas
+	_, err = tree.DepthUnder(root.ID, &other.ID)
+	s.ErrorIs(err, ErrCategoryTooDeep)
+
+	missing := uuid.New()
+	_, err = tree.DepthUnder(uuid.Nil, &missing)
+	s.Error(err)
+}
+
+func (s *CategoryTestSuite) TestCyclePrevention() {
+	tree, root, child, grandchild, _ := s.buildTree()
+
+	_, err := tree.DepthUnder(root.ID, &root.ID)
+	s.ErrorIs(err, ErrCategoryCycle)
+
+	_, err = tree.DepthUnder(root.ID, &grandchild.ID)
+	s.ErrorIs(err, ErrCategoryCycle)
+
+	_, err = tree.DepthUnder(child.ID, &grandchild.ID)
+	s.ErrorIs(err, ErrCategoryCycle)
+}
+
+func (s *CategoryTestSuite) TestMoveUpdatesDescendantDepth() {
+	tree, _, child, grandchild, other := s.buildTree()
+
+	changed, err := tree.Move(child.ID, &other.ID)
+	s.Require().NoError(err)
+	s.Len(changed, 0) // depth tidak berubah, hanya parent
+
+	changed, err = tree.Move(child.ID, nil)
+	s.Require().NoError(err)
+	s.Len(changed, 2)
+	s.Equal(0, tree.Get(child.ID).Depth)
+	s.Equal(1, tree.Get(grandchild.ID).Depth)
+	s.Nil(tree.Get(child.ID).ParentID)
+}
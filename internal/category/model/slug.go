@@ -0,0 +1,68 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxSlugLength = 100
+	fallbackSlug  = "category"
+)
+
+// GenerateSlug membuat slug URL dari nama: huruf beraksen ditransliterasi ("Café" -> "cafe"),
+// karakter selain huruf/angka menjadi "-", dan huruf kecil semua
+func GenerateSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// buang tanda diakritik hasil dekomposisi
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+			dash = false
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		return fallbackSlug
+	}
+	return slug
+}
+
+// NextSlug memilih slug pertama yang belum dipakai: base, base-2, base-3, ...
+func NextSlug(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for i := 2; ; i++ {
+		candidate := base + "-" + strconv.Itoa(i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// transliterations untuk huruf yang tidak terurai oleh NFKD
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th", 'ð': "d", 'Ð': "d",
+	'&': "and",
+}
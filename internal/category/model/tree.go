@@ -0,0 +1,168 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryCycle   = errors.New("category cannot be moved under itself or its descendants")
+	ErrCategoryTooDeep = errors.New("category tree exceeds the maximum depth")
+)
+
+// CategoryNode adalah kategori beserta anak-anaknya untuk response pohon
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// IDs mengembalikan id node beserta semua turunannya
+func (n *CategoryNode) IDs() []uuid.UUID {
+	ids := []uuid.UUID{n.ID}
+	for _, child := range n.Children {
+		ids = append(ids, child.IDs()...)
+	}
+	return ids
+}
+
+// Tree adalah indeks kategori milik satu organisasi untuk operasi pohon di memori
+type Tree struct {
+	byID     map[uuid.UUID]*Category
+	children map[uuid.UUID][]*Category
+	roots    []*Category
+}
+
+// NewTree membangun indeks dari daftar kategori (urutan anak mengikuti urutan input)
+func NewTree(categories []Category) *Tree {
+	t := &Tree{
+		byID:     make(map[uuid.UUID]*Category, len(categories)),
+		children: make(map[uuid.UUID][]*Category),
+	}
+	for i := range categories {
+		t.byID[categories[i].ID] = &categories[i]
+	}
+	for i := range categories {
+		c := &categories[i]
+		if c.ParentID != nil && t.byID[*c.ParentID] != nil {
+			t.children[*c.ParentID] = append(t.children[*c.ParentID], c)
+		} else {
+			t.roots = append(t.roots, c)
+		}
+	}
+	return t
+}
+
+func (t *Tree) Get(id uuid.UUID) *Category {
+	return t.byID[id]
+}
+
+// Nodes mengembalikan pohon lengkap mulai dari root
+func (t *Tree) Nodes() []*CategoryNode {
+	return t.nodes(t.roots)
+}
+
+// Node mengembalikan satu kategori beserta seluruh turunannya
+func (t *Tree) Node(id uuid.UUID) *CategoryNode {
+	c := t.byID[id]
+	if c == nil {
+		return nil
+	}
+	return t.nodes([]*Category{c})[0]
+}
+
+func (t *Tree) nodes(categories []*Category) []*CategoryNode {
+	result := make([]*CategoryNode, 0, len(categories))
+	for _, c := range categories {
+		result = append(result, &CategoryNode{
+			Category: *c,
+			Children: t.nodes(t.children[c.ID]),
+		})
+	}
+	return result
+}
+
+// SubtreeIDs mengembalikan id kategori beserta semua turunannya
+func (t *Tree) SubtreeIDs(id uuid.UUID) []uuid.UUID {
+	if t.byID[id] == nil {
+		return nil
+	}
+	ids := []uuid.UUID{id}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			ids = append(ids, child.ID)
+		}
+	}
+	return ids
+}
+
+// height adalah jumlah level subtree mulai dari id (1 untuk daun)
+func (t *Tree) height(id uuid.UUID) int {
+	h := 0
+	for _, child := range t.children[id] {
+		if ch := t.height(child.ID); ch > h {
+			h = ch
+		}
+	}
+	return h + 1
+}
+
+// DepthUnder menghitung depth kategori baru/pindahan di bawah parentID (nil = root)
+// dan memastikan tidak terjadi siklus serta batas MaxDepth tidak terlampaui.
+// id adalah kategori yang dipindah, atau uuid.Nil untuk kategori baru.
+func (t *Tree) DepthUnder(id uuid.UUID, parentID *uuid.UUID) (int, error) {
+	depth := 0
+	if parentID != nil {
+		parent := t.byID[*parentID]
+		if parent == nil {
+			return 0, errors.New("parent category not found")
+		}
+		// batas langkah mencegah loop tak hingga jika data lama sudah mengandung siklus
+		for p, steps := parent, 0; p != nil && steps <= len(t.byID); steps++ {
+			if p.ID == id {
+				return 0, ErrCategoryCycle
+			}
+			if p.ParentID == nil {
+				break
+			}
+			p = t.byID[*p.ParentID]
+		}
+		depth = parent.Depth + 1
+	}
+
+	height := 1
+	if id != uuid.Nil && t.byID[id] != nil {
+		height = t.height(id)
+	}
+	if depth+height > MaxDepth {
+		return 0, ErrCategoryTooDeep
+	}
+	return depth, nil
+}
+
+// Move memindahkan kategori ke parent baru dan mengembalikan kategori (termasuk turunan)
+// yang depth-nya berubah
+func (t *Tree) Move(id uuid.UUID, parentID *uuid.UUID) ([]*Category, error) {
+	c := t.byID[id]
+	if c == nil {
+		return nil, errors.New("category not found")
+	}
+	depth, err := t.DepthUnder(id, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ParentID = parentID
+	delta := depth - c.Depth
+	if delta == 0 {
+		return nil, nil
+	}
+
+	var changed []*Category
+	for _, subID := range t.SubtreeIDs(id) {
+		sub := t.byID[subID]
+		sub.Depth += delta
+		changed = append(changed, sub)
+	}
+	return changed, nil
+}
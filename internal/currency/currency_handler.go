@@ -0,0 +1,96 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"booking/internal/currency/model"
+	"booking/pkg/response"
+	errs "booking/shared/errors"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CurrencyHandler struct {
+	currencyService CurrencyServiceInterface
+}
+
+func NewCurrencyHandler(currencyService CurrencyServiceInterface) *CurrencyHandler {
+	return &CurrencyHandler{
+		currencyService: currencyService,
+	}
+}
+
+func (h *CurrencyHandler) GetAll(c echo.Context) error {
+	rates, err := h.currencyService.GetAll(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get exchange rates", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Exchange rates retrieved successfully", rates)
+}
+
+func (h *CurrencyHandler) GetByCurrency(c echo.Context) error {
+	rate, err := h.currencyService.GetRate(c.Request().Context(), c.Param("currency"))
+	if err != nil {
+		if errors.Is(err, errs.ErrUnsupportedCurrency) {
+			return response.NotFound(c, "exchange rate not found", err)
+		}
+		return response.InternalServerError(c, "failed to get exchange rate", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Exchange rate retrieved successfully", rate)
+}
+
+func (h *CurrencyHandler) Upsert(c echo.Context) error {
+	var input model.UpsertExchangeRateInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	rate, err := h.currencyService.Upsert(c.Request().Context(), c.Param("currency"), input)
+	if err != nil {
+		return response.BadRequest(c, "failed to save exchange rate", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Exchange rate saved successfully", rate)
+}
+
+func (h *CurrencyHandler) Delete(c echo.Context) error {
+	if err := h.currencyService.Delete(c.Request().Context(), c.Param("currency")); err != nil {
+		if errors.Is(err, errs.ErrUnsupportedCurrency) {
+			return response.NotFound(c, "exchange rate not found", err)
+		}
+		return response.InternalServerError(c, "failed to delete exchange rate", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Exchange rate deleted successfully", nil)
+}
+
+// Import menerima file JSON (multipart field "file") berisi {"USD": 16250, ...}
+func (h *CurrencyHandler) Import(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "file is required", err)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.BadRequest(c, "failed to open file", err)
+	}
+	defer file.Close()
+
+	var rates map[string]float64
+	if err := json.NewDecoder(file).Decode(&rates); err != nil {
+		return response.BadRequest(c, "invalid exchange rate file", err)
+	}
+
+	result, err := h.currencyService.Import(c.Request().Context(), rates)
+	if err != nil {
+		return response.BadRequest(c, "failed to import exchange rates", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Exchange rates imported successfully", result)
+}
@@ -0,0 +1,159 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"booking/internal/currency/model"
+	"booking/pkg/logger"
+	"booking/shared/constants"
+	errs "booking/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CurrencyServiceInterface mendefinisikan kontrak untuk CurrencyService
+type CurrencyServiceInterface interface {
+	Upsert(ctx context.Context, currency string, input model.UpsertExchangeRateInput) (*model.ExchangeRate, error)
+	GetAll(ctx context.Context) ([]model.ExchangeRate, error)
+	GetRate(ctx context.Context, currency string) (*model.ExchangeRate, error)
+	Delete(ctx context.Context, currency string) error
+	Import(ctx context.Context, rates map[string]float64) ([]model.ExchangeRate, error)
+	LoadFromFile(ctx context.Context, path string) ([]model.ExchangeRate, error)
+	Convert(ctx context.Context, amount float64, currency string) (*model.Conversion, error)
+}
+
+type CurrencyService struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+func NewCurrencyService(db *gorm.DB, logger logger.Logger) *CurrencyService {
+	return &CurrencyService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Upsert membuat atau memperbarui nilai tukar untuk satu mata uang
+func (s *CurrencyService) Upsert(ctx context.Context, currency string, input model.UpsertExchangeRateInput) (*model.ExchangeRate, error) {
+	rate, err := model.NewExchangeRate(currency, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.upsert(s.db.WithContext(ctx), rate); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"currency": rate.Currency,
+			"error":    err.Error(),
+		}).Error("Gagal menyimpan nilai tukar")
+		return nil, err
+	}
+
+	return s.GetRate(ctx, rate.Currency)
+}
+
+func (s *CurrencyService) GetAll(ctx context.Context) ([]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+	if err := s.db.WithContext(ctx).Order("currency").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetRate mengambil nilai tukar; mata uang dasar selalu tersedia dengan rate 1
+func (s *CurrencyService) GetRate(ctx context.Context, currency string) (*model.ExchangeRate, error) {
+	currency = model.NormalizeCurrency(currency)
+	if currency == "" || currency == string(constants.BaseCurrency) {
+		return model.BaseRate(), nil
+	}
+
+	var rate model.ExchangeRate
+	if err := s.db.WithContext(ctx).First(&rate, "currency = ?", currency).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrUnsupportedCurrency
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (s *CurrencyService) Delete(ctx context.Context, currency string) error {
+	result := s.db.WithContext(ctx).Delete(&model.ExchangeRate{}, "currency = ?", model.NormalizeCurrency(currency))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrUnsupportedCurrency
+	}
+	return nil
+}
+
+// Import menyimpan sekumpulan nilai tukar dalam satu transaksi
+func (s *CurrencyService) Import(ctx context.Context, rates map[string]float64) ([]model.ExchangeRate, error) {
+	if len(rates) == 0 {
+		return nil, errors.New("no exchange rates to import")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for currency, value := range rates {
+			rate, err := model.NewExchangeRate(currency, model.UpsertExchangeRateInput{Rate: value})
+			if err != nil {
+				return errors.New(currency + ": " + err.Error())
+			}
+			if err := s.upsert(tx, rate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"count": len(rates),
+			"error": err.Error(),
+		}).Error("Gagal mengimpor nilai tukar")
+		return nil, err
+	}
+
+	return s.GetAll(ctx)
+}
+
+// LoadFromFile membaca file JSON berisi {"USD": 16250, "SGD": 12100} lalu mengimpornya
+func (s *CurrencyService) LoadFromFile(ctx context.Context, path string) ([]model.ExchangeRate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates map[string]float64
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, err
+	}
+
+	return s.Import(ctx, rates)
+}
+
+// Convert mengubah nominal IDR ke mata uang tampilan
+func (s *CurrencyService) Convert(ctx context.Context, amount float64, currency string) (*model.Conversion, error) {
+	rate, err := s.GetRate(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Conversion{
+		Currency: rate.Currency,
+		Rate:     rate.Rate,
+		Amount:   rate.Convert(amount),
+	}, nil
+}
+
+func (s *CurrencyService) upsert(db *gorm.DB, rate *model.ExchangeRate) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rate).Error
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+// ExchangeRate menyimpan nilai tukar satu mata uang terhadap mata uang dasar (IDR).
+// Rate adalah jumlah IDR untuk 1 unit mata uang tersebut, misalnya USD = 16250.
+type ExchangeRate struct {
+	ID        uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	Currency  string    `json:"currency" gorm:"type:char(3);uniqueIndex;not null"`
+	Rate      float64   `json:"rate" gorm:"type:decimal(18,6);not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DTO: Upsert exchange rate input
+type UpsertExchangeRateInput struct {
+	Rate float64 `json:"rate" validate:"required,gt=0"`
+}
+
+// Conversion adalah hasil konversi harga dari IDR ke mata uang tampilan
+type Conversion struct {
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+	Amount   float64 `json:"amount"`
+}
+
+// NormalizeCurrency mengubah kode mata uang menjadi format ISO 4217 (huruf besar)
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func NewExchangeRate(currency string, input UpsertExchangeRateInput) (*ExchangeRate, error) {
+	currency = NormalizeCurrency(currency)
+	if len(currency) != 3 {
+		return nil, errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+	if currency == string(constants.BaseCurrency) {
+		return nil, errors.New("base currency rate cannot be changed")
+	}
+	if input.Rate <= 0 {
+		return nil, errors.New("rate must be greater than zero")
+	}
+
+	return &ExchangeRate{
+		ID:       uuid.New(),
+		Currency: currency,
+		Rate:     input.Rate,
+	}, nil
+}
+
+// Convert mengubah nominal dalam IDR ke mata uang ini, dibulatkan ke 2 desimal
+func (r *ExchangeRate) Convert(amount float64) float64 {
+	return RoundAmount(amount / r.Rate)
+}
+
+// RoundAmount membulatkan nominal ke 2 desimal
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// BaseRate adalah nilai tukar mata uang dasar terhadap dirinya sendiri
+func BaseRate() *ExchangeRate {
+	return &ExchangeRate{
+		Currency: string(constants.BaseCurrency),
+		Rate:     1,
+	}
+}
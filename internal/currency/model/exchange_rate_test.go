@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExchangeRateTestSuite struct {
+	suite.Suite
+}
+
+func TestExchangeRateSuite(t *testing.T) {
+	suite.Run(t, new(ExchangeRateTestSuite))
+}
+
+func (s *ExchangeRateTestSuite) TestNewExchangeRate() {
+	tests := []struct {
+		name        string
+		currency    string
+		rate        float64
+		expectedErr bool
+	}{
+		{name: "success create rate", currency: " usd ", rate: 16250},
+		{name: "error invalid code", currency: "US", rate: 16250, expectedErr: true},
+		{name: "error base currency", currency: "IDR", rate: 1, expectedErr: true},
+		{name: "error zero rate", currency: "SGD", rate: 0, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			rate, err := NewExchangeRate(tt.currency, UpsertExchangeRateInput{Rate: tt.rate})
+			if tt.expectedErr {
+				s.Error(err)
+				s.Nil(rate)
+			} else {
+				s.NoError(err)
+				s.Equal("USD", rate.Currency)
+				s.Equal(tt.rate, rate.Rate)
+			}
+		})
+	}
+}
+
+func (s *ExchangeRateTestSuite) TestConvert() {
+	tests := []struct {
+		name     string
+		rate     *ExchangeRate
+		amount   float64
+		expected float64
+	}{
+		{name: "convert to usd", rate: &ExchangeRate{Currency: "USD", Rate: 16250}, amount: 500000, expected: 30.77},
+		{name: "convert to sgd", rate: &ExchangeRate{Currency: "SGD", Rate: 12100}, amount: 1210000, expected: 100},
+		{name: "base currency", rate: BaseRate(), amount: 750000, expected: 750000},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, tt.rate.Convert(tt.amount))
+		})
+	}
+}
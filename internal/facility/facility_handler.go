@@ -2,10 +2,13 @@ package facility
 
 import (
 	"booking/pkg/response"
+	"errors"
 	"net/http"
 
 	facilityModel "booking/internal/facility/model"
+	spaceModel "booking/internal/space/model"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -30,7 +33,7 @@ func (h *FacilityHandler) Create(c echo.Context) error {
 	}
 
 	// Check if facility already exists
-	exists, err := h.facilityService.CheckExists(input.Name)
+	exists, err := h.facilityService.CheckExists(c.Request().Context(), input.Name)
 	if err != nil {
 		return response.InternalServerError(c, "failed to check facility existence", err)
 	}
@@ -38,8 +41,11 @@ func (h *FacilityHandler) Create(c echo.Context) error {
 		return response.BadRequest(c, "facility already exists", nil)
 	}
 
-	facility, err := h.facilityService.Create(input)
+	facility, err := h.facilityService.Create(c.Request().Context(), input)
 	if err != nil {
+		if errors.Is(err, ErrFacilityTrashed) {
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		}
 		return response.BadRequest(c, "failed to create facility", err)
 	}
 
@@ -47,7 +53,7 @@ func (h *FacilityHandler) Create(c echo.Context) error {
 }
 
 func (h *FacilityHandler) GetAll(c echo.Context) error {
-	facilities, err := h.facilityService.GetAll()
+	facilities, err := h.facilityService.GetAll(c.Request().Context())
 	if err != nil {
 		return response.InternalServerError(c, "failed to get facilities", err)
 	}
@@ -57,7 +63,7 @@ func (h *FacilityHandler) GetAll(c echo.Context) error {
 
 func (h *FacilityHandler) GetByID(c echo.Context) error {
 	id := c.Param("id")
-	facility, err := h.facilityService.GetByID(id)
+	facility, err := h.facilityService.GetByID(c.Request().Context(), id)
 	if err != nil {
 		return response.NotFound(c, "facility not found", err)
 	}
@@ -77,7 +83,7 @@ func (h *FacilityHandler) Update(c echo.Context) error {
 	}
 
 	// Check if facility exists
-	exists, err := h.facilityService.CheckExists(input.Name)
+	exists, err := h.facilityService.CheckExists(c.Request().Context(), input.Name)
 	if err != nil {
 		return response.InternalServerError(c, "failed to check facility existence", err)
 	}
@@ -85,19 +91,63 @@ func (h *FacilityHandler) Update(c echo.Context) error {
 		return response.BadRequest(c, "facility name already exists", nil)
 	}
 
-	facility, err := h.facilityService.Update(id, input)
+	facility, err := h.facilityService.Update(c.Request().Context(), id, input)
 	if err != nil {
+		if errors.Is(err, ErrFacilityTrashed) {
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		}
 		return response.BadRequest(c, "failed to update facility", err)
 	}
 
 	return response.Success(c, http.StatusOK, "Facility updated successfully", facility)
 }
 
+// Delete menghapus fasilitas; query reassign_to memindahkan space yang masih memakai
+// fasilitas ini ke fasilitas lain sebelum dihapus
 func (h *FacilityHandler) Delete(c echo.Context) error {
 	id := c.Param("id")
-	if err := h.facilityService.Delete(id); err != nil {
+
+	var reassignTo *uuid.UUID
+	if raw := c.QueryParam("reassign_to"); raw != "" {
+		target, err := uuid.Parse(raw)
+		if err != nil {
+			return response.BadRequest(c, "invalid reassign_to facility ID", err)
+		}
+		reassignTo = &target
+	}
+
+	if err := h.facilityService.Delete(c.Request().Context(), id, reassignTo); err != nil {
+		var dependents *spaceModel.DependentSpacesError
+		switch {
+		case errors.As(err, &dependents):
+			return response.Conflict(c, err.Error(), err, dependents.Spaces)
+		case errors.Is(err, ErrFacilityNotFound):
+			return response.NotFound(c, "facility not found", err)
+		}
 		return response.BadRequest(c, "failed to delete facility", err)
 	}
 
 	return response.Success(c, http.StatusOK, "Facility deleted successfully", nil)
 }
+
+// GetDeleted menampilkan fasilitas yang sudah dihapus dan masih bisa di-restore
+func (h *FacilityHandler) GetDeleted(c echo.Context) error {
+	facilities, err := h.facilityService.GetDeleted(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get deleted facilities", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Deleted facilities retrieved successfully", facilities)
+}
+
+func (h *FacilityHandler) Restore(c echo.Context) error {
+	facility, err := h.facilityService.Restore(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrFacilityNotFound) {
+			return response.NotFound(c, "deleted facility not found", err)
+		}
+		return response.BadRequest(c, "failed to restore facility", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Facility restored successfully", facility)
+}
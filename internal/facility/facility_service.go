@@ -2,20 +2,33 @@ package facility
 
 import (
 	facilityModel "booking/internal/facility/model"
+	spaceModel "booking/internal/space/model"
+	spaceFacilityModel "booking/internal/space_facility/model"
 	"booking/pkg/logger"
+	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFacilityNotFound      = errors.New("facility not found")
+	ErrFacilityTrashed       = errors.New("a deleted facility with this name exists; restore it instead")
+	ErrInvalidReassignTarget = errors.New("spaces must be reassigned to another existing facility")
 )
 
 type FacilityServiceInterface interface {
-	Create(input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error)
-	GetAll() ([]facilityModel.Facility, error)
-	GetByID(id string) (*facilityModel.Facility, error)
-	Update(id string, input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error)
-	Delete(id string) error
-	CheckExists(name string) (bool, error)
+	Create(ctx context.Context, input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error)
+	GetAll(ctx context.Context) ([]facilityModel.Facility, error)
+	GetByID(ctx context.Context, id string) (*facilityModel.Facility, error)
+	Update(ctx context.Context, id string, input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error)
+	Delete(ctx context.Context, id string, reassignTo *uuid.UUID) error
+	GetDeleted(ctx context.Context) ([]facilityModel.Facility, error)
+	Restore(ctx context.Context, id string) (*facilityModel.Facility, error)
+	CheckExists(ctx context.Context, name string) (bool, error)
 }
 
 type FacilityService struct {
@@ -30,16 +43,16 @@ func NewFacilityService(db *gorm.DB, logger logger.Logger) *FacilityService {
 	}
 }
 
-func (s *FacilityService) CheckExists(name string) (bool, error) {
+func (s *FacilityService) CheckExists(ctx context.Context, name string) (bool, error) {
 	var count int64
-	err := s.db.Model(&facilityModel.Facility{}).Where("name = ?", name).Count(&count).Error
+	err := s.db.WithContext(ctx).Model(&facilityModel.Facility{}).Where("name = ?", name).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (s *FacilityService) Create(input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error) {
+func (s *FacilityService) Create(ctx context.Context, input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error) {
 	if input.Name == "" {
 		return nil, errors.New("name is required")
 	}
@@ -52,7 +65,10 @@ func (s *FacilityService) Create(input facilityModel.CreateFacilityInput) (*faci
 		}).Error("Failed to create new facility")
 		return nil, err
 	}
-	if err := s.db.Create(facility).Error; err != nil {
+	if err := s.checkTrashed(ctx, facility.Name, facility.ID); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(facility).Error; err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"name":  input.Name,
 			"error": err.Error(),
@@ -62,9 +78,9 @@ func (s *FacilityService) Create(input facilityModel.CreateFacilityInput) (*faci
 	return facility, nil
 }
 
-func (s *FacilityService) GetAll() ([]facilityModel.Facility, error) {
+func (s *FacilityService) GetAll(ctx context.Context) ([]facilityModel.Facility, error) {
 	var facilities []facilityModel.Facility
-	if err := s.db.Find(&facilities).Error; err != nil {
+	if err := s.db.WithContext(ctx).Find(&facilities).Error; err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"name":  "",
 			"error": err.Error(),
@@ -74,46 +90,139 @@ func (s *FacilityService) GetAll() ([]facilityModel.Facility, error) {
 	return facilities, nil
 }
 
-func (s *FacilityService) GetByID(id string) (*facilityModel.Facility, error) {
+func (s *FacilityService) GetByID(ctx context.Context, id string) (*facilityModel.Facility, error) {
 	var facility facilityModel.Facility
-	if err := s.db.First(&facility, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&facility, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("facility not found")
+			return nil, ErrFacilityNotFound
 		}
 		return nil, err
 	}
 	return &facility, nil
 }
 
-func (s *FacilityService) Update(id string, input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error) {
+func (s *FacilityService) Update(ctx context.Context, id string, input facilityModel.CreateFacilityInput) (*facilityModel.Facility, error) {
 	var facility facilityModel.Facility
-	if err := s.db.First(&facility, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&facility, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("facility not found")
+			return nil, ErrFacilityNotFound
 		}
 		return nil, err
 	}
 
+	if err := s.checkTrashed(ctx, input.Name, facility.ID); err != nil {
+		return nil, err
+	}
+
 	facility.Name = input.Name
-	if err := s.db.Save(&facility).Error; err != nil {
+	if err := s.db.WithContext(ctx).Save(&facility).Error; err != nil {
 		return nil, err
 	}
 
 	return &facility, nil
 }
 
-func (s *FacilityService) Delete(id string) error {
+// Delete melakukan soft delete fasilitas. Relasi space dengan fasilitas ini dipindah ke
+// reassignTo dalam transaksi yang sama; tanpa reassignTo penghapusan ditolak dengan
+// DependentSpacesError jika masih ada space yang memakainya
+func (s *FacilityService) Delete(ctx context.Context, id string, reassignTo *uuid.UUID) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var facility facilityModel.Facility
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&facility, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFacilityNotFound
+			}
+			return err
+		}
+
+		var dependents []spaceModel.SpaceRef
+		err := tx.Model(&spaceModel.Space{}).
+			Select("spaces.id, spaces.name").
+			Joins("JOIN space_facilities AS sf ON sf.space_id = spaces.id").
+			Where("sf.facility_id = ?", facility.ID).
+			Order("spaces.name").
+			Find(&dependents).Error
+		if err != nil {
+			return err
+		}
+
+		if reassignTo != nil {
+			if err := s.reassign(tx, facility.ID, *reassignTo); err != nil {
+				return err
+			}
+		} else if len(dependents) > 0 {
+			return &spaceModel.DependentSpacesError{Resource: "facility", Spaces: dependents}
+		}
+
+		return tx.Delete(&facility).Error
+	})
+}
+
+// GetDeleted mengambil fasilitas yang sudah di-soft delete
+func (s *FacilityService) GetDeleted(ctx context.Context) ([]facilityModel.Facility, error) {
+	facilities := []facilityModel.Facility{}
+	if err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&facilities).Error; err != nil {
+		return nil, err
+	}
+	return facilities, nil
+}
+
+// Restore mengembalikan fasilitas yang sudah di-soft delete
+func (s *FacilityService) Restore(ctx context.Context, id string) (*facilityModel.Facility, error) {
 	var facility facilityModel.Facility
-	if err := s.db.First(&facility, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&facility, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("facility not found")
+			return nil, ErrFacilityNotFound
 		}
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Unscoped().Model(&facility).Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+	facility.DeletedAt = gorm.DeletedAt{}
+	return &facility, nil
+}
+
+// reassign memindahkan relasi space dari fasilitas from ke fasilitas to; space yang sudah
+// punya fasilitas to dilewati. Tidak memakai INSERT IGNORE karena MySQL juga mengubah error
+// lain (foreign key, NOT NULL) menjadi warning sehingga relasi bisa hilang tanpa rollback.
+func (s *FacilityService) reassign(tx *gorm.DB, from, to uuid.UUID) error {
+	if from == to {
+		return ErrInvalidReassignTarget
+	}
+	var count int64
+	if err := tx.Model(&facilityModel.Facility{}).Where("id = ?", to).Count(&count).Error; err != nil {
 		return err
 	}
+	if count == 0 {
+		return ErrInvalidReassignTarget
+	}
 
-	if err := s.db.Delete(&facility).Error; err != nil {
+	err := tx.Exec(
+		"INSERT INTO space_facilities (space_id, facility_id, created_at, updated_at) "+
+			"SELECT sf.space_id, ?, NOW(), NOW() FROM space_facilities AS sf WHERE sf.facility_id = ? "+
+			"ON DUPLICATE KEY UPDATE space_facilities.facility_id = space_facilities.facility_id",
+		to, from,
+	).Error
+	if err != nil {
 		return err
 	}
+	return tx.Where("facility_id = ?", from).Delete(&spaceFacilityModel.SpaceFacility{}).Error
+}
 
+// checkTrashed menolak nama yang masih dipegang fasilitas yang sudah di-soft delete,
+// karena unique index nama juga berlaku untuk baris yang terhapus
+func (s *FacilityService) checkTrashed(ctx context.Context, name string, excludeID uuid.UUID) error {
+	var count int64
+	err := s.db.WithContext(ctx).Unscoped().Model(&facilityModel.Facility{}).
+		Where("name = ? AND id <> ? AND deleted_at IS NOT NULL", name, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrFacilityTrashed
+	}
 	return nil
 }
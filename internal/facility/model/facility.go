@@ -9,11 +9,12 @@ import (
 )
 
 type Facility struct {
-	ID        uuid.UUID      `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
-	Name      string         `json:"name" gorm:"unique;not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID             uuid.UUID      `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
+	OrganizationID uuid.UUID      `json:"organization_id" gorm:"type:char(36);not null;uniqueIndex:idx_facilities_org_name,priority:1"`
+	Name           string         `json:"name" gorm:"size:191;not null;uniqueIndex:idx_facilities_org_name,priority:2"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 type CreateFacilityInput struct {
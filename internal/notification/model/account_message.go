@@ -0,0 +1,8 @@
+package model
+
+// AccountMessageData adalah data yang tersedia di template email akun
+type AccountMessageData struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// BookingMessageData adalah data yang tersedia di template email booking
+type BookingMessageData struct {
+	Name          string
+	SpaceName     string
+	BookingID     string
+	StartDate     time.Time
+	EndDate       time.Time
+	TotalPrice    float64
+	Currency      string
+	ChargedAmount float64
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusDead    OutboxStatus = "dead"
+
+	// MaxAttempts adalah batas percobaan kirim sebelum pesan masuk dead-letter
+	MaxAttempts = 8
+
+	backoffBase = 30 * time.Second
+	backoffMax  = time.Hour
+)
+
+// OutboxMessage adalah email yang ditulis dalam transaksi yang sama dengan perubahan booking
+// lalu dikirim oleh dispatcher
+type OutboxMessage struct {
+	ID             uuid.UUID           `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID           `json:"organization_id" gorm:"type:char(36);not null;index"`
+	EventType      constants.EventType `json:"event_type" gorm:"type:varchar(50);not null"`
+	AggregateID    uuid.UUID           `json:"aggregate_id" gorm:"type:char(36);not null;index"`
+	Recipient      string              `json:"recipient" gorm:"size:255;not null"`
+	Locale         constants.Locale    `json:"locale" gorm:"type:varchar(5);not null"`
+	Subject        string              `json:"subject" gorm:"size:255;not null"`
+	Body           string              `json:"body" gorm:"type:text;not null"`
+	Status         OutboxStatus        `json:"status" gorm:"type:varchar(10);not null;index:idx_outbox_dispatch"`
+	Attempts       int                 `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time           `json:"next_attempt_at" gorm:"not null;index:idx_outbox_dispatch"`
+	LastError      string              `json:"last_error,omitempty" gorm:"type:text"`
+	SentAt         *time.Time          `json:"sent_at"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func NewOutboxMessage(event constants.EventType, aggregateID uuid.UUID, recipient string, locale constants.Locale, subject, body string) (*OutboxMessage, error) {
+	if recipient == "" {
+		return nil, errors.New("recipient is required")
+	}
+	if subject == "" || body == "" {
+		return nil, errors.New("subject and body are required")
+	}
+
+	return &OutboxMessage{
+		ID:            uuid.New(),
+		EventType:     event,
+		AggregateID:   aggregateID,
+		Recipient:     recipient,
+		Locale:        locale,
+		Subject:       subject,
+		Body:          body,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+func (m *OutboxMessage) MarkSent(now time.Time) {
+	m.Status = OutboxStatusSent
+	m.Attempts++
+	m.LastError = ""
+	m.SentAt = &now
+}
+
+// MarkFailed mencatat kegagalan kirim dan menjadwalkan ulang dengan exponential backoff,
+// atau memindahkan pesan ke dead-letter setelah MaxAttempts
+func (m *OutboxMessage) MarkFailed(err error, now time.Time) {
+	m.Attempts++
+	m.LastError = err.Error()
+	if m.Attempts >= MaxAttempts {
+		m.Status = OutboxStatusDead
+		return
+	}
+	m.NextAttemptAt = now.Add(Backoff(m.Attempts))
+}
+
+// Requeue mengembalikan pesan dead-letter ke antrean dengan hitungan percobaan baru
+func (m *OutboxMessage) Requeue(now time.Time) error {
+	if m.Status == OutboxStatusSent {
+		return errors.New("message has already been sent")
+	}
+	m.Status = OutboxStatusPending
+	m.Attempts = 0
+	m.NextAttemptAt = now
+	return nil
+}
+
+// Backoff menghitung jeda sebelum percobaan berikutnya: 30s, 1m, 2m, ... maksimal 1 jam
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := backoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= backoffMax {
+			return backoffMax
+		}
+	}
+	return d
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxTestSuite struct {
+	suite.Suite
+}
+
+func TestOutboxSuite(t *testing.T) {
+	suite.Run(t, new(OutboxTestSuite))
+}
+
+func (s *OutboxTestSuite) newMessage() *OutboxMessage {
+	message, err := NewOutboxMessage(constants.EventBookingCreated, uuid.New(), "user@example.com", constants.LocaleEnglish, "Subject", "Body")
+	s.Require().NoError(err)
+	return message
+}
+
+func (s *OutboxTestSuite) TestBackoff() {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 0, expected: 0},
+		{attempts: 1, expected: 30 * time.Second},
+		{attempts: 2, expected: time.Minute},
+		{attempts: 4, expected: 4 * time.Minute},
+		{attempts: 10, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		s.Equal(tt.expected, Backoff(tt.attempts))
+	}
+}
+
+func (s *OutboxTestSuite) TestMarkFailedSchedulesRetry() {
+	message := s.newMessage()
+	now := time.Now()
+
+	message.MarkFailed(errors.New("connection refused"), now)
+
+	s.Equal(OutboxStatusPending, message.Status)
+	s.Equal(1, message.Attempts)
+	s.Equal("connection refused", message.LastError)
+	s.Equal(now.Add(30*time.Second), message.NextAttemptAt)
+}
+
+func (s *OutboxTestSuite) TestMarkFailedDeadLetters() {
+	message := s.newMessage()
+	for i := 0; i < MaxAttempts; i++ {
+		message.MarkFailed(errors.New("mailbox unavailable"), time.Now())
+	}
+
+	s.Equal(OutboxStatusDead, message.Status)
+	s.Equal(MaxAttempts, message.Attempts)
+
+	s.NoError(message.Requeue(time.Now()))
+	s.Equal(OutboxStatusPending, message.Status)
+	s.Zero(message.Attempts)
+}
+
+func (s *OutboxTestSuite) TestMarkSent() {
+	message := s.newMessage()
+	message.MarkSent(time.Now())
+
+	s.Equal(OutboxStatusSent, message.Status)
+	s.NotNil(message.SentAt)
+	s.Error(message.Requeue(time.Now()))
+}
@@ -0,0 +1,38 @@
+package notification
+
+import (
+	"net/http"
+
+	"booking/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	notificationService NotificationServiceInterface
+}
+
+func NewNotificationHandler(notificationService NotificationServiceInterface) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetAll menampilkan isi outbox; ?status=pending|sent|dead
+func (h *NotificationHandler) GetAll(c echo.Context) error {
+	messages, err := h.notificationService.GetMessages(c.Request().Context(), c.QueryParam("status"))
+	if err != nil {
+		return response.InternalServerError(c, "failed to get notifications", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Notifications retrieved successfully", messages)
+}
+
+func (h *NotificationHandler) Retry(c echo.Context) error {
+	message, err := h.notificationService.Retry(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.BadRequest(c, "failed to retry notification", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Notification requeued successfully", message)
+}
@@ -0,0 +1,180 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/notification/model"
+	userModel "booking/internal/user/model"
+	"booking/pkg/logger"
+	"booking/pkg/mailer"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// dispatchBatchSize membatasi jumlah pesan yang diproses dalam satu putaran dispatcher
+	dispatchBatchSize = 50
+	// sendTimeout membatasi pengiriman satu pesan ke mailer
+	sendTimeout = 15 * time.Second
+	// dispatchLease menyembunyikan pesan yang sudah diklaim dari dispatcher lain selama
+	// dikirim; harus lebih lama dari satu batch (dispatchBatchSize x sendTimeout)
+	dispatchLease = 15 * time.Minute
+)
+
+// NotificationServiceInterface mendefinisikan kontrak untuk NotificationService
+type NotificationServiceInterface interface {
+	EnqueueBooking(tx *gorm.DB, event constants.EventType, recipient *userModel.User, data model.BookingMessageData) error
+	GetMessages(ctx context.Context, status string) ([]model.OutboxMessage, error)
+	Retry(ctx context.Context, id string) (*model.OutboxMessage, error)
+	DispatchPending(ctx context.Context) (int, error)
+}
+
+type NotificationService struct {
+	db     *gorm.DB
+	logger logger.Logger
+	mailer mailer.Mailer
+}
+
+func NewNotificationService(db *gorm.DB, logger logger.Logger, mailer mailer.Mailer) *NotificationService {
+	return &NotificationService{
+		db:     db,
+		logger: logger,
+		mailer: mailer,
+	}
+}
+
+// EnqueueBooking merender email booking dan menulisnya ke outbox memakai tx milik pemanggil,
+// sehingga email hanya terkirim jika perubahan booking ter-commit
+func (s *NotificationService) EnqueueBooking(tx *gorm.DB, event constants.EventType, recipient *userModel.User, data model.BookingMessageData) error {
+	if recipient == nil {
+		return errors.New("recipient is required")
+	}
+
+	locale := recipient.Locale
+	if locale == "" {
+		locale = constants.DefaultLocale
+	}
+	data.Name = recipient.Name
+
+	subject, body, err := renderBooking(event, locale, data)
+	if err != nil {
+		return err
+	}
+
+	aggregateID, err := uuid.Parse(data.BookingID)
+	if err != nil {
+		return errors.New("invalid booking ID")
+	}
+
+	message, err := model.NewOutboxMessage(event, aggregateID, recipient.Email, locale, subject, body)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(message).Error
+}
+
+func (s *NotificationService) GetMessages(ctx context.Context, status string) ([]model.OutboxMessage, error) {
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(200)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var messages []model.OutboxMessage
+	if err := query.Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// Retry mengembalikan pesan (biasanya dead-letter) ke antrean
+func (s *NotificationService) Retry(ctx context.Context, id string) (*model.OutboxMessage, error) {
+	var message model.OutboxMessage
+	if err := s.db.WithContext(ctx).First(&message, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, err
+	}
+
+	if err := message.Requeue(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// DispatchPending mengirim pesan yang sudah jatuh tempo. Pesan diklaim di transaksi singkat
+// lalu dikirim di luar transaksi dan disimpan satu per satu, sehingga lock tidak ditahan
+// selama SMTP dan pesan yang sudah terkirim tidak ikut di-rollback lalu terkirim dua kali.
+func (s *NotificationService) DispatchPending(ctx context.Context) (int, error) {
+	messages, err := s.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs error
+	for i := range messages {
+		message := &messages[i]
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := s.mailer.Send(sendCtx, mailer.Message{
+			To:      message.Recipient,
+			Subject: message.Subject,
+			Body:    message.Body,
+		})
+		cancel()
+		if err != nil {
+			message.MarkFailed(err, time.Now())
+			s.logger.WithFields(logrus.Fields{
+				"notification_id": message.ID,
+				"attempts":        message.Attempts,
+				"status":          message.Status,
+				"error":           err.Error(),
+			}).Warn("Gagal mengirim notifikasi")
+		} else {
+			message.MarkSent(time.Now())
+			sent++
+		}
+
+		if err := s.db.WithContext(ctx).Save(message).Error; err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"notification_id": message.ID,
+				"error":           err.Error(),
+			}).Error("Gagal menyimpan status notifikasi")
+			errs = errors.Join(errs, err)
+		}
+	}
+	return sent, errs
+}
+
+// claim mengunci pesan yang jatuh tempo dengan SKIP LOCKED dan memundurkan next_attempt_at
+// sebagai lease; jika proses mati sebelum status disimpan, pesan dicoba lagi setelah lease habis
+func (s *NotificationService) claim(ctx context.Context) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxStatusPending, now).
+			Order("next_attempt_at").
+			Limit(dispatchBatchSize).
+			Find(&messages).Error; err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+		}
+		return tx.Model(&model.OutboxMessage{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(dispatchLease)).Error
+	})
+	return messages, err
+}
@@ -0,0 +1,234 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"booking/internal/notification/model"
+	"booking/shared/constants"
+)
+
+type messageTemplate struct {
+	subject string
+	body    string
+}
+
+var templateFuncs = template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.Format("02 Jan 2006 15:04")
+	},
+	"money": func(amount float64) string {
+		return fmt.Sprintf("%.2f", amount)
+	},
+}
+
+// bookingTemplates berisi template email per event dan bahasa
+var bookingTemplates = map[constants.EventType]map[constants.Locale]messageTemplate{
+	constants.EventBookingCreated: {
+		constants.LocaleIndonesian: {
+			subject: "Booking {{.SpaceName}} berhasil dibuat",
+			body: `Halo {{.Name}},
+
+Terima kasih, booking Anda untuk {{.SpaceName}} sudah kami terima.
+
+Kode booking : {{.BookingID}}
+Check-in     : {{date .StartDate}}
+Check-out    : {{date .EndDate}}
+Total        : {{.Currency}} {{money .ChargedAmount}}
+
+Silakan selesaikan pembayaran agar booking Anda terkonfirmasi.
+`,
+		},
+		constants.LocaleEnglish: {
+			subject: "Your booking at {{.SpaceName}} has been created",
+			body: `Hi {{.Name}},
+
+Thank you, we have received your booking for {{.SpaceName}}.
+
+Booking code : {{.BookingID}}
+Check-in     : {{date .StartDate}}
+Check-out    : {{date .EndDate}}
+Total        : {{.Currency}} {{money .ChargedAmount}}
+
+Please complete the payment to confirm your booking.
+`,
+		},
+	},
+	constants.EventBookingPaid: {
+		constants.LocaleIndonesian: {
+			subject: "Pembayaran booking {{.SpaceName}} berhasil",
+			body: `Halo {{.Name}},
+
+Pembayaran untuk booking {{.BookingID}} sudah kami terima. Booking Anda terkonfirmasi.
+
+Check-in     : {{date .StartDate}}
+Check-out    : {{date .EndDate}}
+Dibayar      : {{.Currency}} {{money .ChargedAmount}}
+
+Sampai jumpa di {{.SpaceName}}!
+`,
+		},
+		constants.LocaleEnglish: {
+			subject: "Payment received for {{.SpaceName}}",
+			body: `Hi {{.Name}},
+
+We have received the payment for booking {{.BookingID}}. Your booking is confirmed.
+
+Check-in     : {{date .StartDate}}
+Check-out    : {{date .EndDate}}
+Paid         : {{.Currency}} {{money .ChargedAmount}}
+
+See you at {{.SpaceName}}!
+`,
+		},
+	},
+	constants.EventBookingCancelled: {
+		constants.LocaleIndonesian: {
+			subject: "Booking {{.SpaceName}} dibatalkan",
+			body: `Halo {{.Name}},
+
+Booking {{.BookingID}} untuk {{.SpaceName}} ({{date .StartDate}} - {{date .EndDate}}) telah dibatalkan.
+
+Jika ini bukan permintaan Anda, silakan hubungi kami.
+`,
+		},
+		constants.LocaleEnglish: {
+			subject: "Your booking at {{.SpaceName}} has been cancelled",
+			body: `Hi {{.Name}},
+
+Booking {{.BookingID}} for {{.SpaceName}} ({{date .StartDate}} - {{date .EndDate}}) has been cancelled.
+
+If you did not request this, please contact us.
+`,
+		},
+	},
+}
+
+// accountTemplates berisi template email akun yang memuat link rahasia
+var accountTemplates = map[constants.EventType]map[constants.Locale]messageTemplate{
+	constants.EventEmailVerification: {
+		constants.LocaleIndonesian: {
+			subject: "Verifikasi alamat email Anda",
+			body: `Halo {{.Name}},
+
+Silakan verifikasi alamat email Anda dengan membuka link berikut:
+
+{{.Link}}
+
+Link ini berlaku selama {{.ExpiresIn}} dan hanya bisa dipakai sekali.
+Jika Anda tidak mendaftar, abaikan email ini.
+`,
+		},
+		constants.LocaleEnglish: {
+			subject: "Verify your email address",
+			body: `Hi {{.Name}},
+
+Please verify your email address by opening the link below:
+
+{{.Link}}
+
+This link is valid for {{.ExpiresIn}} and can only be used once.
+If you did not sign up, you can ignore this email.
+`,
+		},
+	},
+	constants.EventPasswordReset: {
+		constants.LocaleIndonesian: {
+			subject: "Atur ulang password Anda",
+			body: `Halo {{.Name}},
+
+Kami menerima permintaan untuk mengatur ulang password akun Anda. Buka link berikut untuk membuat password baru:
+
+{{.Link}}
+
+Link ini berlaku selama {{.ExpiresIn}} dan hanya bisa dipakai sekali.
+Setelah password diganti, semua perangkat yang sedang login akan dikeluarkan.
+Jika Anda tidak meminta ini, abaikan email ini; password Anda tidak berubah.
+`,
+		},
+		constants.LocaleEnglish: {
+			subject: "Reset your password",
+			body: `Hi {{.Name}},
+
+We received a request to reset the password for your account. Open the link below to choose a new password:
+
+{{.Link}}
+
+This link is valid for {{.ExpiresIn}} and can only be used once.
+After the password is changed, every signed-in device will be logged out.
+If you did not request this, you can ignore this email; your password has not changed.
+`,
+		},
+	},
+	constants.EventAccountLocked: {
+		constants.LocaleIndonesian: {
+			subject: "Akun Anda dikunci sementara",
+			body: `Halo {{.Name}},
+
+Ada beberapa percobaan login yang gagal ke akun Anda, sehingga login dikunci selama {{.ExpiresIn}}.
+
+Jika itu bukan Anda, segera atur ulang password Anda melalui link berikut:
+
+{{.Link}}
+`,
+		},
+		constants.LocaleEnglish: {
+			subject: "Your account has been temporarily locked",
+			body: `Hi {{.Name}},
+
+There were several failed login attempts on your account, so logging in has been locked for {{.ExpiresIn}}.
+
+If this was not you, please reset your password using the link below:
+
+{{.Link}}
+`,
+		},
+	},
+}
+
+// renderBooking merender subject dan body email; bahasa yang tidak dikenal memakai bahasa default
+func renderBooking(event constants.EventType, locale constants.Locale, data model.BookingMessageData) (string, string, error) {
+	return render(bookingTemplates, event, locale, data)
+}
+
+// RenderAccount merender email akun (misalnya verifikasi email). Email ini dikirim langsung,
+// tidak lewat outbox, agar link rahasia tidak tersimpan di database
+func RenderAccount(event constants.EventType, locale constants.Locale, data model.AccountMessageData) (string, string, error) {
+	return render(accountTemplates, event, locale, data)
+}
+
+func render(templates map[constants.EventType]map[constants.Locale]messageTemplate, event constants.EventType, locale constants.Locale, data interface{}) (string, string, error) {
+	byLocale, ok := templates[event]
+	if !ok {
+		return "", "", fmt.Errorf("no template for event %q", event)
+	}
+	tmpl, ok := byLocale[locale]
+	if !ok {
+		tmpl = byLocale[constants.DefaultLocale]
+	}
+
+	subject, err := execute(tmpl.subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(tmpl.body, data)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject), body, nil
+}
+
+func execute(text string, data interface{}) (string, error) {
+	tmpl, err := template.New("message").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"booking/internal/notification/model"
+	"booking/shared/constants"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TemplatesTestSuite struct {
+	suite.Suite
+	data model.BookingMessageData
+}
+
+func TestTemplatesSuite(t *testing.T) {
+	suite.Run(t, new(TemplatesTestSuite))
+}
+
+func (s *TemplatesTestSuite) SetupTest() {
+	s.data = model.BookingMessageData{
+		Name:          "Budi",
+		SpaceName:     "Villa Ubud",
+		BookingID:     "3f1c8d2e-0000-0000-0000-000000000001",
+		StartDate:     time.Date(2026, 11, 1, 14, 0, 0, 0, time.Local),
+		EndDate:       time.Date(2026, 11, 3, 12, 0, 0, 0, time.Local),
+		TotalPrice:    3000000,
+		Currency:      "USD",
+		ChargedAmount: 184.62,
+	}
+}
+
+func (s *TemplatesTestSuite) TestAllEventsHaveBothLocales() {
+	for event, byLocale := range bookingTemplates {
+		for _, locale := range []constants.Locale{constants.LocaleIndonesian, constants.LocaleEnglish} {
+			_, ok := byLocale[locale]
+			s.True(ok, "missing %s template for %s", locale, event)
+
+			subject, body, err := renderBooking(event, locale, s.data)
+			s.NoError(err)
+			s.Contains(subject, "Villa Ubud")
+			s.Contains(body, s.data.BookingID)
+		}
+	}
+}
+
+func (s *TemplatesTestSuite) TestRenderBookingCreated() {
+	subject, body, err := renderBooking(constants.EventBookingCreated, constants.LocaleIndonesian, s.data)
+	s.Require().NoError(err)
+	s.Equal("Booking Villa Ubud berhasil dibuat", subject)
+	s.Contains(body, "Halo Budi,")
+	s.Contains(body, "USD 184.62")
+	s.Contains(body, "01 Nov 2026 14:00")
+
+	subject, body, err = renderBooking(constants.EventBookingCreated, constants.LocaleEnglish, s.data)
+	s.Require().NoError(err)
+	s.Equal("Your booking at Villa Ubud has been created", subject)
+	s.Contains(body, "Hi Budi,")
+}
+
+func (s *TemplatesTestSuite) TestUnknownLocaleFallsBackToDefault() {
+	subject, _, err := renderBooking(constants.EventBookingCancelled, constants.Locale("fr"), s.data)
+	s.Require().NoError(err)
+	s.Equal("Booking Villa Ubud dibatalkan", subject)
+}
+
+func (s *TemplatesTestSuite) TestUnknownEvent() {
+	_, _, err := renderBooking(constants.EventType("space.updated"), constants.LocaleEnglish, s.data)
+	s.Error(err)
+}
+
+func (s *TemplatesTestSuite) TestAccountEventsHaveBothLocales() {
+	data := model.AccountMessageData{Name: "Budi", Link: "http://localhost/verify?token=abc", ExpiresIn: "24 jam"}
+	for event, byLocale := range accountTemplates {
+		for _, locale := range []constants.Locale{constants.LocaleIndonesian, constants.LocaleEnglish} {
+			_, ok := byLocale[locale]
+			s.True(ok, "missing %s template for %s", locale, event)
+
+			_, body, err := RenderAccount(event, locale, data)
+			s.NoError(err)
+			s.Contains(body, data.Link)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSlug adalah organisasi yang menampung data lama sebelum multi-tenant
+const DefaultSlug = "default"
+
+// slug dipakai sebagai subdomain, jadi harus berupa label DNS yang valid
+var slugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type Organization struct {
+	ID        uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	Slug      string    `json:"slug" gorm:"size:63;not null;uniqueIndex"`
+	IsActive  bool      `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DTO: Create organization input; Admin opsional untuk membuat superadmin pertama organisasi
+type CreateOrganizationInput struct {
+	Name  string      `json:"name" validate:"required,min=2,max=100"`
+	Slug  string      `json:"slug" validate:"required,max=63"`
+	Admin *AdminInput `json:"admin"`
+}
+
+type AdminInput struct {
+	Name     string `json:"name" validate:"required,min=2,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+func NewOrganization(input CreateOrganizationInput) (*Organization, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("organization name is required")
+	}
+
+	slug := NormalizeSlug(input.Slug)
+	if !ValidSlug(slug) {
+		return nil, errors.New("organization slug must contain only lowercase letters, digits and dashes")
+	}
+
+	return &Organization{
+		ID:       uuid.New(),
+		Name:     name,
+		Slug:     slug,
+		IsActive: true,
+	}, nil
+}
+
+func NormalizeSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+func ValidSlug(slug string) bool {
+	return slugPattern.MatchString(slug)
+}
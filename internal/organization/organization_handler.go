@@ -0,0 +1,88 @@
+package organization
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/organization/model"
+	"booking/pkg/response"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OrganizationHandler struct {
+	organizationService OrganizationServiceInterface
+}
+
+func NewOrganizationHandler(organizationService OrganizationServiceInterface) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+	}
+}
+
+type setActiveRequest struct {
+	IsActive bool `json:"is_active"`
+}
+
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	var input model.CreateOrganizationInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+	if input.Admin != nil {
+		if err := c.Validate(input.Admin); err != nil {
+			return response.ValidationError(c, err)
+		}
+	}
+
+	org, err := h.organizationService.Create(c.Request().Context(), input)
+	if err != nil {
+		if errors.Is(err, ErrSlugTaken) {
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		}
+		return response.BadRequest(c, "failed to create organization", err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Organization created successfully", org)
+}
+
+func (h *OrganizationHandler) GetAll(c echo.Context) error {
+	orgs, err := h.organizationService.GetAll(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get organizations", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Organizations retrieved successfully", orgs)
+}
+
+func (h *OrganizationHandler) GetByID(c echo.Context) error {
+	org, err := h.organizationService.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.notFoundOr(c, "failed to get organization", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Organization retrieved successfully", org)
+}
+
+// SetActive mengaktifkan / menonaktifkan organisasi; organisasi nonaktif tidak bisa diakses
+func (h *OrganizationHandler) SetActive(c echo.Context) error {
+	var req setActiveRequest
+	if err := c.Bind(&req); err != nil {
+		return response.BadRequest(c, "invalid request payload", err)
+	}
+
+	org, err := h.organizationService.SetActive(c.Request().Context(), c.Param("id"), req.IsActive)
+	if err != nil {
+		return h.notFoundOr(c, "failed to update organization", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Organization updated successfully", org)
+}
+
+func (h *OrganizationHandler) notFoundOr(c echo.Context, message string, err error) error {
+	if errors.Is(err, ErrOrganizationNotFound) {
+		return response.NotFound(c, err.Error(), err)
+	}
+	return response.InternalServerError(c, message, err)
+}
@@ -0,0 +1,140 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/organization/model"
+	roleModel "booking/internal/role/model"
+	userModel "booking/internal/user/model"
+	"booking/pkg/tenant"
+	"booking/shared/constants"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrSlugTaken            = errors.New("organization slug is already taken")
+)
+
+// OrganizationServiceInterface mendefinisikan kontrak untuk OrganizationService
+type OrganizationServiceInterface interface {
+	Create(ctx context.Context, input model.CreateOrganizationInput) (*model.Organization, error)
+	GetAll(ctx context.Context) ([]model.Organization, error)
+	GetByID(ctx context.Context, id string) (*model.Organization, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Organization, error)
+	SetActive(ctx context.Context, id string, active bool) (*model.Organization, error)
+}
+
+type OrganizationService struct {
+	db *gorm.DB
+}
+
+func NewOrganizationService(db *gorm.DB) *OrganizationService {
+	return &OrganizationService{
+		db: db,
+	}
+}
+
+// Create membuat organisasi baru, sekaligus superadmin pertamanya jika input.Admin diisi
+func (s *OrganizationService) Create(ctx context.Context, input model.CreateOrganizationInput) (*model.Organization, error) {
+	org, err := model.NewOrganization(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var admin *userModel.User
+	if input.Admin != nil {
+		admin, err = userModel.NewUser(userModel.RegisterInput{
+			Name:     input.Admin.Name,
+			Email:    input.Admin.Email,
+			Password: input.Admin.Password,
+		})
+		if err != nil {
+			return nil, err
+		}
+		admin.Role = constants.RoleSuperAdmin
+		// email admin dibuat oleh operator platform sehingga dianggap sudah terverifikasi
+		admin.MarkEmailVerified(time.Now())
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Organization{}).Where("slug = ?", org.Slug).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrSlugTaken
+		}
+
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+
+		// Data awal dibuat di bawah ctx tenant supaya organization_id terisi otomatis
+		scoped := tx.WithContext(tenant.WithOrganization(ctx, org.ID))
+		roles := roleModel.SystemRoles()
+		if err := scoped.Create(&roles).Error; err != nil {
+			return err
+		}
+
+		if admin != nil {
+			return scoped.Create(admin).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+func (s *OrganizationService) GetAll(ctx context.Context) ([]model.Organization, error) {
+	var orgs []model.Organization
+	if err := s.db.WithContext(ctx).Order("name").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (s *OrganizationService) GetByID(ctx context.Context, id string) (*model.Organization, error) {
+	var org model.Organization
+	if err := s.db.WithContext(ctx).First(&org, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GetBySlug mencari organisasi aktif berdasarkan slug (subdomain / header X-Organization)
+func (s *OrganizationService) GetBySlug(ctx context.Context, slug string) (*model.Organization, error) {
+	var org model.Organization
+	err := s.db.WithContext(ctx).
+		Where("slug = ? AND is_active = ?", model.NormalizeSlug(slug), true).
+		First(&org).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (s *OrganizationService) SetActive(ctx context.Context, id string, active bool) (*model.Organization, error) {
+	org, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	org.IsActive = active
+	if err := s.db.WithContext(ctx).Model(org).Update("is_active", active).Error; err != nil {
+		return nil, err
+	}
+	return org, nil
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+// Role adalah kumpulan permission bernama milik satu organisasi. RequireTwoFactor mewajibkan
+// user dengan role ini mengaktifkan 2FA sebelum memakai permission-nya.
+type Role struct {
+	ID               uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID   uuid.UUID `json:"organization_id" gorm:"type:char(36);not null;uniqueIndex:idx_roles_org_name,priority:1"`
+	Name             string    `json:"name" gorm:"size:50;not null;uniqueIndex:idx_roles_org_name,priority:2"`
+	Description      string    `json:"description" gorm:"size:255"`
+	Permissions      string    `json:"-" gorm:"type:text;not null"`
+	IsSystem         bool      `json:"is_system" gorm:"not null;default:false"`
+	RequireTwoFactor bool      `json:"require_two_factor" gorm:"not null;default:false"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// DTO: Create/update role input
+type RoleInput struct {
+	Name             string   `json:"name" validate:"required,max=50"`
+	Description      string   `json:"description" validate:"max=255"`
+	Permissions      []string `json:"permissions" validate:"required"`
+	RequireTwoFactor bool     `json:"require_two_factor"`
+}
+
+type RoleResponse struct {
+	Role
+	Permissions []string `json:"permissions"`
+}
+
+// systemRoles adalah role bawaan yang dibuat untuk setiap organisasi
+var systemRoles = []struct {
+	name        constants.Role
+	description string
+	permissions []constants.Permission
+}{
+	{constants.RoleSuperAdmin, "Akses penuh termasuk pengelolaan user dan role", []constants.Permission{constants.PermissionAll}},
+	{constants.RoleAdmin, "Mengelola katalog, booking dan integrasi", []constants.Permission{
+		constants.PermissionUserRead,
+		constants.PermissionCategoryWrite,
+		constants.PermissionFacilityWrite,
+		constants.PermissionSpaceWrite,
+		constants.PermissionSpaceHost,
+		constants.PermissionBookingManage,
+		constants.PermissionNotificationManage,
+		constants.PermissionWebhookManage,
+	}},
+	{constants.RoleHost, "Mengelola space miliknya sendiri", []constants.Permission{constants.PermissionSpaceHost}},
+	{constants.RoleUser, "Pengguna biasa", nil},
+}
+
+// SystemRoles mengembalikan role bawaan baru; organization_id diisi oleh pemanggil / tenant ctx
+func SystemRoles() []Role {
+	roles := make([]Role, 0, len(systemRoles))
+	for _, r := range systemRoles {
+		perms := make([]string, 0, len(r.permissions))
+		for _, p := range r.permissions {
+			perms = append(perms, string(p))
+		}
+		roles = append(roles, Role{
+			ID:          uuid.New(),
+			Name:        string(r.name),
+			Description: r.description,
+			Permissions: strings.Join(perms, ","),
+			IsSystem:    true,
+		})
+	}
+	return roles
+}
+
+func NewRole(input RoleInput) (*Role, error) {
+	role := &Role{ID: uuid.New()}
+	if err := role.Update(input); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Update mengubah role; nama role bawaan dan permission superadmin tidak bisa diubah
+func (r *Role) Update(input RoleInput) error {
+	name := strings.ToLower(strings.TrimSpace(input.Name))
+	if !namePattern.MatchString(name) {
+		return errors.New("role name must start with a letter and contain only lowercase letters, digits, '-' or '_'")
+	}
+	if r.IsSystem && name != r.Name {
+		return errors.New("system role cannot be renamed")
+	}
+
+	perms, err := NormalizePermissions(input.Permissions)
+	if err != nil {
+		return err
+	}
+	if r.IsSystem && r.Name == string(constants.RoleSuperAdmin) && perms != string(constants.PermissionAll) {
+		return errors.New("superadmin permissions cannot be changed")
+	}
+
+	r.Name = name
+	r.Description = strings.TrimSpace(input.Description)
+	r.Permissions = perms
+	r.RequireTwoFactor = input.RequireTwoFactor
+	return nil
+}
+
+func (r *Role) PermissionList() []string {
+	if r.Permissions == "" {
+		return []string{}
+	}
+	return strings.Split(r.Permissions, ",")
+}
+
+func (r *Role) PermissionSet() PermissionSet {
+	set := PermissionSet{}
+	for _, p := range r.PermissionList() {
+		set[constants.Permission(p)] = true
+	}
+	return set
+}
+
+func (r *Role) ToResponse() RoleResponse {
+	return RoleResponse{
+		Role:        *r,
+		Permissions: r.PermissionList(),
+	}
+}
+
+// PermissionSet adalah permission efektif milik user
+type PermissionSet map[constants.Permission]bool
+
+func (s PermissionSet) Has(permission constants.Permission) bool {
+	return s[constants.PermissionAll] || s[permission]
+}
+
+func (s PermissionSet) HasAll(permissions ...constants.Permission) bool {
+	for _, p := range permissions {
+		if !s.Has(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Covers mengecek apakah s memiliki semua permission di other; dipakai untuk mencegah
+// user memberikan role yang lebih kuat dari role miliknya sendiri
+func (s PermissionSet) Covers(other PermissionSet) bool {
+	if s[constants.PermissionAll] {
+		return true
+	}
+	for p, ok := range other {
+		if ok && !s[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// Restrict membatasi s ke scopes (misalnya scope API key); hasilnya hanya permission yang
+// dimiliki keduanya
+func (s PermissionSet) Restrict(scopes PermissionSet) PermissionSet {
+	result := PermissionSet{}
+	for p, ok := range scopes {
+		if !ok {
+			continue
+		}
+		if p == constants.PermissionAll {
+			return s
+		}
+		if s.Has(p) {
+			result[p] = true
+		}
+	}
+	return result
+}
+
+// NormalizePermissions memvalidasi daftar permission lalu mengurutkannya menjadi string
+// dipisah koma seperti yang disimpan di database
+func NormalizePermissions(permissions []string) (string, error) {
+	seen := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		p = strings.TrimSpace(p)
+		if p == string(constants.PermissionAll) {
+			return p, nil
+		}
+		if !isKnownPermission(p) {
+			return "", errors.New("unknown permission: " + p)
+		}
+		seen[p] = true
+	}
+
+	result := make([]string, 0, len(seen))
+	for p := range seen {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ","), nil
+}
+
+func isKnownPermission(permission string) bool {
+	for _, p := range constants.Permissions {
+		if string(p) == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"testing"
+
+	"booking/shared/constants"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleTestSuite struct {
+	suite.Suite
+}
+
+func TestRoleSuite(t *testing.T) {
+	suite.Run(t, new(RoleTestSuite))
+}
+
+func (s *RoleTestSuite) systemRole(name constants.Role) *Role {
+	roles := SystemRoles()
+	for i := range roles {
+		if roles[i].Name == string(name) {
+			return &roles[i]
+		}
+	}
+	s.FailNow("system role not found", name)
+	return nil
+}
+
+func (s *RoleTestSuite) TestNewRoleNormalizesPermissions() {
+	role, err := NewRole(RoleInput{
+		Name:        " Support ",
+		Permissions: []string{"webhook:manage", "booking:manage", "webhook:manage"},
+	})
+	s.Require().NoError(err)
+	s.Equal("support", role.Name)
+	s.Equal([]string{"booking:manage", "webhook:manage"}, role.PermissionList())
+	s.False(role.IsSystem)
+}
+
+func (s *RoleTestSuite) TestNewRoleRejectsInvalidInput() {
+	_, err := NewRole(RoleInput{Name: "support", Permissions: []string{"space:delete-everything"}})
+	s.Error(err)
+
+	_, err = NewRole(RoleInput{Name: "1support", Permissions: []string{}})
+	s.Error(err)
+
+	role, err := NewRole(RoleInput{Name: "guest", Permissions: []string{}})
+	s.Require().NoError(err)
+	s.Empty(role.PermissionList())
+}
+
+func (s *RoleTestSuite) TestSystemRoleGuards() {
+	admin := s.systemRole(constants.RoleAdmin)
+	s.Error(admin.Update(RoleInput{Name: "manager", Permissions: []string{"space:write"}}))
+	s.NoError(admin.Update(RoleInput{Name: "admin", Permissions: []string{"space:write"}}))
+	s.Equal([]string{"space:write"}, admin.PermissionList())
+
+	superadmin := s.systemRole(constants.RoleSuperAdmin)
+	s.Error(superadmin.Update(RoleInput{Name: "superadmin", Permissions: []string{"space:write"}}))
+	s.NoError(superadmin.Update(RoleInput{Name: "superadmin", Permissions: []string{"*"}}))
+}
+
+func (s *RoleTestSuite) TestSystemRolePermissions() {
+	superadmin := s.systemRole(constants.RoleSuperAdmin).PermissionSet()
+	admin := s.systemRole(constants.RoleAdmin).PermissionSet()
+	host := s.systemRole(constants.RoleHost).PermissionSet()
+	user := s.systemRole(constants.RoleUser).PermissionSet()
+
+	for _, p := range constants.Permissions {
+		s.True(superadmin.Has(p), p)
+		s.False(user.Has(p), p)
+	}
+
+	s.True(admin.HasAll(constants.PermissionSpaceWrite, constants.PermissionSpaceHost, constants.PermissionUserRead))
+	s.False(admin.Has(constants.PermissionUserManage))
+	s.False(admin.Has(constants.PermissionRoleManage))
+
+	s.True(host.Has(constants.PermissionSpaceHost))
+	s.False(host.Has(constants.PermissionSpaceWrite))
+}
+
+func (s *RoleTestSuite) TestCovers() {
+	superadmin := s.systemRole(constants.RoleSuperAdmin).PermissionSet()
+	admin := s.systemRole(constants.RoleAdmin).PermissionSet()
+	host := s.systemRole(constants.RoleHost).PermissionSet()
+	user := s.systemRole(constants.RoleUser).PermissionSet()
+
+	s.True(superadmin.Covers(admin))
+	s.True(admin.Covers(host))
+	s.True(host.Covers(user))
+	s.False(admin.Covers(superadmin))
+	s.False(host.Covers(admin))
+}
+
+func (s *RoleTestSuite) TestRestrict() {
+	superadmin := s.systemRole(constants.RoleSuperAdmin).PermissionSet()
+	admin := s.systemRole(constants.RoleAdmin).PermissionSet()
+	scopes := PermissionSet{constants.PermissionSpaceWrite: true, constants.PermissionRoleManage: true}
+
+	restricted := superadmin.Restrict(scopes)
+	s.True(restricted.Has(constants.PermissionSpaceWrite))
+	s.False(restricted.Has(constants.PermissionUserManage))
+
+	// admin tidak punya role:manage; scope tidak menambah permission
+	restricted = admin.Restrict(scopes)
+	s.True(restricted.Has(constants.PermissionSpaceWrite))
+	s.False(restricted.Has(constants.PermissionRoleManage))
+
+	s.Equal(admin, admin.Restrict(PermissionSet{constants.PermissionAll: true}))
+}
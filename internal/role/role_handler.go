@@ -0,0 +1,97 @@
+package role
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/role/model"
+	"booking/pkg/response"
+	"booking/shared/constants"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	roleService RoleServiceInterface
+}
+
+func NewRoleHandler(roleService RoleServiceInterface) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+func (h *RoleHandler) Create(c echo.Context) error {
+	var input model.RoleInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	role, err := h.roleService.Create(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, "failed to create role", err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Role created successfully", role.ToResponse())
+}
+
+func (h *RoleHandler) GetAll(c echo.Context) error {
+	roles, err := h.roleService.GetAll(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get roles", err)
+	}
+
+	result := make([]model.RoleResponse, 0, len(roles))
+	for i := range roles {
+		result = append(result, roles[i].ToResponse())
+	}
+	return response.Success(c, http.StatusOK, "Roles retrieved successfully", result)
+}
+
+func (h *RoleHandler) GetByID(c echo.Context) error {
+	role, err := h.roleService.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.errorResponse(c, "failed to get role", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Role retrieved successfully", role.ToResponse())
+}
+
+func (h *RoleHandler) Update(c echo.Context) error {
+	var input model.RoleInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	role, err := h.roleService.Update(c.Request().Context(), c.Param("id"), input)
+	if err != nil {
+		return h.errorResponse(c, "failed to update role", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Role updated successfully", role.ToResponse())
+}
+
+func (h *RoleHandler) Delete(c echo.Context) error {
+	if err := h.roleService.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return h.errorResponse(c, "failed to delete role", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Role deleted successfully", nil)
+}
+
+// GetPermissions menampilkan semua permission yang bisa diberikan ke role
+func (h *RoleHandler) GetPermissions(c echo.Context) error {
+	return response.Success(c, http.StatusOK, "Permissions retrieved successfully", constants.Permissions)
+}
+
+func (h *RoleHandler) errorResponse(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, ErrRoleNotFound):
+		return response.NotFound(c, err.Error(), err)
+	case errors.Is(err, ErrRoleExists), errors.Is(err, ErrRoleInUse):
+		return response.Error(c, http.StatusConflict, err.Error(), err)
+	default:
+		return response.BadRequest(c, message, err)
+	}
+}
@@ -0,0 +1,189 @@
+package role
+
+import (
+	"context"
+	"errors"
+
+	"booking/internal/role/model"
+	"booking/internal/session"
+	userModel "booking/internal/user/model"
+	"booking/shared/constants"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound     = errors.New("role not found")
+	ErrRoleExists       = errors.New("role name is already used")
+	ErrRoleInUse        = errors.New("role is still assigned to users")
+	ErrSystemRoleDelete = errors.New("system role cannot be deleted")
+)
+
+// RoleServiceInterface mendefinisikan kontrak untuk RoleService
+type RoleServiceInterface interface {
+	Create(ctx context.Context, input model.RoleInput) (*model.Role, error)
+	GetAll(ctx context.Context) ([]model.Role, error)
+	GetByID(ctx context.Context, id string) (*model.Role, error)
+	GetByName(ctx context.Context, name constants.Role) (*model.Role, error)
+	Update(ctx context.Context, id string, input model.RoleInput) (*model.Role, error)
+	Delete(ctx context.Context, id string) error
+	Permissions(ctx context.Context, role constants.Role) (model.PermissionSet, error)
+	RequiresTwoFactor(ctx context.Context, role constants.Role) (bool, error)
+}
+
+type RoleService struct {
+	db             *gorm.DB
+	sessionService session.SessionServiceInterface
+}
+
+func NewRoleService(db *gorm.DB, sessionService session.SessionServiceInterface) *RoleService {
+	return &RoleService{
+		db:             db,
+		sessionService: sessionService,
+	}
+}
+
+func (s *RoleService) Create(ctx context.Context, input model.RoleInput) (*model.Role, error) {
+	role, err := model.NewRole(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUniqueName(ctx, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(role).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *RoleService) GetAll(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	if err := s.db.WithContext(ctx).Order("is_system DESC, name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (s *RoleService) GetByID(ctx context.Context, id string) (*model.Role, error) {
+	var role model.Role
+	if err := s.db.WithContext(ctx).First(&role, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s *RoleService) GetByName(ctx context.Context, name constants.Role) (*model.Role, error) {
+	var role model.Role
+	if err := s.db.WithContext(ctx).Where("name = ?", string(name)).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Update mengubah role; jika nama berubah, user dengan role lama ikut dipindahkan dan access
+// token mereka ditolak karena claim role masih berisi nama lama yang tidak lagi punya permission
+func (s *RoleService) Update(ctx context.Context, id string, input model.RoleInput) (*model.Role, error) {
+	role, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := role.Name
+	if err := role.Update(input); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUniqueName(ctx, role); err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return err
+		}
+		if role.Name == oldName {
+			return nil
+		}
+		if err := tx.Model(&userModel.User{}).Where("role = ?", oldName).Pluck("id", &userIDs).Error; err != nil {
+			return err
+		}
+		return tx.Model(&userModel.User{}).Where("role = ?", oldName).Update("role", role.Name).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Perubahan di database sudah tersimpan; kegagalan mencabut token hanya dicatat oleh
+	// sessionService dan user tetap mendapat role baru setelah refresh
+	if len(userIDs) > 0 {
+		_ = s.sessionService.InvalidateAccessTokens(ctx, userIDs...)
+	}
+	return role, nil
+}
+
+func (s *RoleService) Delete(ctx context.Context, id string) error {
+	role, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return ErrSystemRoleDelete
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&userModel.User{}).Where("role = ?", role.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+
+	return s.db.WithContext(ctx).Delete(role).Error
+}
+
+// Permissions mengembalikan permission efektif untuk nama role; role yang tidak ada tidak punya permission
+func (s *RoleService) Permissions(ctx context.Context, role constants.Role) (model.PermissionSet, error) {
+	r, err := s.GetByName(ctx, role)
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return model.PermissionSet{}, nil
+		}
+		return nil, err
+	}
+	return r.PermissionSet(), nil
+}
+
+// RequiresTwoFactor mengecek apakah role mewajibkan 2FA; role yang tidak ada tidak mewajibkan
+func (s *RoleService) RequiresTwoFactor(ctx context.Context, role constants.Role) (bool, error) {
+	r, err := s.GetByName(ctx, role)
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return r.RequireTwoFactor, nil
+}
+
+func (s *RoleService) ensureUniqueName(ctx context.Context, role *model.Role) error {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&model.Role{}).
+		Where("name = ? AND id <> ?", role.Name, role.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleExists
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/session/model"
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultKeyRotation = 30 * 24 * time.Hour
+	DefaultKeyGrace    = 24 * time.Hour
+
+	// keyRefreshInterval adalah seberapa sering key dimuat ulang dari database agar key
+	// yang dibuat instance lain ikut dipakai
+	keyRefreshInterval = time.Minute
+	// maxKeyPublishLead adalah seberapa lama key berikutnya dipublikasikan di JWKS sebelum
+	// mulai dipakai, agar service lain yang meng-cache JWKS sudah mengenalnya
+	maxKeyPublishLead = time.Hour
+)
+
+// KeyRotator membuat key penanda tangan access token secara terjadwal dan memuatnya ke
+// KeySet. Key lama tetap dipakai memverifikasi selama masa tenggang setelah diganti.
+type KeyRotator struct {
+	db       *gorm.DB
+	logger   logger.Logger
+	keys     *jwt.KeySet
+	secret   string
+	rotation time.Duration
+	grace    time.Duration
+	now      func() time.Time
+}
+
+// NewKeyRotator membuat rotator; grace minimal sama dengan masa berlaku access token agar
+// token yang ditandatangani key lama tidak ditolak sebelum kedaluwarsa
+func NewKeyRotator(db *gorm.DB, logger logger.Logger, keys *jwt.KeySet, secret string, rotation, grace, accessTTL time.Duration) *KeyRotator {
+	if secret == "" {
+		panic("jwt secret is required")
+	}
+	if rotation <= 0 {
+		rotation = DefaultKeyRotation
+	}
+	if grace <= 0 {
+		grace = DefaultKeyGrace
+	}
+	if grace < accessTTL {
+		grace = accessTTL
+	}
+
+	return &KeyRotator{
+		db:       db,
+		logger:   logger,
+		keys:     keys,
+		secret:   secret,
+		rotation: rotation,
+		grace:    grace,
+		now:      time.Now,
+	}
+}
+
+// Start berjalan sampai ctx dibatalkan; tidak melakukan apa-apa pada mode HMAC
+func (r *KeyRotator) Start(ctx context.Context) {
+	if !r.keys.Asymmetric() {
+		return
+	}
+
+	ticker := time.NewTicker(keyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				r.logger.WithFields(logrus.Fields{
+					"error": err.Error(),
+				}).Error("Gagal merotasi key penanda tangan token")
+			}
+		}
+	}
+}
+
+// Refresh memastikan key untuk periode ini (dan periode berikutnya menjelang rotasi) ada,
+// menghapus key yang sudah kedaluwarsa, lalu memuat key yang masih berlaku ke KeySet
+func (r *KeyRotator) Refresh(ctx context.Context) error {
+	if !r.keys.Asymmetric() {
+		return nil
+	}
+
+	now := r.now()
+	current := model.KeyPeriod(now, r.rotation)
+	if err := r.ensure(ctx, current); err != nil {
+		return err
+	}
+	next := current.Add(r.rotation)
+	if !now.Before(next.Add(-r.publishLead())) {
+		if err := r.ensure(ctx, next); err != nil {
+			return err
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.SigningKey{}).Error; err != nil {
+		return err
+	}
+
+	var stored []model.SigningKey
+	if err := r.db.WithContext(ctx).Where("expires_at > ?", now).Find(&stored).Error; err != nil {
+		return err
+	}
+
+	keys := make([]*jwt.SigningKey, 0, len(stored))
+	for _, s := range stored {
+		key, err := r.parse(s)
+		if err != nil {
+			// Key yang tidak bisa dibaca (misalnya JWT_SECRET berubah) dilewati
+			r.logger.WithFields(logrus.Fields{
+				"kid":   s.ID,
+				"error": err.Error(),
+			}).Error("Key penanda tangan token tidak bisa dibaca")
+			continue
+		}
+		keys = append(keys, key)
+	}
+	r.keys.Replace(keys)
+	return nil
+}
+
+// ensure membuat key untuk periode yang dimulai di start jika belum ada; kid yang sama dari
+// instance lain diabaikan
+func (r *KeyRotator) ensure(ctx context.Context, start time.Time) error {
+	id := model.SigningKeyID(r.keys.Algorithm(), start)
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.SigningKey{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	key, err := jwt.GenerateSigningKey(id, r.keys.Algorithm(), start, start.Add(r.rotation+r.grace))
+	if err != nil {
+		return err
+	}
+	privatePEM, err := key.MarshalPrivateKey()
+	if err != nil {
+		return err
+	}
+	encrypted, err := jwt.EncryptPrivateKey(privatePEM, r.secret)
+	if err != nil {
+		return err
+	}
+
+	stored := model.SigningKey{
+		ID:          id,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  encrypted,
+		ActivatesAt: key.ActivatesAt,
+		ExpiresAt:   key.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&stored).Error; err != nil {
+		return err
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"kid":          id,
+		"activates_at": key.ActivatesAt,
+	}).Info("Key penanda tangan token baru dibuat")
+	return nil
+}
+
+func (r *KeyRotator) parse(stored model.SigningKey) (*jwt.SigningKey, error) {
+	privatePEM, err := jwt.DecryptPrivateKey(stored.PrivateKey, r.secret)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseSigningKey(stored.ID, stored.Algorithm, privatePEM, stored.ActivatesAt, stored.ExpiresAt)
+}
+
+func (r *KeyRotator) publishLead() time.Duration {
+	if lead := r.rotation / 2; lead < maxKeyPublishLead {
+		return lead
+	}
+	return maxKeyPublishLead
+}
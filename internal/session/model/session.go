@@ -0,0 +1,155 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+	TokenType         = "Bearer"
+
+	refreshTokenPrefix = "rt_"
+	refreshTokenBytes  = 32
+	maxUserAgentLen    = 512
+	maxIPAddressLen    = 45
+)
+
+// Alasan session dicabut, disimpan di kolom revoke_reason
+const (
+	RevokeLogout      = "logout"
+	RevokeLogoutAll   = "logout_all"
+	RevokeManual      = "revoked"
+	RevokeTokenReuse  = "refresh_token_reuse"
+	RevokeUserDeleted = "user_deleted"
+	RevokePassword    = "password_changed"
+	RevokeAccountLink = "account_linked"
+	RevokeSuspended   = "user_suspended"
+	RevokeAdminReset  = "admin_password_reset"
+)
+
+// Session mewakili satu perangkat yang login; setiap login membuat session baru
+// sehingga login di perangkat lain tidak mengeluarkan perangkat yang sudah ada
+type Session struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:char(36);not null;index"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:char(36);not null;index"`
+	UserAgent      string     `json:"user_agent" gorm:"size:512"`
+	IPAddress      string     `json:"ip_address" gorm:"size:45"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"index"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason   string     `json:"revoke_reason,omitempty" gorm:"size:32"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Current menandai session yang dipakai request saat ini
+	Current bool `json:"current" gorm:"-"`
+}
+
+// RefreshToken menyimpan hash refresh token. Token yang sudah dipakai (UsedAt terisi)
+// tetap disimpan untuk mendeteksi pemakaian ulang token curian
+type RefreshToken struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:char(36);not null;index"`
+	SessionID      uuid.UUID  `json:"session_id" gorm:"type:char(36);not null;index"`
+	TokenHash      string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	UsedAt         *time.Time `json:"used_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// DeviceInfo adalah informasi perangkat dari request login/refresh
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// TokenPair dikembalikan saat login dan refresh
+type TokenPair struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"` // detik
+	SessionID    uuid.UUID `json:"session_id"`
+}
+
+// DTO: Refresh input
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func NewSession(userID uuid.UUID, device DeviceInfo, ttl time.Duration, now time.Time) *Session {
+	session := &Session{
+		ID:     uuid.New(),
+		UserID: userID,
+	}
+	session.Touch(device, ttl, now)
+	return session
+}
+
+// Touch memperbarui data perangkat dan memperpanjang masa berlaku session
+func (s *Session) Touch(device DeviceInfo, ttl time.Duration, now time.Time) {
+	s.UserAgent = truncate(device.UserAgent, maxUserAgentLen)
+	s.IPAddress = truncate(device.IPAddress, maxIPAddressLen)
+	s.LastSeenAt = now
+	s.ExpiresAt = now.Add(ttl)
+}
+
+// Active mengecek apakah session belum dicabut dan belum kedaluwarsa
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+func (s *Session) Revoke(reason string, now time.Time) {
+	if s.RevokedAt != nil {
+		return
+	}
+	s.RevokedAt = &now
+	s.RevokeReason = reason
+}
+
+// NewRefreshToken membuat refresh token baru; nilai asli hanya dikembalikan sekali
+// ke client, database hanya menyimpan hash-nya
+func NewRefreshToken(sessionID uuid.UUID, ttl time.Duration, now time.Time) (*RefreshToken, string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	raw := refreshTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	return &RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		TokenHash: HashToken(raw),
+		ExpiresAt: now.Add(ttl),
+	}, raw, nil
+}
+
+// Used mengecek apakah token sudah pernah ditukar
+func (t *RefreshToken) Used() bool {
+	return t.UsedAt != nil
+}
+
+func (t *RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// HashToken menghitung hash SHA-256 (hex) dari token rahasia
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
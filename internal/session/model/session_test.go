@@ -0,0 +1,70 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionTestSuite struct {
+	suite.Suite
+}
+
+func TestSessionSuite(t *testing.T) {
+	suite.Run(t, new(SessionTestSuite))
+}
+
+func (s *SessionTestSuite) TestSessionLifecycle() {
+	now := time.Now()
+	session := NewSession(uuid.New(), DeviceInfo{UserAgent: strings.Repeat("a", 600), IPAddress: "10.0.0.1"}, time.Hour, now)
+
+	s.Len(session.UserAgent, maxUserAgentLen)
+	s.Equal(now, session.LastSeenAt)
+	s.True(session.Active(now))
+	s.False(session.Active(now.Add(time.Hour)))
+
+	later := now.Add(30 * time.Minute)
+	session.Touch(DeviceInfo{UserAgent: "phone"}, time.Hour, later)
+	s.True(session.Active(now.Add(time.Hour)))
+	s.Equal("phone", session.UserAgent)
+
+	session.Revoke(RevokeLogout, later)
+	session.Revoke(RevokeTokenReuse, later.Add(time.Minute))
+	s.False(session.Active(later))
+	s.Equal(RevokeLogout, session.RevokeReason)
+}
+
+func (s *SessionTestSuite) TestNewRefreshToken() {
+	now := time.Now()
+	sessionID := uuid.New()
+
+	token, raw, err := NewRefreshToken(sessionID, time.Hour, now)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(raw, refreshTokenPrefix))
+	s.Equal(HashToken(raw), token.TokenHash)
+	s.NotContains(token.TokenHash, raw)
+	s.Equal(sessionID, token.SessionID)
+	s.False(token.Used())
+	s.False(token.Expired(now))
+	s.True(token.Expired(now.Add(time.Hour)))
+
+	other, otherRaw, err := NewRefreshToken(sessionID, time.Hour, now)
+	s.Require().NoError(err)
+	s.NotEqual(raw, otherRaw)
+	s.NotEqual(token.TokenHash, other.TokenHash)
+}
+
+func (s *SessionTestSuite) TestSigningKeyPeriod() {
+	rotation := 24 * time.Hour
+	t := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	start := KeyPeriod(t, rotation)
+	s.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), start)
+	s.Equal(start, KeyPeriod(t.Add(8*time.Hour), rotation))
+	s.Equal("rs256-"+strconv.FormatInt(start.Unix(), 10), SigningKeyID("RS256", start))
+	s.NotEqual(SigningKeyID("RS256", start), SigningKeyID("EdDSA", start))
+}
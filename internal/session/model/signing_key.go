@@ -0,0 +1,29 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SigningKey menyimpan key penanda tangan access token (berlaku untuk semua organisasi).
+// Private key dienkripsi dengan JWT_SECRET. ID dipakai sebagai kid dan ditentukan dari
+// periode rotasi sehingga beberapa instance yang merotasi bersamaan membuat key yang sama.
+type SigningKey struct {
+	ID          string    `json:"kid" gorm:"size:64;primary_key"`
+	Algorithm   string    `json:"alg" gorm:"size:16;not null"`
+	PrivateKey  string    `json:"-" gorm:"type:text;not null"`
+	ActivatesAt time.Time `json:"activates_at" gorm:"index"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// KeyPeriod menghitung awal periode rotasi untuk waktu t
+func KeyPeriod(t time.Time, rotation time.Duration) time.Time {
+	return t.Truncate(rotation).UTC()
+}
+
+// SigningKeyID membuat kid untuk key pada periode yang dimulai di start
+func SigningKeyID(algorithm string, start time.Time) string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(algorithm), start.Unix())
+}
@@ -0,0 +1,187 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"booking/internal/session/model"
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRevocationSyncInterval adalah jeda maksimum sampai pencabutan dari instance lain
+	// berlaku di instance ini
+	DefaultRevocationSyncInterval = 2 * time.Second
+
+	// revocationSyncOverlap mengambil ulang sebagian entri lama agar entri dari instance
+	// dengan jam yang sedikit berbeda tidak terlewat
+	revocationSyncOverlap = 5 * time.Second
+	// revocationMaxStaleness: jika daftar tidak tersinkron selama ini, token ditolak karena
+	// pencabutan dari instance lain mungkin belum diketahui
+	revocationMaxStaleness = time.Minute
+)
+
+var ErrRevocationListStale = errors.New("token revocation list is not up to date")
+
+// RevocationStore adalah penyimpanan bersama daftar pencabutan (Redis)
+type RevocationStore interface {
+	AddRevocations(ctx context.Context, members []string, at time.Time, retention time.Duration) error
+	RevocationsSince(ctx context.Context, since time.Time) (map[string]time.Time, error)
+}
+
+// RevocationList adalah salinan lokal daftar pencabutan access token sehingga validasi token
+// tidak perlu ke Redis di setiap request. Entri berupa token (jti), session, atau user
+// (semua token user yang diterbitkan sebelum waktu pencabutan, misalnya setelah role
+// berubah). Entri hanya disimpan selama masa berlaku access token.
+type RevocationList struct {
+	store     RevocationStore
+	logger    logger.Logger
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+
+	mu       sync.RWMutex
+	entries  map[string]time.Time
+	syncedAt time.Time
+}
+
+func NewRevocationList(store RevocationStore, logger logger.Logger, accessTTL, interval time.Duration) *RevocationList {
+	if accessTTL <= 0 {
+		accessTTL = model.DefaultAccessTTL
+	}
+	if interval <= 0 {
+		interval = DefaultRevocationSyncInterval
+	}
+
+	return &RevocationList{
+		store:     store,
+		logger:    logger,
+		retention: accessTTL + time.Minute,
+		interval:  interval,
+		now:       time.Now,
+		entries:   map[string]time.Time{},
+	}
+}
+
+func tokenEntry(tokenID string) string     { return "t:" + tokenID }
+func sessionEntry(sessionID string) string { return "s:" + sessionID }
+func userEntry(userID string) string       { return "u:" + userID }
+
+// RevokeSessions mencabut semua access token dari session tertentu
+func (l *RevocationList) RevokeSessions(ctx context.Context, sessionIDs ...string) error {
+	members := make([]string, len(sessionIDs))
+	for i, id := range sessionIDs {
+		members[i] = sessionEntry(id)
+	}
+	return l.add(ctx, members)
+}
+
+// RevokeToken mencabut satu access token berdasarkan jti
+func (l *RevocationList) RevokeToken(ctx context.Context, tokenID string) error {
+	return l.add(ctx, []string{tokenEntry(tokenID)})
+}
+
+// RevokeUsers mencabut semua access token user yang diterbitkan sampai detik ini; session
+// tetap aktif sehingga client cukup refresh untuk mendapat claims terbaru
+func (l *RevocationList) RevokeUsers(ctx context.Context, userIDs ...string) error {
+	members := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = userEntry(id)
+	}
+	return l.add(ctx, members)
+}
+
+func (l *RevocationList) add(ctx context.Context, members []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	now := l.now()
+
+	// dicatat lokal lebih dulu agar langsung berlaku di instance ini
+	l.mu.Lock()
+	for _, m := range members {
+		l.entries[m] = now
+	}
+	l.mu.Unlock()
+
+	return l.store.AddRevocations(ctx, members, now, l.retention)
+}
+
+// Check mengecek claims terhadap salinan lokal tanpa akses jaringan
+func (l *RevocationList) Check(claims *jwt.Claims) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.now().Sub(l.syncedAt) > revocationMaxStaleness {
+		return ErrRevocationListStale
+	}
+	if _, ok := l.entries[sessionEntry(claims.SessionID)]; ok {
+		return ErrSessionRevoked
+	}
+	if _, ok := l.entries[tokenEntry(claims.ID)]; ok {
+		return ErrSessionRevoked
+	}
+	// iat berpresisi detik sehingga token yang diterbitkan pada detik yang sama dengan
+	// pencabutan ikut ditolak; client cukup refresh lagi setelah detik tersebut lewat
+	if revokedAt, ok := l.entries[userEntry(claims.UserID)]; ok {
+		if claims.IssuedAt == nil || !claims.IssuedAt.Time.After(revokedAt.Truncate(time.Second)) {
+			return ErrSessionRevoked
+		}
+	}
+	return nil
+}
+
+// Sync mengambil entri baru dari store dan membuang entri yang sudah melewati retention
+func (l *RevocationList) Sync(ctx context.Context) error {
+	now := l.now()
+	l.mu.RLock()
+	since := l.syncedAt.Add(-revocationSyncOverlap)
+	l.mu.RUnlock()
+	if oldest := now.Add(-l.retention); since.Before(oldest) {
+		since = oldest
+	}
+
+	entries, err := l.store.RevocationsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for member, at := range entries {
+		if current, ok := l.entries[member]; !ok || at.After(current) {
+			l.entries[member] = at
+		}
+	}
+	for member, at := range l.entries {
+		if now.Sub(at) > l.retention {
+			delete(l.entries, member)
+		}
+	}
+	l.syncedAt = now
+	return nil
+}
+
+// Start menyinkronkan daftar secara berkala sampai ctx dibatalkan
+func (l *RevocationList) Start(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Sync(ctx); err != nil {
+				l.logger.WithFields(logrus.Fields{
+					"error": err.Error(),
+				}).Warn("Gagal menyinkronkan daftar pencabutan token")
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryRevocationStore meniru sorted set Redis yang dipakai bersama beberapa instance
+type memoryRevocationStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	err     error
+}
+
+func (m *memoryRevocationStore) AddRevocations(ctx context.Context, members []string, at time.Time, retention time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	for _, member := range members {
+		m.entries[member] = at
+	}
+	return nil
+}
+
+func (m *memoryRevocationStore) RevocationsSince(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := map[string]time.Time{}
+	for member, at := range m.entries {
+		if !at.Before(since) {
+			result[member] = at
+		}
+	}
+	return result, nil
+}
+
+type RevocationListTestSuite struct {
+	suite.Suite
+	now   time.Time
+	store *memoryRevocationStore
+}
+
+func TestRevocationListSuite(t *testing.T) {
+	suite.Run(t, new(RevocationListTestSuite))
+}
+
+func (s *RevocationListTestSuite) SetupTest() {
+	s.now = time.Unix(1790000000, 0)
+	s.store = &memoryRevocationStore{entries: map[string]time.Time{}}
+}
+
+// list membuat satu instance yang berbagi store dan jam dengan instance lain di test
+func (s *RevocationListTestSuite) list() *RevocationList {
+	list := NewRevocationList(s.store, logger.NewLogger(), 15*time.Minute, time.Second)
+	list.now = func() time.Time { return s.now }
+	s.Require().NoError(list.Sync(context.Background()))
+	return list
+}
+
+func (s *RevocationListTestSuite) claims(issuedAt time.Time) *jwt.Claims {
+	return &jwt.Claims{
+		UserID:    "user-1",
+		SessionID: "session-1",
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:       "token-1",
+			IssuedAt: gojwt.NewNumericDate(issuedAt),
+		},
+	}
+}
+
+func (s *RevocationListTestSuite) TestRevokeSessionAppliesLocallyAndAfterSync() {
+	local, other := s.list(), s.list()
+	claims := s.claims(s.now)
+	s.NoError(other.Check(claims))
+
+	s.Require().NoError(local.RevokeSessions(context.Background(), "session-1"))
+	s.ErrorIs(local.Check(claims), ErrSessionRevoked)
+	// instance lain baru tahu setelah sinkronisasi berikutnya
+	s.NoError(other.Check(claims))
+
+	s.now = s.now.Add(time.Second)
+	s.Require().NoError(other.Sync(context.Background()))
+	s.ErrorIs(other.Check(claims), ErrSessionRevoked)
+}
+
+func (s *RevocationListTestSuite) TestRevokeToken() {
+	list := s.list()
+	s.Require().NoError(list.RevokeToken(context.Background(), "token-1"))
+	s.ErrorIs(list.Check(s.claims(s.now)), ErrSessionRevoked)
+
+	other := s.claims(s.now)
+	other.ID = "token-2"
+	s.NoError(list.Check(other))
+}
+
+func (s *RevocationListTestSuite) TestRevokeUserOnlyRejectsOlderTokens() {
+	list := s.list()
+	old := s.claims(s.now.Add(-time.Minute))
+
+	s.now = s.now.Add(500 * time.Millisecond)
+	s.Require().NoError(list.RevokeUsers(context.Background(), "user-1"))
+	s.ErrorIs(list.Check(old), ErrSessionRevoked)
+
+	// token pada detik yang sama tidak bisa dibedakan dari token sebelum pencabutan
+	sameSecond := s.claims(s.now.Truncate(time.Second))
+	sameSecond.ID = "token-2"
+	s.ErrorIs(list.Check(sameSecond), ErrSessionRevoked)
+
+	// token hasil refresh pada detik berikutnya diterima
+	refreshed := s.claims(s.now.Truncate(time.Second).Add(time.Second))
+	refreshed.ID = "token-3"
+	s.NoError(list.Check(refreshed))
+}
+
+func (s *RevocationListTestSuite) TestEntriesExpireAfterRetention() {
+	list := s.list()
+	s.Require().NoError(list.RevokeSessions(context.Background(), "session-1"))
+
+	s.now = s.now.Add(20 * time.Minute)
+	s.Require().NoError(list.Sync(context.Background()))
+	s.Empty(list.entries)
+}
+
+func (s *RevocationListTestSuite) TestStaleListRejectsTokens() {
+	list := s.list()
+	s.store.err = errors.New("redis down")
+
+	s.now = s.now.Add(30 * time.Second)
+	s.Error(list.Sync(context.Background()))
+	s.NoError(list.Check(s.claims(s.now)))
+
+	s.now = s.now.Add(time.Minute)
+	s.ErrorIs(list.Check(s.claims(s.now)), ErrRevocationListStale)
+
+	s.store.err = nil
+	s.Require().NoError(list.Sync(context.Background()))
+	s.NoError(list.Check(s.claims(s.now)))
+}
@@ -0,0 +1,126 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/session/model"
+	"booking/pkg/response"
+	errs "booking/shared/errors"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	sessionService SessionServiceInterface
+}
+
+func NewSessionHandler(sessionService SessionServiceInterface) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// Device mengambil informasi perangkat dari request untuk dicatat di session
+func Device(c echo.Context) model.DeviceInfo {
+	return model.DeviceInfo{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+}
+
+// Refresh menukar refresh token dengan access token dan refresh token baru
+func (h *SessionHandler) Refresh(c echo.Context) error {
+	var input model.RefreshInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	tokens, err := h.sessionService.Refresh(c.Request().Context(), input.RefreshToken, Device(c))
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrRefreshTokenReused) || errors.Is(err, ErrSessionRevoked) || errors.Is(err, errs.ErrAccountSuspended) {
+			return response.Unauthorized(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to refresh token", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Token refreshed successfully", tokens)
+}
+
+// JWKS menampilkan public key untuk memverifikasi access token (/.well-known/jwks.json).
+// Formatnya mengikuti RFC 7517 sehingga tidak dibungkus response standar.
+func (h *SessionHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.sessionService.JWKS())
+}
+
+// GetAll menampilkan session aktif (perangkat yang sedang login) milik user
+func (h *SessionHandler) GetAll(c echo.Context) error {
+	userID, sessionID, err := current(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	sessions, err := h.sessionService.GetByUser(c.Request().Context(), userID, sessionID)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get sessions", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// Revoke mencabut satu session milik user, misalnya perangkat yang hilang
+func (h *SessionHandler) Revoke(c echo.Context) error {
+	userID, _, err := current(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	if err := h.sessionService.Revoke(c.Request().Context(), userID, c.Param("id"), model.RevokeManual); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return response.NotFound(c, "session not found", err)
+		}
+		return response.InternalServerError(c, "failed to revoke session", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// Logout mencabut session yang sedang dipakai
+func (h *SessionHandler) Logout(c echo.Context) error {
+	userID, sessionID, err := current(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	if err := h.sessionService.Revoke(c.Request().Context(), userID, sessionID, model.RevokeLogout); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return response.InternalServerError(c, "logout failed", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Logout successful", nil)
+}
+
+// LogoutAll mencabut semua session user di semua perangkat
+func (h *SessionHandler) LogoutAll(c echo.Context) error {
+	userID, _, err := current(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	if err := h.sessionService.RevokeAll(c.Request().Context(), userID, model.RevokeLogoutAll); err != nil {
+		return response.InternalServerError(c, "logout failed", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Logged out from all devices", nil)
+}
+
+// current mengambil user_id dan session_id yang di-set AuthMiddleware
+func current(c echo.Context) (string, string, error) {
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return "", "", echo.NewHTTPError(http.StatusUnauthorized, "user ID not found in context")
+	}
+	sessionID, _ := c.Get("session_id").(string)
+	return userID, sessionID, nil
+}
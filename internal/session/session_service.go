@@ -0,0 +1,360 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/session/model"
+	userModel "booking/internal/user/model"
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+	errs "booking/shared/errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used; session revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session has been revoked or expired")
+)
+
+// SessionServiceInterface mendefinisikan kontrak untuk SessionService
+type SessionServiceInterface interface {
+	Start(ctx context.Context, userID uuid.UUID, device model.DeviceInfo) (*model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string, device model.DeviceInfo) (*model.TokenPair, error)
+	Validate(ctx context.Context, claims *jwt.Claims) error
+	GetByUser(ctx context.Context, userID string, currentSessionID string) ([]model.Session, error)
+	Revoke(ctx context.Context, userID string, sessionID string, reason string) error
+	RevokeAll(ctx context.Context, userID string, reason string) error
+	RevokeOthers(ctx context.Context, userID string, keepSessionID string, reason string) error
+	InvalidateAccessTokens(ctx context.Context, userIDs ...string) error
+	Impersonate(ctx context.Context, actorID uuid.UUID, actorSessionID string, targetUserID uuid.UUID) (*model.TokenPair, error)
+	JWKS() jwt.JWKS
+}
+
+type SessionService struct {
+	db          *gorm.DB
+	logger      logger.Logger
+	keys        *jwt.KeySet
+	revocations *RevocationList
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+func NewSessionService(db *gorm.DB, logger logger.Logger, keys *jwt.KeySet, revocations *RevocationList, accessTTL, refreshTTL time.Duration) *SessionService {
+	if keys == nil {
+		panic("jwt key set is required")
+	}
+	if accessTTL <= 0 {
+		accessTTL = model.DefaultAccessTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = model.DefaultRefreshTTL
+	}
+
+	return &SessionService{
+		db:          db,
+		logger:      logger,
+		keys:        keys,
+		revocations: revocations,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Start membuat session baru untuk perangkat yang login beserta pasangan token pertamanya
+func (s *SessionService) Start(ctx context.Context, userID uuid.UUID, device model.DeviceInfo) (*model.TokenPair, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := model.NewSession(userID, device, s.refreshTTL, now)
+	token, raw, err := model.NewRefreshToken(session.ID, s.refreshTTL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, session, raw)
+}
+
+// Refresh menukar refresh token dengan pasangan token baru (rotasi). Token lama yang
+// ditukar ulang dianggap bocor sehingga seluruh session dicabut.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string, device model.DeviceInfo) (*model.TokenPair, error) {
+	now := time.Now()
+	var (
+		session model.Session
+		raw     string
+		reused  bool
+	)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var token model.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&token, "token_hash = ?", model.HashToken(refreshToken)).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, "id = ?", token.SessionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if !session.Active(now) {
+			return ErrSessionRevoked
+		}
+
+		if token.Used() {
+			// dicabut di dalam transaksi yang di-commit; error dikembalikan setelahnya
+			reused = true
+			return s.revoke(tx, &session, model.RevokeTokenReuse, now)
+		}
+		if token.Expired(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		next, nextRaw, err := model.NewRefreshToken(session.ID, s.refreshTTL, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		// token lama yang sudah lewat masa berlakunya tidak perlu disimpan lagi
+		if err := tx.Where("session_id = ? AND expires_at < ?", session.ID, now).Delete(&model.RefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		session.Touch(device, s.refreshTTL, now)
+		raw = nextRaw
+		return tx.Save(&session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		s.logger.WithFields(logrus.Fields{
+			"user_id":    session.UserID,
+			"session_id": session.ID,
+			"ip_address": device.IPAddress,
+		}).Warn("Refresh token dipakai ulang, session dicabut")
+		if err := s.revocations.RevokeSessions(ctx, session.ID.String()); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.activeUser(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(user, &session, raw)
+}
+
+// Validate memastikan session dan token dari access token belum dicabut. Pengecekan memakai
+// salinan lokal daftar pencabutan sehingga tidak ada akses Redis/database per request.
+func (s *SessionService) Validate(ctx context.Context, claims *jwt.Claims) error {
+	if claims.SessionID == "" {
+		return ErrSessionRevoked
+	}
+	return s.revocations.Check(claims)
+}
+
+// GetByUser mengambil session aktif milik user, terbaru dipakai lebih dulu
+func (s *SessionService) GetByUser(ctx context.Context, userID string, currentSessionID string) ([]model.Session, error) {
+	sessions := []model.Session{}
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == currentSessionID
+	}
+	return sessions, nil
+}
+
+// Revoke mencabut satu session milik user
+func (s *SessionService) Revoke(ctx context.Context, userID string, sessionID string, reason string) error {
+	now := time.Now()
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var session model.Session
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", sessionID, userID).
+			First(&session).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSessionNotFound
+			}
+			return err
+		}
+		if !session.Active(now) {
+			return ErrSessionNotFound
+		}
+		return s.revoke(tx, &session, reason, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.revocations.RevokeSessions(ctx, sessionID)
+}
+
+// RevokeAll mencabut semua session aktif milik user ("log out everywhere")
+func (s *SessionService) RevokeAll(ctx context.Context, userID string, reason string) error {
+	return s.revokeMany(ctx, userID, "", reason)
+}
+
+// RevokeOthers mencabut semua session user kecuali session yang sedang dipakai
+func (s *SessionService) RevokeOthers(ctx context.Context, userID string, keepSessionID string, reason string) error {
+	return s.revokeMany(ctx, userID, keepSessionID, reason)
+}
+
+func (s *SessionService) revokeMany(ctx context.Context, userID string, keepSessionID string, reason string) error {
+	now := time.Now()
+	var ids []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		if keepSessionID != "" {
+			query = query.Where("id <> ?", keepSessionID)
+		}
+		if err := query.Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err := tx.Model(&model.Session{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"revoked_at": now, "revoke_reason": reason}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("session_id IN ?", ids).Delete(&model.RefreshToken{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.revocations.RevokeSessions(ctx, ids...)
+}
+
+// InvalidateAccessTokens menolak access token user yang sudah diterbitkan (misalnya setelah
+// role atau 2FA berubah) tanpa mencabut session; client refresh untuk mendapat claims baru
+func (s *SessionService) InvalidateAccessTokens(ctx context.Context, userIDs ...string) error {
+	if err := s.revocations.RevokeUsers(ctx, userIDs...); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_ids": userIDs,
+			"error":    err.Error(),
+		}).Error("Gagal mencabut access token user")
+		return err
+	}
+	return nil
+}
+
+// revoke menandai session dicabut dan menghapus semua refresh token-nya
+func (s *SessionService) revoke(tx *gorm.DB, session *model.Session, reason string, now time.Time) error {
+	session.Revoke(reason, now)
+	if err := tx.Save(session).Error; err != nil {
+		return err
+	}
+	return tx.Where("session_id = ?", session.ID).Delete(&model.RefreshToken{}).Error
+}
+
+// Impersonate membuat access token untuk target atas nama admin (claim "act"). Token tidak
+// punya refresh token dan memakai session admin, sehingga ikut dicabut saat admin logout.
+func (s *SessionService) Impersonate(ctx context.Context, actorID uuid.UUID, actorSessionID string, targetUserID uuid.UUID) (*model.TokenPair, error) {
+	sessionID, err := uuid.Parse(actorSessionID)
+	if err != nil {
+		return nil, ErrSessionRevoked
+	}
+	user, err := s.activeUser(ctx, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.accessToken(user, sessionID, &jwt.Actor{Subject: actorID.String()})
+	if err != nil {
+		return nil, err
+	}
+	return &model.TokenPair{
+		AccessToken: accessToken,
+		TokenType:   model.TokenType,
+		ExpiresIn:   int(s.accessTTL.Seconds()),
+		SessionID:   sessionID,
+	}, nil
+}
+
+// activeUser memuat user terbaru untuk claims; akun yang dinonaktifkan tidak mendapat token
+func (s *SessionService) activeUser(ctx context.Context, userID uuid.UUID) (*userModel.User, error) {
+	var user userModel.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, errs.ErrAccountSuspended
+	}
+	return &user, nil
+}
+
+func (s *SessionService) accessToken(user *userModel.User, sessionID uuid.UUID, actor *jwt.Actor) (string, error) {
+	return jwt.GenerateToken(jwt.Claims{
+		UserID:         user.ID.String(),
+		SessionID:      sessionID.String(),
+		OrganizationID: user.OrganizationID.String(),
+		Role:           string(user.Role),
+		TwoFactor:      user.TwoFactorEnabled(),
+		Actor:          actor,
+	}, s.keys, s.accessTTL)
+}
+
+// issue membuat access token dengan claims user terbaru (organisasi, role, status 2FA)
+func (s *SessionService) issue(user *userModel.User, session *model.Session, refreshToken string) (*model.TokenPair, error) {
+	accessToken, err := s.accessToken(user, session.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    model.TokenType,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+		SessionID:    session.ID,
+	}, nil
+}
+
+// JWKS mengembalikan public key penanda tangan access token untuk service lain
+func (s *SessionService) JWKS() jwt.JWKS {
+	return s.keys.JWKS()
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Blackout adalah rentang tanggal saat space tidak bisa dibooking.
+// StartDate dan EndDate memakai jam check-in (14:00) dan check-out (12:00) seperti booking.
+type Blackout struct {
+	ID        uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	SpaceID   uuid.UUID `json:"space_id" gorm:"type:char(36);not null;index"`
+	StartDate time.Time `json:"start_date" gorm:"not null"`
+	EndDate   time.Time `json:"end_date" gorm:"not null"`
+	Reason    string    `json:"reason" gorm:"size:255"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DTO: Create blackout input, tanggal dalam format YYYY-MM-DD
+type CreateBlackoutInput struct {
+	StartDate string `json:"start_date" validate:"required"`
+	EndDate   string `json:"end_date" validate:"required"`
+	Reason    string `json:"reason" validate:"max=255"`
+}
+
+func NewBlackout(spaceID uuid.UUID, input CreateBlackoutInput) (*Blackout, error) {
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start_date format. Use YYYY-MM-DD")
+	}
+	endDate, err := time.Parse("2006-01-02", input.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end_date format. Use YYYY-MM-DD")
+	}
+	if !endDate.After(startDate) {
+		return nil, errors.New("end_date must be after start_date")
+	}
+
+	return &Blackout{
+		ID:        uuid.New(),
+		SpaceID:   spaceID,
+		StartDate: time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 14, 0, 0, 0, time.Local),
+		EndDate:   time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 12, 0, 0, 0, time.Local),
+		Reason:    input.Reason,
+	}, nil
+}
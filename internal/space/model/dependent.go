@@ -0,0 +1,23 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SpaceRef adalah ringkasan space yang masih bergantung pada data lain
+type SpaceRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// DependentSpacesError dikembalikan ketika data tidak bisa dihapus karena masih dipakai space
+type DependentSpacesError struct {
+	Resource string
+	Spaces   []SpaceRef
+}
+
+func (e *DependentSpacesError) Error() string {
+	return fmt.Sprintf("%s is still used by %d space(s); reassign them first", e.Resource, len(e.Spaces))
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SRID 4326 (WGS 84) dipakai untuk semua koordinat space
+const (
+	SRID          = 4326
+	earthRadiusKm = 6371.0
+	kmPerDegree   = 111.32
+	MaxRadiusKm   = 500
+
+	// GeomFromTextSQL membentuk geometri dari WKT long-lat; argumennya WKT dan SRID
+	GeomFromTextSQL = "ST_GeomFromText(?, ?, 'axis-order=long-lat')"
+)
+
+// Address adalah alamat terstruktur space
+type Address struct {
+	Street     string `json:"street" gorm:"size:255"`
+	City       string `json:"city" gorm:"size:100;index"`
+	Province   string `json:"province" gorm:"size:100"`
+	PostalCode string `json:"postal_code" gorm:"size:20"`
+	Country    string `json:"country" gorm:"size:2"`
+}
+
+// Point adalah kolom POINT MySQL. Nilai ditulis dalam urutan long-lat dan tidak pernah
+// dibaca; Space membentuknya ulang dari latitude/longitude setelah query (AfterFind).
+type Point struct {
+	Lng float64
+	Lat float64
+}
+
+func (Point) GormDataType() string {
+	return "point"
+}
+
+func (p Point) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return clause.Expr{
+		SQL:  GeomFromTextSQL,
+		Vars: []interface{}{p.WKT(), SRID},
+	}
+}
+
+func (p Point) WKT() string {
+	return fmt.Sprintf("POINT(%f %f)", p.Lng, p.Lat)
+}
+
+// ValidateCoordinates memastikan latitude dan longitude diisi bersamaan dan dalam rentang
+func ValidateCoordinates(lat, lng *float64) error {
+	if (lat == nil) != (lng == nil) {
+		return errors.New("latitude and longitude must be provided together")
+	}
+	if lat == nil {
+		return nil
+	}
+	if *lat < -90 || *lat > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if *lng < -180 || *lng > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+// BoundingBox adalah area peta dalam derajat
+type BoundingBox struct {
+	MinLng float64
+	MinLat float64
+	MaxLng float64
+	MaxLat float64
+}
+
+// Polygon menghasilkan WKT polygon dalam urutan long-lat
+func (b BoundingBox) Polygon() string {
+	return fmt.Sprintf("POLYGON((%[1]f %[2]f, %[3]f %[2]f, %[3]f %[4]f, %[1]f %[4]f, %[1]f %[2]f))",
+		b.MinLng, b.MinLat, b.MaxLng, b.MaxLat)
+}
+
+func (b BoundingBox) Validate() error {
+	if b.MinLat < -90 || b.MaxLat > 90 || b.MinLng < -180 || b.MaxLng > 180 {
+		return errors.New("bbox is out of range")
+	}
+	if b.MinLat >= b.MaxLat || b.MinLng >= b.MaxLng {
+		return errors.New("bbox must be min_lng,min_lat,max_lng,max_lat")
+	}
+	return nil
+}
+
+// RadiusBox menghitung bounding box di sekitar titik untuk prefilter spatial index.
+// Mengembalikan false jika area melewati kutub atau garis 180 derajat sehingga box tidak bisa dipakai.
+func RadiusBox(lat, lng, radiusKm float64) (BoundingBox, bool) {
+	dLat := radiusKm / kmPerDegree
+	if lat-dLat < -90 || lat+dLat > 90 {
+		return BoundingBox{}, false
+	}
+	dLng := radiusKm / (kmPerDegree * math.Cos(lat*math.Pi/180))
+	if lng-dLng < -180 || lng+dLng > 180 {
+		return BoundingBox{}, false
+	}
+	return BoundingBox{MinLng: lng - dLng, MinLat: lat - dLat, MaxLng: lng + dLng, MaxLat: lat + dLat}, true
+}
+
+// DistanceKm menghitung jarak haversine antara dua titik
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
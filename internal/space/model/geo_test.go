@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GeoTestSuite struct {
+	suite.Suite
+}
+
+func TestGeoSuite(t *testing.T) {
+	suite.Run(t, new(GeoTestSuite))
+}
+
+func (s *GeoTestSuite) TestValidateCoordinates() {
+	lat, lng, bad := -8.65, 115.13, 200.0
+	s.NoError(ValidateCoordinates(nil, nil))
+	s.NoError(ValidateCoordinates(&lat, &lng))
+	s.Error(ValidateCoordinates(&lat, nil))
+	s.Error(ValidateCoordinates(&bad, &lng))
+	s.Error(ValidateCoordinates(&lat, &bad))
+}
+
+func (s *GeoTestSuite) TestRadiusBoxContainsRadius() {
+	// Denpasar
+	lat, lng := -8.65, 115.22
+	box, ok := RadiusBox(lat, lng, 10)
+	s.Require().True(ok)
+
+	s.InDelta(10, DistanceKm(lat, lng, box.MaxLat, lng), 0.1)
+	s.InDelta(10, DistanceKm(lat, lng, lat, box.MaxLng), 0.1)
+	s.NoError(box.Validate())
+}
+
+func (s *GeoTestSuite) TestRadiusBoxNearAntimeridian() {
+	_, ok := RadiusBox(0, 179.99, 50)
+	s.False(ok)
+}
+
+func (s *GeoTestSuite) TestDistanceKm() {
+	// Jakarta - Bandung sekitar 117 km
+	s.InDelta(117, DistanceKm(-6.2, 106.816666, -6.914744, 107.609810), 3)
+}
+
+func (s *GeoTestSuite) TestPolygon() {
+	box := BoundingBox{MinLng: 115, MinLat: -9, MaxLng: 116, MaxLat: -8}
+	s.Equal("POLYGON((115.000000 -9.000000, 116.000000 -9.000000, 116.000000 -8.000000, 115.000000 -8.000000, 115.000000 -9.000000))", box.Polygon())
+}
+
+func (s *GeoTestSuite) TestSearchFilterGeo() {
+	lat, lng := -8.65, 115.22
+	filter, err := SearchInput{Lat: &lat, Lng: &lng}.Filter()
+	s.Require().NoError(err)
+	s.Equal(float64(DefaultRadiusKm), filter.RadiusKm)
+	s.Equal(SortDistance, filter.Sort)
+
+	_, err = SearchInput{Sort: SortDistance}.Filter()
+	s.Error(err)
+
+	filter, err = SearchInput{BBox: "115,-9,116,-8"}.Filter()
+	s.Require().NoError(err)
+	s.Equal(&BoundingBox{MinLng: 115, MinLat: -9, MaxLng: 116, MaxLat: -8}, filter.BBox)
+
+	_, err = SearchInput{BBox: "116,-9,115,-8"}.Filter()
+	s.Error(err)
+}
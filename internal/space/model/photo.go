@@ -0,0 +1,146 @@
+package model
+
+import (
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThumbnailSizes adalah lebar maksimal (px) setiap ukuran thumbnail
+var ThumbnailSizes = map[string]int{
+	"small":  160,
+	"medium": 480,
+	"large":  1024,
+}
+
+// Photo adalah foto galeri space; file asli dan thumbnail disimpan di Storage
+type Photo struct {
+	ID          uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	SpaceID     uuid.UUID `json:"space_id" gorm:"type:char(36);not null;index:idx_photos_space_position,priority:1"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null"`
+	ContentType string    `json:"content_type" gorm:"size:50;not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	Width       int       `json:"width" gorm:"not null"`
+	Height      int       `json:"height" gorm:"not null"`
+	Position    int       `json:"position" gorm:"not null;default:0;index:idx_photos_space_position,priority:2"`
+	IsCover     bool      `json:"is_cover" gorm:"not null;default:false"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// URL publik diisi oleh service dari Storage
+	URL        string            `json:"url" gorm:"-"`
+	Thumbnails map[string]string `json:"thumbnails" gorm:"-"`
+}
+
+type ReorderPhotosInput struct {
+	PhotoIDs []uuid.UUID `json:"photo_ids" validate:"required,min=1"`
+}
+
+// PublicPhoto adalah tampilan foto untuk katalog publik
+type PublicPhoto struct {
+	ID         uuid.UUID         `json:"id"`
+	URL        string            `json:"url"`
+	Thumbnails map[string]string `json:"thumbnails"`
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	IsCover    bool              `json:"is_cover"`
+}
+
+func NewPhoto(spaceID uuid.UUID, contentType string, size int64, width, height, position int) *Photo {
+	id := uuid.New()
+	return &Photo{
+		ID:          id,
+		SpaceID:     spaceID,
+		StorageKey:  path.Join("spaces", spaceID.String(), id.String(), "original"+extension(contentType)),
+		ContentType: contentType,
+		Size:        size,
+		Width:       width,
+		Height:      height,
+		Position:    position,
+	}
+}
+
+// ThumbnailKey adalah key storage untuk thumbnail ukuran tertentu
+func (p *Photo) ThumbnailKey(size string) string {
+	return path.Join(path.Dir(p.StorageKey), size+".jpg")
+}
+
+// Keys mengembalikan semua key storage milik foto (asli dan thumbnail)
+func (p *Photo) Keys() []string {
+	keys := []string{p.StorageKey}
+	for size := range ThumbnailSizes {
+		keys = append(keys, p.ThumbnailKey(size))
+	}
+	return keys
+}
+
+// SetURLs mengisi URL publik foto dan thumbnail
+func (p *Photo) SetURLs(url func(key string) string) {
+	p.URL = url(p.StorageKey)
+	p.Thumbnails = make(map[string]string, len(ThumbnailSizes))
+	for size := range ThumbnailSizes {
+		p.Thumbnails[size] = url(p.ThumbnailKey(size))
+	}
+}
+
+func (p *Photo) ToPublic() PublicPhoto {
+	return PublicPhoto{
+		ID:         p.ID,
+		URL:        p.URL,
+		Thumbnails: p.Thumbnails,
+		Width:      p.Width,
+		Height:     p.Height,
+		IsCover:    p.IsCover,
+	}
+}
+
+// ReorderPhotos mengatur ulang posisi sesuai urutan ids; ids harus berisi semua foto space
+func ReorderPhotos(photos []Photo, ids []uuid.UUID) bool {
+	if len(photos) != len(ids) {
+		return false
+	}
+	position := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		if _, dup := position[id]; dup {
+			return false
+		}
+		position[id] = i
+	}
+	for i := range photos {
+		pos, ok := position[photos[i].ID]
+		if !ok {
+			return false
+		}
+		photos[i].Position = pos
+	}
+	return true
+}
+
+// SetCover menjadikan satu foto sebagai cover dan melepas cover lainnya
+func SetCover(photos []Photo, id uuid.UUID) bool {
+	found := false
+	for i := range photos {
+		if photos[i].ID == id {
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+	for i := range photos {
+		photos[i].IsCover = photos[i].ID == id
+	}
+	return true
+}
+
+func extension(contentType string) string {
+	switch contentType {
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".jpg"
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type PhotoTestSuite struct {
+	suite.Suite
+}
+
+func TestPhotoSuite(t *testing.T) {
+	suite.Run(t, new(PhotoTestSuite))
+}
+
+func (s *PhotoTestSuite) photos(n int) []Photo {
+	photos := make([]Photo, n)
+	for i := range photos {
+		photos[i] = Photo{ID: uuid.New(), Position: i, IsCover: i == 0}
+	}
+	return photos
+}
+
+func (s *PhotoTestSuite) TestKeys() {
+	spaceID := uuid.New()
+	photo := NewPhoto(spaceID, "image/png", 10, 4, 4, 0)
+
+	s.True(strings.HasPrefix(photo.StorageKey, "spaces/"+spaceID.String()+"/"))
+	s.True(strings.HasSuffix(photo.StorageKey, "/original.png"))
+	s.Len(photo.Keys(), 1+len(ThumbnailSizes))
+
+	photo.SetURLs(func(key string) string { return "https://cdn/" + key })
+	s.Equal("https://cdn/"+photo.ThumbnailKey("small"), photo.Thumbnails["small"])
+}
+
+func (s *PhotoTestSuite) TestReorderPhotos() {
+	photos := s.photos(3)
+	ids := []uuid.UUID{photos[2].ID, photos[0].ID, photos[1].ID}
+
+	s.True(ReorderPhotos(photos, ids))
+	s.Equal(1, photos[0].Position)
+	s.Equal(2, photos[1].Position)
+	s.Equal(0, photos[2].Position)
+
+	s.False(ReorderPhotos(photos, ids[:2]))
+	s.False(ReorderPhotos(photos, []uuid.UUID{ids[0], ids[0], ids[1]}))
+}
+
+func (s *PhotoTestSuite) TestSetCover() {
+	photos := s.photos(3)
+
+	s.True(SetCover(photos, photos[2].ID))
+	s.False(photos[0].IsCover)
+	s.True(photos[2].IsCover)
+
+	s.False(SetCover(photos, uuid.New()))
+	s.True(photos[2].IsCover)
+}
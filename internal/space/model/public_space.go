@@ -0,0 +1,79 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// PublicSpace adalah tampilan space untuk katalog publik, tanpa field khusus admin
+type PublicSpace struct {
+	ID            uuid.UUID        `json:"id"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description"`
+	PricePerNight float64          `json:"price_per_night"`
+	MaxGuests     int              `json:"max_guests"`
+	Address       Address          `json:"address"`
+	Latitude      *float64         `json:"latitude"`
+	Longitude     *float64         `json:"longitude"`
+	DistanceKm    *float64         `json:"distance_km,omitempty"`
+	Category      *PublicCategory  `json:"category"`
+	Facilities    []PublicFacility `json:"facilities"`
+	CoverPhoto    *PublicPhoto     `json:"cover_photo"`
+	Photos        []PublicPhoto    `json:"photos"`
+
+	// Harga tampilan hasil konversi dari IDR, diisi jika query currency diberikan
+	Currency     string  `json:"currency,omitempty"`
+	DisplayPrice float64 `json:"display_price,omitempty"`
+}
+
+type PublicCategory struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+// PublicFacility juga dipakai sebagai baris hasil join space_facilities -> facilities
+type PublicFacility struct {
+	SpaceID uuid.UUID `json:"-"`
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+}
+
+func NewPublicSpace(space *Space, category *PublicCategory, facilities []PublicFacility) PublicSpace {
+	if facilities == nil {
+		facilities = []PublicFacility{}
+	}
+	return PublicSpace{
+		ID:            space.ID,
+		Name:          space.Name,
+		Description:   space.Description,
+		PricePerNight: space.PricePerNight,
+		MaxGuests:     space.MaxGuests,
+		Address:       space.Address,
+		Latitude:      space.Latitude,
+		Longitude:     space.Longitude,
+		DistanceKm:    space.DistanceKm,
+		Category:      category,
+		Facilities:    facilities,
+	}
+}
+
+// SetDisplayPrice mengisi harga tampilan dalam mata uang lain
+func (s *PublicSpace) SetDisplayPrice(currency string, amount float64) {
+	s.Currency = currency
+	s.DisplayPrice = amount
+}
+
+// SetPhotos mengisi galeri (sudah terurut) dan cover photo
+func (s *PublicSpace) SetPhotos(photos []PublicPhoto) {
+	if photos == nil {
+		photos = []PublicPhoto{}
+	}
+	s.Photos = photos
+	s.CoverPhoto = nil
+	for i := range photos {
+		if photos[i].IsCover {
+			s.CoverPhoto = &photos[i]
+			break
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SortNewest    = "newest"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortDistance  = "distance"
+
+	DefaultPerPage  = 20
+	MaxPerPage      = 100
+	DefaultRadiusKm = 10
+)
+
+// SearchInput adalah query string pencarian space; check_in/check_out dalam format YYYY-MM-DD,
+// bbox dalam format min_lng,min_lat,max_lng,max_lat
+type SearchInput struct {
+	Q          string   `query:"q"`
+	Category   string   `query:"category"`
+	Facilities string   `query:"facilities"`
+	MinPrice   float64  `query:"min_price"`
+	MaxPrice   float64  `query:"max_price"`
+	Guests     int      `query:"guests"`
+	CheckIn    string   `query:"check_in"`
+	CheckOut   string   `query:"check_out"`
+	Lat        *float64 `query:"lat"`
+	Lng        *float64 `query:"lng"`
+	RadiusKm   float64  `query:"radius_km"`
+	BBox       string   `query:"bbox"`
+	Sort       string   `query:"sort"`
+	Page       int      `query:"page"`
+	PerPage    int      `query:"per_page"`
+}
+
+// SearchFilter adalah SearchInput yang sudah divalidasi dan siap dipakai query
+type SearchFilter struct {
+	Query        string
+	CategorySlug string
+	CategoryIDs  []uuid.UUID // kategori dari CategorySlug beserta sub-kategorinya, diisi handler
+	FacilityIDs  []uuid.UUID
+	MinPrice     float64
+	MaxPrice     float64
+	Guests       int
+	CheckIn      *time.Time
+	CheckOut     *time.Time
+	Center       *Point
+	RadiusKm     float64
+	BBox         *BoundingBox
+	Sort         string
+	Page         int
+	PerPage      int
+}
+
+type SearchResult struct {
+	Data       []PublicSpace `json:"data"`
+	Page       int           `json:"page"`
+	PerPage    int           `json:"per_page"`
+	Total      int64         `json:"total"`
+	TotalPages int           `json:"total_pages"`
+}
+
+// Filter memvalidasi input dan mengisi nilai default
+func (in SearchInput) Filter() (*SearchFilter, error) {
+	filter := &SearchFilter{
+		Query:        strings.TrimSpace(in.Q),
+		CategorySlug: strings.TrimSpace(in.Category),
+		MinPrice:     in.MinPrice,
+		MaxPrice:     in.MaxPrice,
+		Guests:       in.Guests,
+		Sort:         in.Sort,
+		Page:         in.Page,
+		PerPage:      in.PerPage,
+	}
+
+	for _, raw := range strings.Split(in.Facilities, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("invalid facility ID: " + raw)
+		}
+		filter.FacilityIDs = append(filter.FacilityIDs, id)
+	}
+
+	if filter.MinPrice < 0 || filter.MaxPrice < 0 {
+		return nil, errors.New("price filter must not be negative")
+	}
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return nil, errors.New("min_price must not exceed max_price")
+	}
+	if filter.Guests < 0 {
+		return nil, errors.New("guests must not be negative")
+	}
+
+	if in.CheckIn != "" || in.CheckOut != "" {
+		if in.CheckIn == "" || in.CheckOut == "" {
+			return nil, errors.New("check_in and check_out must be provided together")
+		}
+		checkIn, err := time.Parse("2006-01-02", in.CheckIn)
+		if err != nil {
+			return nil, errors.New("invalid check_in format. Use YYYY-MM-DD")
+		}
+		checkOut, err := time.Parse("2006-01-02", in.CheckOut)
+		if err != nil {
+			return nil, errors.New("invalid check_out format. Use YYYY-MM-DD")
+		}
+		if !checkOut.After(checkIn) {
+			return nil, errors.New("check_out must be after check_in")
+		}
+		// Pakai jam check-in/check-out yang sama dengan booking
+		start := time.Date(checkIn.Year(), checkIn.Month(), checkIn.Day(), 14, 0, 0, 0, time.Local)
+		end := time.Date(checkOut.Year(), checkOut.Month(), checkOut.Day(), 12, 0, 0, 0, time.Local)
+		filter.CheckIn = &start
+		filter.CheckOut = &end
+	}
+
+	if in.Lat != nil || in.Lng != nil {
+		if err := ValidateCoordinates(in.Lat, in.Lng); err != nil {
+			return nil, err
+		}
+		filter.Center = &Point{Lng: *in.Lng, Lat: *in.Lat}
+		filter.RadiusKm = in.RadiusKm
+		if filter.RadiusKm == 0 {
+			filter.RadiusKm = DefaultRadiusKm
+		}
+		if filter.RadiusKm < 0 || filter.RadiusKm > MaxRadiusKm {
+			return nil, fmt.Errorf("radius_km must be between 0 and %d", MaxRadiusKm)
+		}
+	}
+
+	if in.BBox != "" {
+		bbox, err := parseBoundingBox(in.BBox)
+		if err != nil {
+			return nil, err
+		}
+		filter.BBox = bbox
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = SortNewest
+		if filter.Center != nil {
+			filter.Sort = SortDistance
+		}
+	case SortNewest, SortPriceAsc, SortPriceDesc:
+	case SortDistance:
+		if filter.Center == nil {
+			return nil, errors.New("sort by distance requires lat and lng")
+		}
+	default:
+		return nil, errors.New("invalid sort, use newest, price_asc, price_desc or distance")
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = DefaultPerPage
+	}
+	if filter.PerPage > MaxPerPage {
+		filter.PerPage = MaxPerPage
+	}
+	return filter, nil
+}
+
+func parseBoundingBox(raw string) (*BoundingBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("bbox must be min_lng,min_lat,max_lng,max_lat")
+	}
+	values := make([]float64, 4)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, errors.New("bbox must be min_lng,min_lat,max_lng,max_lat")
+		}
+		values[i] = v
+	}
+	bbox := &BoundingBox{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	if err := bbox.Validate(); err != nil {
+		return nil, err
+	}
+	return bbox, nil
+}
+
+func (f *SearchFilter) Offset() int {
+	return (f.Page - 1) * f.PerPage
+}
+
+func NewSearchResult(data []PublicSpace, filter *SearchFilter, total int64) *SearchResult {
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+	return &SearchResult{
+		Data:       data,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+}
+
+func TestSearchSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
+
+func (s *SearchTestSuite) TestFilterDefaults() {
+	filter, err := SearchInput{}.Filter()
+	s.Require().NoError(err)
+	s.Equal(SortNewest, filter.Sort)
+	s.Equal(1, filter.Page)
+	s.Equal(DefaultPerPage, filter.PerPage)
+	s.Nil(filter.CheckIn)
+	s.Zero(filter.Offset())
+}
+
+func (s *SearchTestSuite) TestFilterParsesInput() {
+	wifi, pool := uuid.New(), uuid.New()
+	filter, err := SearchInput{
+		Q:          " villa ",
+		Facilities: wifi.String() + ", " + pool.String(),
+		CheckIn:    "2026-12-24",
+		CheckOut:   "2026-12-26",
+		Sort:       SortPriceAsc,
+		Page:       3,
+		PerPage:    500,
+	}.Filter()
+	s.Require().NoError(err)
+	s.Equal("villa", filter.Query)
+	s.Equal([]uuid.UUID{wifi, pool}, filter.FacilityIDs)
+	s.Equal(time.Date(2026, 12, 24, 14, 0, 0, 0, time.Local), *filter.CheckIn)
+	s.Equal(time.Date(2026, 12, 26, 12, 0, 0, 0, time.Local), *filter.CheckOut)
+	s.Equal(MaxPerPage, filter.PerPage)
+	s.Equal(2*MaxPerPage, filter.Offset())
+}
+
+func (s *SearchTestSuite) TestFilterInvalid() {
+	cases := []SearchInput{
+		{Facilities: "wifi"},
+		{MinPrice: 500, MaxPrice: 100},
+		{CheckIn: "2026-12-24"},
+		{CheckIn: "2026-12-24", CheckOut: "2026-12-24"},
+		{Sort: "cheapest"},
+	}
+	for _, in := range cases {
+		_, err := in.Filter()
+		s.Error(err)
+	}
+}
+
+func (s *SearchTestSuite) TestNewSearchResult() {
+	filter := &SearchFilter{Page: 1, PerPage: 20}
+	result := NewSearchResult([]PublicSpace{}, filter, 41)
+	s.Equal(3, result.TotalPages)
+}
@@ -2,24 +2,53 @@ package model
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Space struct {
-	ID            uuid.UUID `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
-	CategoryID    uuid.UUID `json:"category_id" gorm:"type:char(36)"`
-	Name          string    `json:"name" gorm:"size:150"`
-	Description   string    `json:"description" gorm:"type:text"`
-	PricePerNight float64   `json:"price_per_night" gorm:"type:decimal(12,2)"`
-	IsActive      bool      `json:"is_active" gorm:"default:true"`
+	ID             uuid.UUID      `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
+	OrganizationID uuid.UUID      `json:"organization_id" gorm:"type:char(36);not null;index"`
+	CategoryID     uuid.UUID      `json:"category_id" gorm:"type:char(36)"`
+	OwnerID        *uuid.UUID     `json:"owner_id" gorm:"type:char(36);index"`
+	Name           string         `json:"name" gorm:"size:150;index:idx_spaces_search,class:FULLTEXT"`
+	Description    string         `json:"description" gorm:"type:text;index:idx_spaces_search,class:FULLTEXT"`
+	PricePerNight  float64        `json:"price_per_night" gorm:"type:decimal(12,2);index"`
+	MaxGuests      int            `json:"max_guests" gorm:"not null;default:1"`
+	Address        Address        `json:"address" gorm:"embedded"`
+	Latitude       *float64       `json:"latitude" gorm:"type:decimal(10,7)"`
+	Longitude      *float64       `json:"longitude" gorm:"type:decimal(10,7)"`
+	IsActive       bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Location diisi dari Latitude/Longitude untuk spatial index; (0,0) jika belum ada koordinat
+	Location Point `json:"-" gorm:"type:POINT SRID 4326;not null;default:(ST_SRID(POINT(0,0),4326));index:idx_spaces_location,class:SPATIAL;<-;->:false"`
+
+	// Harga tampilan hasil konversi dari IDR, diisi jika query currency diberikan
+	Currency     string  `json:"currency,omitempty" gorm:"-"`
+	DisplayPrice float64 `json:"display_price,omitempty" gorm:"-"`
+
+	// Fasilitas space, dimuat oleh SpaceService untuk response admin dan host
+	Facilities []PublicFacility `json:"facilities,omitempty" gorm:"-"`
+
+	// Jarak dari titik pencarian, hanya diisi oleh pencarian radius
+	DistanceKm *float64 `json:"distance_km,omitempty" gorm:"->;-:migration"`
 }
 
 type CreateSpaceInput struct {
-	CategoryID    uuid.UUID `json:"category_id" binding:"required"`
-	Name          string    `json:"name" binding:"required"`
-	Description   string    `json:"description" binding:"required"`
-	PricePerNight float64   `json:"price_per_night" binding:"required"`
+	CategoryID    uuid.UUID  `json:"category_id" binding:"required"`
+	Name          string     `json:"name" binding:"required"`
+	Description   string     `json:"description" binding:"required"`
+	PricePerNight float64    `json:"price_per_night" binding:"required"`
+	MaxGuests     int        `json:"max_guests"`
+	OwnerID       *uuid.UUID `json:"owner_id"`
+	Address       Address    `json:"address"`
+	Latitude      *float64   `json:"latitude"`
+	Longitude     *float64   `json:"longitude"`
 }
 
 func NewSpace(input CreateSpaceInput, categoryID uuid.UUID) (*Space, error) {
@@ -35,14 +64,55 @@ func NewSpace(input CreateSpaceInput, categoryID uuid.UUID) (*Space, error) {
 	if input.PricePerNight <= 0 {
 		return nil, errors.New("price must be greater than zero")
 	}
+	if input.MaxGuests < 0 {
+		return nil, errors.New("max guests must not be negative")
+	}
+	if input.MaxGuests == 0 {
+		input.MaxGuests = 1
+	}
+	if err := ValidateCoordinates(input.Latitude, input.Longitude); err != nil {
+		return nil, err
+	}
 	space := &Space{
 		ID:            uuid.New(),
 		CategoryID:    categoryID,
 		Name:          input.Name,
 		Description:   input.Description,
 		PricePerNight: input.PricePerNight,
+		MaxGuests:     input.MaxGuests,
+		OwnerID:       input.OwnerID,
+		Address:       input.Address,
 		IsActive:      true,
 	}
+	space.SetCoordinates(input.Latitude, input.Longitude)
 
 	return space, nil
 }
+
+// SetDisplayPrice mengisi harga tampilan dalam mata uang lain
+func (s *Space) SetDisplayPrice(currency string, amount float64) {
+	s.Currency = currency
+	s.DisplayPrice = amount
+}
+
+// SetCoordinates mengisi koordinat sekaligus kolom Location untuk spatial index
+func (s *Space) SetCoordinates(lat, lng *float64) {
+	s.Latitude = lat
+	s.Longitude = lng
+	if lat == nil || lng == nil {
+		s.Location = Point{}
+		return
+	}
+	s.Location = Point{Lng: *lng, Lat: *lat}
+}
+
+// AfterFind membentuk ulang Location yang tidak ikut dibaca agar Save tidak menimpanya dengan (0,0)
+func (s *Space) AfterFind(tx *gorm.DB) error {
+	s.SetCoordinates(s.Latitude, s.Longitude)
+	return nil
+}
+
+// IsOwnedBy mengecek apakah space dimiliki host tertentu
+func (s *Space) IsOwnedBy(userID uuid.UUID) bool {
+	return s.OwnerID != nil && *s.OwnerID == userID
+}
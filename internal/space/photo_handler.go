@@ -0,0 +1,117 @@
+package space
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	spaceModel "booking/internal/space/model"
+	"booking/pkg/imaging"
+	"booking/pkg/response"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PhotoHandler struct {
+	photoService PhotoServiceInterface
+}
+
+func NewPhotoHandler(photoService PhotoServiceInterface) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+	}
+}
+
+// Upload menerima multipart field "file" berisi gambar JPEG, PNG atau WebP
+func (h *PhotoHandler) Upload(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "file is required", err)
+	}
+	if fileHeader.Size > h.photoService.MaxSize() {
+		return response.Error(c, http.StatusRequestEntityTooLarge, ErrPhotoTooLarge.Error(), ErrPhotoTooLarge)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.BadRequest(c, "failed to open file", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.photoService.MaxSize()+1))
+	if err != nil {
+		return response.BadRequest(c, "failed to read file", err)
+	}
+
+	photo, err := h.photoService.Upload(c.Request().Context(), c.Param("id"), data)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrPhotoTooLarge), errors.Is(err, imaging.ErrImageTooLarge):
+			return response.Error(c, http.StatusRequestEntityTooLarge, err.Error(), err)
+		case errors.Is(err, imaging.ErrUnsupportedImage):
+			return response.Error(c, http.StatusUnsupportedMediaType, err.Error(), err)
+		}
+		return response.BadRequest(c, "failed to upload photo", err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Photo uploaded successfully", photo)
+}
+
+func (h *PhotoHandler) GetAll(c echo.Context) error {
+	photos, err := h.photoService.GetAll(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.InternalServerError(c, "failed to get photos", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Photos retrieved successfully", photos)
+}
+
+func (h *PhotoHandler) Reorder(c echo.Context) error {
+	var input spaceModel.ReorderPhotosInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	photos, err := h.photoService.Reorder(c.Request().Context(), c.Param("id"), input.PhotoIDs)
+	if err != nil {
+		if errors.Is(err, ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.BadRequest(c, "failed to reorder photos", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Photos reordered successfully", photos)
+}
+
+func (h *PhotoHandler) SetCover(c echo.Context) error {
+	photos, err := h.photoService.SetCover(c.Request().Context(), c.Param("id"), c.Param("photoId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSpaceNotFound):
+			return response.NotFound(c, "space not found", err)
+		case errors.Is(err, ErrPhotoNotFound):
+			return response.NotFound(c, "photo not found", err)
+		}
+		return response.BadRequest(c, "failed to set cover photo", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Cover photo updated successfully", photos)
+}
+
+func (h *PhotoHandler) Delete(c echo.Context) error {
+	if err := h.photoService.Delete(c.Request().Context(), c.Param("id"), c.Param("photoId")); err != nil {
+		switch {
+		case errors.Is(err, ErrSpaceNotFound):
+			return response.NotFound(c, "space not found", err)
+		case errors.Is(err, ErrPhotoNotFound):
+			return response.NotFound(c, "photo not found", err)
+		}
+		return response.BadRequest(c, "failed to delete photo", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Photo deleted successfully", nil)
+}
@@ -0,0 +1,238 @@
+package space
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	spaceModel "booking/internal/space/model"
+	"booking/pkg/imaging"
+	"booking/pkg/logger"
+	"booking/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPhotoNotFound = errors.New("photo not found")
+	ErrPhotoTooLarge = errors.New("photo exceeds the maximum upload size")
+)
+
+// PhotoServiceInterface mendefinisikan kontrak untuk PhotoService
+type PhotoServiceInterface interface {
+	Upload(ctx context.Context, spaceID string, data []byte) (*spaceModel.Photo, error)
+	GetAll(ctx context.Context, spaceID string) ([]spaceModel.Photo, error)
+	Reorder(ctx context.Context, spaceID string, photoIDs []uuid.UUID) ([]spaceModel.Photo, error)
+	SetCover(ctx context.Context, spaceID string, photoID string) ([]spaceModel.Photo, error)
+	Delete(ctx context.Context, spaceID string, photoID string) error
+	MaxSize() int64
+}
+
+type PhotoService struct {
+	db           *gorm.DB
+	logger       logger.Logger
+	storage      storage.Storage
+	spaceService SpaceServiceInterface
+	maxSize      int64
+}
+
+func NewPhotoService(db *gorm.DB, logger logger.Logger, storage storage.Storage, spaceService SpaceServiceInterface, maxSize int64) *PhotoService {
+	return &PhotoService{
+		db:           db,
+		logger:       logger,
+		storage:      storage,
+		spaceService: spaceService,
+		maxSize:      maxSize,
+	}
+}
+
+func (s *PhotoService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload memvalidasi gambar, menyimpan file asli beserta thumbnail, lalu mencatat foto di database.
+// Foto pertama sebuah space otomatis menjadi cover.
+func (s *PhotoService) Upload(ctx context.Context, spaceID string, data []byte) (*spaceModel.Photo, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrPhotoTooLarge
+	}
+	contentType, err := imaging.DetectContentType(data)
+	if err != nil {
+		return nil, err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&spaceModel.Photo{}).Where("space_id = ?", space.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	photo := spaceModel.NewPhoto(space.ID, contentType, int64(len(data)), bounds.Dx(), bounds.Dy(), int(count))
+	photo.IsCover = count == 0
+
+	if err := s.storage.Put(ctx, photo.StorageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+	for size, width := range spaceModel.ThumbnailSizes {
+		thumb, err := imaging.EncodeJPEG(imaging.Resize(img, width))
+		if err != nil {
+			s.removeFiles(ctx, photo)
+			return nil, err
+		}
+		if err := s.storage.Put(ctx, photo.ThumbnailKey(size), bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg"); err != nil {
+			s.removeFiles(ctx, photo)
+			return nil, err
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Create(photo).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"space_id": space.ID,
+			"error":    err.Error(),
+		}).Error("Gagal menyimpan foto space")
+		s.removeFiles(ctx, photo)
+		return nil, err
+	}
+
+	photo.SetURLs(s.storage.URL)
+	return photo, nil
+}
+
+// GetAll mengambil foto space; tabel photos tidak punya organization_id sehingga space
+// dicek lebih dulu lewat query yang dibatasi tenant
+func (s *PhotoService) GetAll(ctx context.Context, spaceID string) ([]spaceModel.Photo, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.findBySpace(ctx, space.ID)
+}
+
+// Reorder menyimpan urutan baru; photoIDs harus berisi semua foto space tersebut
+func (s *PhotoService) Reorder(ctx context.Context, spaceID string, photoIDs []uuid.UUID) ([]spaceModel.Photo, error) {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	photos, err := s.findBySpace(ctx, space.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !spaceModel.ReorderPhotos(photos, photoIDs) {
+		return nil, errors.New("photo_ids must list every photo of the space exactly once")
+	}
+
+	if err := s.savePositions(ctx, photos); err != nil {
+		return nil, err
+	}
+	return s.findBySpace(ctx, space.ID)
+}
+
+func (s *PhotoService) SetCover(ctx context.Context, spaceID string, photoID string) ([]spaceModel.Photo, error) {
+	id, err := uuid.Parse(photoID)
+	if err != nil {
+		return nil, ErrPhotoNotFound
+	}
+
+	photos, err := s.GetAll(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !spaceModel.SetCover(photos, id) {
+		return nil, ErrPhotoNotFound
+	}
+
+	if err := s.savePositions(ctx, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// Delete menghapus foto dan file-nya; jika foto adalah cover, foto berikutnya menjadi cover
+func (s *PhotoService) Delete(ctx context.Context, spaceID string, photoID string) error {
+	space, err := s.spaceService.GetByID(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	var photo spaceModel.Photo
+	if err := s.db.WithContext(ctx).First(&photo, "id = ? AND space_id = ?", photoID, space.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPhotoNotFound
+		}
+		return err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&photo).Error; err != nil {
+			return err
+		}
+		if !photo.IsCover {
+			return nil
+		}
+		var next spaceModel.Photo
+		err := tx.Where("space_id = ?", photo.SpaceID).Order("position").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_cover", true).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeFiles(ctx, &photo)
+	return nil
+}
+
+func (s *PhotoService) findBySpace(ctx context.Context, spaceID uuid.UUID) ([]spaceModel.Photo, error) {
+	var photos []spaceModel.Photo
+	if err := s.db.WithContext(ctx).Where("space_id = ?", spaceID).Order("position").Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	for i := range photos {
+		photos[i].SetURLs(s.storage.URL)
+	}
+	return photos, nil
+}
+
+func (s *PhotoService) savePositions(ctx context.Context, photos []spaceModel.Photo) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range photos {
+			if err := tx.Model(&photos[i]).Updates(map[string]interface{}{
+				"position": photos[i].Position,
+				"is_cover": photos[i].IsCover,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// removeFiles menghapus file foto dari storage; kegagalan hanya dicatat karena data sudah konsisten
+func (s *PhotoService) removeFiles(ctx context.Context, photo *spaceModel.Photo) {
+	for _, key := range photo.Keys() {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"photo_id": photo.ID,
+				"key":      key,
+				"error":    err.Error(),
+			}).Error("Gagal menghapus file foto")
+		}
+	}
+}
@@ -1,11 +1,20 @@
 package space
 
 import (
+	"errors"
 	"net/http"
 
 	categoryService "booking/internal/category"
+	currencyService "booking/internal/currency"
+	currencyModel "booking/internal/currency/model"
+	roleService "booking/internal/role"
+	roleModel "booking/internal/role/model"
 	spaceModel "booking/internal/space/model"
+	userService "booking/internal/user"
+	userModel "booking/internal/user/model"
 	"booking/pkg/response"
+	"booking/shared/constants"
+	"booking/shared/validate"
 
 	"github.com/labstack/echo/v4"
 )
@@ -13,12 +22,18 @@ import (
 type SpaceHandler struct {
 	spaceService    SpaceServiceInterface
 	categoryService categoryService.CategoryServiceInterface
+	currencyService currencyService.CurrencyServiceInterface
+	userService     userService.UserServiceInterface
+	roleService     roleService.RoleServiceInterface
 }
 
-func NewSpaceHandler(spaceService SpaceServiceInterface, categoryService categoryService.CategoryServiceInterface) *SpaceHandler {
+func NewSpaceHandler(spaceService SpaceServiceInterface, categoryService categoryService.CategoryServiceInterface, currencyService currencyService.CurrencyServiceInterface, userService userService.UserServiceInterface, roleService roleService.RoleServiceInterface) *SpaceHandler {
 	return &SpaceHandler{
 		spaceService:    spaceService,
 		categoryService: categoryService,
+		currencyService: currencyService,
+		userService:     userService,
+		roleService:     roleService,
 	}
 }
 
@@ -32,13 +47,17 @@ func (h *SpaceHandler) Create(c echo.Context) error {
 		return response.BadRequest(c, err.Error(), nil)
 	}
 
+	if err := h.resolveOwner(c, &input); err != nil {
+		return response.BadRequest(c, err.Error(), err)
+	}
+
 	// Get category from database using CategoryService
-	category, err := h.categoryService.GetByID(input.CategoryID.String())
+	category, err := h.categoryService.GetByID(c.Request().Context(), input.CategoryID.String())
 	if err != nil {
 		return response.NotFound(c, "category not found", err)
 	}
 
-	space, err := h.spaceService.Create(input, category)
+	space, err := h.spaceService.Create(c.Request().Context(), input, category)
 	if err != nil {
 		return response.BadRequest(c, "failed to create space", err)
 	}
@@ -47,21 +66,147 @@ func (h *SpaceHandler) Create(c echo.Context) error {
 }
 
 func (h *SpaceHandler) GetAll(c echo.Context) error {
-	spaces, err := h.spaceService.GetAll()
+	spaces, err := h.spaceService.GetAll(c.Request().Context())
 	if err != nil {
 		return response.InternalServerError(c, "failed to get spaces", err)
 	}
 
+	rate, err := h.displayRate(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid currency", err)
+	}
+	if rate != nil {
+		for i := range spaces {
+			spaces[i].SetDisplayPrice(rate.Currency, rate.Convert(spaces[i].PricePerNight))
+		}
+	}
+
 	return response.Success(c, http.StatusOK, "Spaces retrieved successfully", spaces)
 }
 
 func (h *SpaceHandler) GetByID(c echo.Context) error {
 	id := c.Param("id")
-	space, err := h.spaceService.GetByID(id)
+	space, err := h.spaceService.GetByID(c.Request().Context(), id)
 	if err != nil {
 		return response.NotFound(c, "space not found", err)
 	}
 
+	rate, err := h.displayRate(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid currency", err)
+	}
+	if rate != nil {
+		space.SetDisplayPrice(rate.Currency, rate.Convert(space.PricePerNight))
+	}
+
+	return response.Success(c, http.StatusOK, "Space retrieved successfully", space)
+}
+
+// GetMine menampilkan space milik host yang login
+func (h *SpaceHandler) GetMine(c echo.Context) error {
+	user, ok := c.Get("principal").(*userModel.Principal)
+	if !ok || user == nil {
+		return response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	spaces, err := h.spaceService.GetByOwner(c.Request().Context(), user.UserID)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get spaces", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Spaces retrieved successfully", spaces)
+}
+
+// GetPublicAll menampilkan katalog space aktif untuk publik
+func (h *SpaceHandler) GetPublicAll(c echo.Context) error {
+	spaces, err := h.spaceService.GetPublic(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get spaces", err)
+	}
+
+	rate, err := h.displayRate(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid currency", err)
+	}
+	if rate != nil {
+		for i := range spaces {
+			spaces[i].SetDisplayPrice(rate.Currency, rate.Convert(spaces[i].PricePerNight))
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Spaces retrieved successfully", spaces)
+}
+
+// Search mencari space di katalog publik dengan filter, sorting dan paginasi
+func (h *SpaceHandler) Search(c echo.Context) error {
+	var input spaceModel.SearchInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+	return h.search(c, input)
+}
+
+// GetByCategory menampilkan space aktif pada kategori :slug termasuk sub-kategorinya,
+// dengan filter dan paginasi yang sama seperti pencarian
+func (h *SpaceHandler) GetByCategory(c echo.Context) error {
+	var input spaceModel.SearchInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+	input.Category = c.Param("slug")
+	return h.search(c, input)
+}
+
+func (h *SpaceHandler) search(c echo.Context, input spaceModel.SearchInput) error {
+	filter, err := input.Filter()
+	if err != nil {
+		return response.BadRequest(c, err.Error(), err)
+	}
+
+	if filter.CategorySlug != "" {
+		category, err := h.categoryService.GetBySlug(c.Request().Context(), filter.CategorySlug)
+		if err != nil {
+			if errors.Is(err, categoryService.ErrCategoryNotFound) {
+				return response.NotFound(c, "category not found", err)
+			}
+			return response.InternalServerError(c, "failed to get category", err)
+		}
+		filter.CategoryIDs = category.IDs()
+	}
+
+	result, err := h.spaceService.Search(c.Request().Context(), filter)
+	if err != nil {
+		return response.InternalServerError(c, "failed to search spaces", err)
+	}
+
+	rate, err := h.displayRate(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid currency", err)
+	}
+	if rate != nil {
+		for i := range result.Data {
+			result.Data[i].SetDisplayPrice(rate.Currency, rate.Convert(result.Data[i].PricePerNight))
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Spaces retrieved successfully", result)
+}
+
+// GetPublicByID menampilkan detail space aktif; space nonaktif dianggap tidak ada
+func (h *SpaceHandler) GetPublicByID(c echo.Context) error {
+	space, err := h.spaceService.GetPublicByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.NotFound(c, "space not found", err)
+	}
+
+	rate, err := h.displayRate(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid currency", err)
+	}
+	if rate != nil {
+		space.SetDisplayPrice(rate.Currency, rate.Convert(space.PricePerNight))
+	}
+
 	return response.Success(c, http.StatusOK, "Space retrieved successfully", space)
 }
 
@@ -76,12 +221,16 @@ func (h *SpaceHandler) Update(c echo.Context) error {
 		return response.BadRequest(c, err.Error(), nil)
 	}
 
+	if err := h.resolveOwner(c, &input); err != nil {
+		return response.BadRequest(c, err.Error(), err)
+	}
+
 	// Verify category exists
-	if _, err := h.categoryService.GetByID(input.CategoryID.String()); err != nil {
+	if _, err := h.categoryService.GetByID(c.Request().Context(), input.CategoryID.String()); err != nil {
 		return response.NotFound(c, "category not found", err)
 	}
 
-	space, err := h.spaceService.Update(id, input)
+	space, err := h.spaceService.Update(c.Request().Context(), id, input)
 	if err != nil {
 		return response.BadRequest(c, "failed to update space", err)
 	}
@@ -91,9 +240,118 @@ func (h *SpaceHandler) Update(c echo.Context) error {
 
 func (h *SpaceHandler) Delete(c echo.Context) error {
 	id := c.Param("id")
-	if err := h.spaceService.Delete(id); err != nil {
+	if err := h.spaceService.Delete(c.Request().Context(), id); err != nil {
+		if errors.Is(err, ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
 		return response.BadRequest(c, "failed to delete space", err)
 	}
 
 	return response.Success(c, http.StatusOK, "Space deleted successfully", nil)
 }
+
+// GetDeleted menampilkan space yang sudah dihapus dan masih bisa di-restore
+func (h *SpaceHandler) GetDeleted(c echo.Context) error {
+	spaces, err := h.spaceService.GetDeleted(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get deleted spaces", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Deleted spaces retrieved successfully", spaces)
+}
+
+func (h *SpaceHandler) Restore(c echo.Context) error {
+	space, err := h.spaceService.Restore(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSpaceNotFound):
+			return response.NotFound(c, "deleted space not found", err)
+		case errors.Is(err, ErrSpaceCategoryDeleted):
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		}
+		return response.BadRequest(c, "failed to restore space", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Space restored successfully", space)
+}
+
+func (h *SpaceHandler) CreateBlackout(c echo.Context) error {
+	var input spaceModel.CreateBlackoutInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	blackout, err := h.spaceService.CreateBlackout(c.Request().Context(), c.Param("id"), input)
+	if err != nil {
+		if errors.Is(err, ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.BadRequest(c, "failed to create blackout", err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Blackout created successfully", blackout)
+}
+
+func (h *SpaceHandler) GetBlackouts(c echo.Context) error {
+	blackouts, err := h.spaceService.GetBlackouts(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.BadRequest(c, "failed to get blackouts", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Blackouts retrieved successfully", blackouts)
+}
+
+func (h *SpaceHandler) DeleteBlackout(c echo.Context) error {
+	if err := h.spaceService.DeleteBlackout(c.Request().Context(), c.Param("id"), c.Param("blackoutId")); err != nil {
+		if errors.Is(err, ErrSpaceNotFound) {
+			return response.NotFound(c, "space not found", err)
+		}
+		return response.BadRequest(c, "failed to delete blackout", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Blackout deleted successfully", nil)
+}
+
+// displayRate mengambil nilai tukar dari query param currency; nil jika tidak diminta
+func (h *SpaceHandler) displayRate(c echo.Context) (*currencyModel.ExchangeRate, error) {
+	currency := c.QueryParam("currency")
+	if currency == "" {
+		return nil, nil
+	}
+	return h.currencyService.GetRate(c.Request().Context(), currency)
+}
+
+// resolveOwner menentukan pemilik space: host selalu menjadi pemilik space yang ia kelola,
+// sedangkan user dengan permission space:write boleh menetapkan owner_id ke user yang
+// role-nya memiliki permission space:host
+func (h *SpaceHandler) resolveOwner(c echo.Context, input *spaceModel.CreateSpaceInput) error {
+	user, ok := c.Get("principal").(*userModel.Principal)
+	if !ok || user == nil {
+		return errors.New("unauthorized")
+	}
+
+	perms, _ := c.Get("permissions").(roleModel.PermissionSet)
+	if !perms.Has(constants.PermissionSpaceWrite) {
+		input.OwnerID = &user.UserID
+		return nil
+	}
+
+	if input.OwnerID == nil {
+		return nil
+	}
+	owner, err := h.userService.GetUserByID(c.Request().Context(), input.OwnerID.String())
+	if err != nil || owner == nil {
+		return errors.New("owner not found")
+	}
+	ownerPerms, err := h.roleService.Permissions(c.Request().Context(), owner.Role)
+	if err != nil {
+		return err
+	}
+	if !ownerPerms.Has(constants.PermissionSpaceHost) {
+		return errors.New("owner must be a user with host role")
+	}
+	return nil
+}
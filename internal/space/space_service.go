@@ -3,32 +3,56 @@ package space
 import (
 	categoryModel "booking/internal/category/model"
 	spaceModel "booking/internal/space/model"
+	"booking/internal/webhook"
+	"booking/pkg/storage"
+	"booking/shared/constants"
+	"context"
 
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrSpaceNotFound        = errors.New("space not found")
+	ErrSpaceCategoryDeleted = errors.New("space category has been deleted; restore the category first")
+)
+
 type SpaceServiceInterface interface {
-	Create(input spaceModel.CreateSpaceInput, category *categoryModel.Category) (*spaceModel.Space, error)
-	GetAll() ([]spaceModel.Space, error)
-	GetByID(id string) (*spaceModel.Space, error)
-	Update(id string, input spaceModel.CreateSpaceInput) (*spaceModel.Space, error)
-	Delete(id string) error
+	Create(ctx context.Context, input spaceModel.CreateSpaceInput, category *categoryModel.Category) (*spaceModel.Space, error)
+	GetAll(ctx context.Context) ([]spaceModel.Space, error)
+	GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]spaceModel.Space, error)
+	GetByID(ctx context.Context, id string) (*spaceModel.Space, error)
+	Update(ctx context.Context, id string, input spaceModel.CreateSpaceInput) (*spaceModel.Space, error)
+	Delete(ctx context.Context, id string) error
+	GetDeleted(ctx context.Context) ([]spaceModel.Space, error)
+	Restore(ctx context.Context, id string) (*spaceModel.Space, error)
+	CreateBlackout(ctx context.Context, spaceID string, input spaceModel.CreateBlackoutInput) (*spaceModel.Blackout, error)
+	GetBlackouts(ctx context.Context, spaceID string) ([]spaceModel.Blackout, error)
+	DeleteBlackout(ctx context.Context, spaceID string, blackoutID string) error
+	HasBlackout(ctx context.Context, spaceID uuid.UUID, startDate, endDate time.Time) (bool, error)
+	GetPublic(ctx context.Context) ([]spaceModel.PublicSpace, error)
+	GetPublicByID(ctx context.Context, id string) (*spaceModel.PublicSpace, error)
+	Search(ctx context.Context, filter *spaceModel.SearchFilter) (*spaceModel.SearchResult, error)
 }
 
 type SpaceService struct {
-	db *gorm.DB
+	db             *gorm.DB
+	webhookService webhook.WebhookServiceInterface
+	storage        storage.Storage
 }
 
-func NewSpaceService(db *gorm.DB) *SpaceService {
+func NewSpaceService(db *gorm.DB, webhookService webhook.WebhookServiceInterface, storage storage.Storage) *SpaceService {
 	return &SpaceService{
-		db: db,
+		db:             db,
+		webhookService: webhookService,
+		storage:        storage,
 	}
 }
 
-func (s *SpaceService) Create(input spaceModel.CreateSpaceInput, category *categoryModel.Category) (*spaceModel.Space, error) {
+func (s *SpaceService) Create(ctx context.Context, input spaceModel.CreateSpaceInput, category *categoryModel.Category) (*spaceModel.Space, error) {
 	if category == nil {
 		return nil, errors.New("category is required")
 	}
@@ -42,48 +66,67 @@ func (s *SpaceService) Create(input spaceModel.CreateSpaceInput, category *categ
 		return nil, err
 	}
 
-	if err := s.db.Create(space).Error; err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(space).Error; err != nil {
+			return err
+		}
+		return s.webhookService.Publish(tx, constants.EventSpaceCreated, space)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return space, nil
 }
 
-func (s *SpaceService) GetAll() ([]spaceModel.Space, error) {
+func (s *SpaceService) GetAll(ctx context.Context) ([]spaceModel.Space, error) {
 	var spaces []spaceModel.Space
-	if err := s.db.Find(&spaces).Error; err != nil {
+	if err := s.db.WithContext(ctx).Find(&spaces).Error; err != nil {
 		return nil, err
 	}
-	return spaces, nil
+	return spaces, s.withFacilities(ctx, spaces)
 }
 
-func (s *SpaceService) GetByID(id string) (*spaceModel.Space, error) {
+// GetByOwner mengambil semua space milik host
+func (s *SpaceService) GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]spaceModel.Space, error) {
+	var spaces []spaceModel.Space
+	if err := s.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&spaces).Error; err != nil {
+		return nil, err
+	}
+	return spaces, s.withFacilities(ctx, spaces)
+}
+
+func (s *SpaceService) GetByID(ctx context.Context, id string) (*spaceModel.Space, error) {
 	spaceID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, errors.New("invalid space ID")
 	}
 
 	var space spaceModel.Space
-	if err := s.db.First(&space, "id = ?", spaceID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&space, "id = ?", spaceID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("space not found")
+			return nil, ErrSpaceNotFound
 		}
 		return nil, err
 	}
 
-	return &space, nil
+	spaces := []spaceModel.Space{space}
+	if err := s.withFacilities(ctx, spaces); err != nil {
+		return nil, err
+	}
+	return &spaces[0], nil
 }
 
-func (s *SpaceService) Update(id string, input spaceModel.CreateSpaceInput) (*spaceModel.Space, error) {
+func (s *SpaceService) Update(ctx context.Context, id string, input spaceModel.CreateSpaceInput) (*spaceModel.Space, error) {
 	spaceID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, errors.New("invalid space ID")
 	}
 
 	var space spaceModel.Space
-	if err := s.db.First(&space, "id = ?", spaceID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&space, "id = ?", spaceID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("space not found")
+			return nil, ErrSpaceNotFound
 		}
 		return nil, err
 	}
@@ -93,28 +136,372 @@ func (s *SpaceService) Update(id string, input spaceModel.CreateSpaceInput) (*sp
 	space.Description = input.Description
 	space.PricePerNight = input.PricePerNight
 	space.CategoryID = input.CategoryID
+	if input.MaxGuests > 0 {
+		space.MaxGuests = input.MaxGuests
+	}
+	if err := spaceModel.ValidateCoordinates(input.Latitude, input.Longitude); err != nil {
+		return nil, err
+	}
+	space.Address = input.Address
+	if input.OwnerID != nil {
+		space.OwnerID = input.OwnerID
+	}
+	space.SetCoordinates(input.Latitude, input.Longitude)
 
-	if err := s.db.Save(&space).Error; err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&space).Error; err != nil {
+			return err
+		}
+		return s.webhookService.Publish(tx, constants.EventSpaceUpdated, &space)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &space, nil
+	spaces := []spaceModel.Space{space}
+	if err := s.withFacilities(ctx, spaces); err != nil {
+		return nil, err
+	}
+	return &spaces[0], nil
 }
 
-func (s *SpaceService) Delete(id string) error {
+func (s *SpaceService) Delete(ctx context.Context, id string) error {
 	spaceID, err := uuid.Parse(id)
 	if err != nil {
 		return errors.New("invalid space ID")
 	}
 
-	result := s.db.Delete(&spaceModel.Space{}, "id = ?", spaceID)
+	var space spaceModel.Space
+	if err := s.db.WithContext(ctx).First(&space, "id = ?", spaceID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSpaceNotFound
+		}
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&space).Error; err != nil {
+			return err
+		}
+		return s.webhookService.Publish(tx, constants.EventSpaceDeleted, &space)
+	})
+}
+
+// GetDeleted mengambil space yang sudah di-soft delete
+func (s *SpaceService) GetDeleted(ctx context.Context) ([]spaceModel.Space, error) {
+	spaces := []spaceModel.Space{}
+	if err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&spaces).Error; err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+// Restore mengembalikan space yang sudah di-soft delete selama kategorinya masih ada
+func (s *SpaceService) Restore(ctx context.Context, id string) (*spaceModel.Space, error) {
+	var space spaceModel.Space
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&space, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSpaceNotFound
+			}
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&categoryModel.Category{}).Where("id = ?", space.CategoryID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrSpaceCategoryDeleted
+		}
+
+		if err := tx.Unscoped().Model(&space).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		space.DeletedAt = gorm.DeletedAt{}
+		return s.webhookService.Publish(tx, constants.EventSpaceRestored, &space)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spaces := []spaceModel.Space{space}
+	if err := s.withFacilities(ctx, spaces); err != nil {
+		return nil, err
+	}
+	return &spaces[0], nil
+}
+
+func (s *SpaceService) CreateBlackout(ctx context.Context, spaceID string, input spaceModel.CreateBlackoutInput) (*spaceModel.Blackout, error) {
+	space, err := s.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	blackout, err := spaceModel.NewBlackout(space.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(blackout).Error; err != nil {
+		return nil, err
+	}
+
+	return blackout, nil
+}
+
+// GetBlackouts mengambil blackout space; tabel blackouts tidak punya organization_id
+// sehingga space dicek lebih dulu lewat query yang dibatasi tenant
+func (s *SpaceService) GetBlackouts(ctx context.Context, spaceID string) ([]spaceModel.Blackout, error) {
+	space, err := s.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var blackouts []spaceModel.Blackout
+	if err := s.db.WithContext(ctx).Where("space_id = ?", space.ID).Order("start_date").Find(&blackouts).Error; err != nil {
+		return nil, err
+	}
+	return blackouts, nil
+}
+
+func (s *SpaceService) DeleteBlackout(ctx context.Context, spaceID string, blackoutID string) error {
+	space, err := s.GetByID(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	result := s.db.WithContext(ctx).Delete(&spaceModel.Blackout{}, "id = ? AND space_id = ?", blackoutID, space.ID)
 	if result.Error != nil {
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("space not found")
+		return errors.New("blackout not found")
+	}
+
+	return nil
+}
+
+// HasBlackout mengecek apakah rentang tanggal bertabrakan dengan blackout space
+func (s *SpaceService) HasBlackout(ctx context.Context, spaceID uuid.UUID, startDate, endDate time.Time) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&spaceModel.Blackout{}).
+		Where("space_id = ? AND start_date < ? AND end_date > ?", spaceID, endDate, startDate).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetPublic mengambil katalog space aktif beserta kategori dan fasilitasnya
+func (s *SpaceService) GetPublic(ctx context.Context) ([]spaceModel.PublicSpace, error) {
+	var spaces []spaceModel.Space
+	if err := s.db.WithContext(ctx).Where("is_active = ?", true).Order("name").Find(&spaces).Error; err != nil {
+		return nil, err
+	}
+	return s.toPublic(ctx, spaces)
+}
+
+func (s *SpaceService) GetPublicByID(ctx context.Context, id string) (*spaceModel.PublicSpace, error) {
+	spaceID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid space ID")
+	}
+
+	var space spaceModel.Space
+	if err := s.db.WithContext(ctx).First(&space, "id = ? AND is_active = ?", spaceID, true).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSpaceNotFound
+		}
+		return nil, err
+	}
+
+	result, err := s.toPublic(ctx, []spaceModel.Space{space})
+	if err != nil {
+		return nil, err
+	}
+	return &result[0], nil
+}
+
+// Search mencari space aktif dengan filter; seluruh filter termasuk ketersediaan tanggal
+// dijalankan di SQL sehingga paginasi dan total tetap akurat
+func (s *SpaceService) Search(ctx context.Context, filter *spaceModel.SearchFilter) (*spaceModel.SearchResult, error) {
+	query := s.db.WithContext(ctx).Model(&spaceModel.Space{}).Where("spaces.is_active = ?", true)
+
+	if filter.Query != "" {
+		query = query.Where("MATCH(spaces.name, spaces.description) AGAINST (? IN NATURAL LANGUAGE MODE)", filter.Query)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("spaces.category_id IN ?", filter.CategoryIDs)
+	}
+	if len(filter.FacilityIDs) > 0 {
+		// Space harus memiliki semua fasilitas yang diminta
+		query = query.Where("spaces.id IN (?)", s.db.WithContext(ctx).Table("space_facilities").
+			Select("space_id").
+			Where("facility_id IN ?", filter.FacilityIDs).
+			Group("space_id").
+			Having("COUNT(DISTINCT facility_id) = ?", len(filter.FacilityIDs)))
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("spaces.price_per_night >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("spaces.price_per_night <= ?", filter.MaxPrice)
+	}
+	if filter.Guests > 0 {
+		query = query.Where("spaces.max_guests >= ?", filter.Guests)
+	}
+	if filter.CheckIn != nil && filter.CheckOut != nil {
+		start, end := *filter.CheckIn, *filter.CheckOut
+		query = query.
+			Where("NOT EXISTS (SELECT 1 FROM bookings WHERE bookings.space_id = spaces.id AND bookings.status != ? AND bookings.start_date < ? AND bookings.end_date > ?)",
+				constants.BookingStatusCancelled, end, start).
+			Where("NOT EXISTS (SELECT 1 FROM blackouts WHERE blackouts.space_id = spaces.id AND blackouts.start_date < ? AND blackouts.end_date > ?)",
+				end, start).
+			Where("NOT EXISTS (SELECT 1 FROM external_blocks WHERE external_blocks.space_id = spaces.id AND external_blocks.start_date < ? AND external_blocks.end_date > ?)",
+				end, start)
+	}
+
+	selects := "spaces.*"
+	var selectArgs []interface{}
+	if filter.BBox != nil {
+		query = query.Where("spaces.latitude IS NOT NULL AND MBRContains("+spaceModel.GeomFromTextSQL+", spaces.location)",
+			filter.BBox.Polygon(), spaceModel.SRID)
+	}
+	if filter.Center != nil {
+		distance := "ST_Distance_Sphere(spaces.location, " + spaceModel.GeomFromTextSQL + ")"
+		query = query.Where("spaces.latitude IS NOT NULL")
+		// Prefilter dengan bounding box agar spatial index terpakai, lalu saring jarak sebenarnya
+		if box, ok := spaceModel.RadiusBox(filter.Center.Lat, filter.Center.Lng, filter.RadiusKm); ok {
+			query = query.Where("MBRContains("+spaceModel.GeomFromTextSQL+", spaces.location)", box.Polygon(), spaceModel.SRID)
+		}
+		query = query.Where(distance+" <= ?", filter.Center.WKT(), spaceModel.SRID, filter.RadiusKm*1000)
+		selects += ", " + distance + " / 1000 AS distance_km"
+		selectArgs = []interface{}{filter.Center.WKT(), spaceModel.SRID}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	switch filter.Sort {
+	case spaceModel.SortPriceAsc:
+		query = query.Order("spaces.price_per_night ASC")
+	case spaceModel.SortPriceDesc:
+		query = query.Order("spaces.price_per_night DESC")
+	case spaceModel.SortDistance:
+		query = query.Order("distance_km ASC")
+	default:
+		query = query.Order("spaces.created_at DESC")
+	}
+
+	var spaces []spaceModel.Space
+	if err := query.Select(selects, selectArgs...).Order("spaces.id").
+		Offset(filter.Offset()).Limit(filter.PerPage).
+		Find(&spaces).Error; err != nil {
+		return nil, err
 	}
 
+	data, err := s.toPublic(ctx, spaces)
+	if err != nil {
+		return nil, err
+	}
+	return spaceModel.NewSearchResult(data, filter, total), nil
+}
+
+// toPublic memuat kategori, fasilitas dan foto untuk sekumpulan space dengan satu query per relasi
+func (s *SpaceService) toPublic(ctx context.Context, spaces []spaceModel.Space) ([]spaceModel.PublicSpace, error) {
+	result := make([]spaceModel.PublicSpace, 0, len(spaces))
+	if len(spaces) == 0 {
+		return result, nil
+	}
+
+	spaceIDs := make([]uuid.UUID, 0, len(spaces))
+	categoryIDs := make([]uuid.UUID, 0, len(spaces))
+	for _, space := range spaces {
+		spaceIDs = append(spaceIDs, space.ID)
+		categoryIDs = append(categoryIDs, space.CategoryID)
+	}
+
+	var categories []categoryModel.Category
+	if err := s.db.WithContext(ctx).Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	categoryByID := make(map[uuid.UUID]*spaceModel.PublicCategory, len(categories))
+	for _, category := range categories {
+		categoryByID[category.ID] = &spaceModel.PublicCategory{
+			ID:   category.ID,
+			Name: category.Name,
+			Slug: category.Slug,
+		}
+	}
+
+	facilitiesBySpace, err := s.loadFacilities(ctx, spaceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var photos []spaceModel.Photo
+	if err := s.db.WithContext(ctx).Where("space_id IN ?", spaceIDs).Order("position").Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	photosBySpace := make(map[uuid.UUID][]spaceModel.PublicPhoto, len(spaces))
+	for i := range photos {
+		photos[i].SetURLs(s.storage.URL)
+		photosBySpace[photos[i].SpaceID] = append(photosBySpace[photos[i].SpaceID], photos[i].ToPublic())
+	}
+
+	for i := range spaces {
+		public := spaceModel.NewPublicSpace(&spaces[i], categoryByID[spaces[i].CategoryID], facilitiesBySpace[spaces[i].ID])
+		public.SetPhotos(photosBySpace[spaces[i].ID])
+		result = append(result, public)
+	}
+	return result, nil
+}
+
+// withFacilities mengisi Facilities setiap space dengan satu query
+func (s *SpaceService) withFacilities(ctx context.Context, spaces []spaceModel.Space) error {
+	if len(spaces) == 0 {
+		return nil
+	}
+
+	spaceIDs := make([]uuid.UUID, 0, len(spaces))
+	for _, space := range spaces {
+		spaceIDs = append(spaceIDs, space.ID)
+	}
+
+	facilitiesBySpace, err := s.loadFacilities(ctx, spaceIDs)
+	if err != nil {
+		return err
+	}
+	for i := range spaces {
+		spaces[i].Facilities = facilitiesBySpace[spaces[i].ID]
+		if spaces[i].Facilities == nil {
+			spaces[i].Facilities = []spaceModel.PublicFacility{}
+		}
+	}
 	return nil
 }
+
+// loadFacilities memuat fasilitas (yang belum dihapus) untuk sekumpulan space, dikelompokkan per space
+func (s *SpaceService) loadFacilities(ctx context.Context, spaceIDs []uuid.UUID) (map[uuid.UUID][]spaceModel.PublicFacility, error) {
+	var facilities []spaceModel.PublicFacility
+	err := s.db.WithContext(ctx).Table("space_facilities AS sf").
+		Select("sf.space_id, f.id, f.name").
+		Joins("JOIN facilities AS f ON f.id = sf.facility_id AND f.deleted_at IS NULL").
+		Where("sf.space_id IN ?", spaceIDs).
+		Order("f.name").
+		Scan(&facilities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	facilitiesBySpace := make(map[uuid.UUID][]spaceModel.PublicFacility, len(spaceIDs))
+	for _, facility := range facilities {
+		facilitiesBySpace[facility.SpaceID] = append(facilitiesBySpace[facility.SpaceID], facility)
+	}
+	return facilitiesBySpace, nil
+}
@@ -19,6 +19,25 @@ type SpaceFacilityInput struct {
 	FacilityID uuid.UUID `json:"facility_id" validate:"required"`
 }
 
+// DTO: Replace facilities input; daftar kosong menghapus semua fasilitas space
+type ReplaceFacilitiesInput struct {
+	FacilityIDs []uuid.UUID `json:"facility_ids" validate:"required"`
+}
+
+// UniqueFacilityIDs membuang id kosong dan duplikat dengan urutan tetap
+func (i ReplaceFacilitiesInput) UniqueFacilityIDs() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(i.FacilityIDs))
+	ids := make([]uuid.UUID, 0, len(i.FacilityIDs))
+	for _, id := range i.FacilityIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func NewSpaceFacility(input SpaceFacilityInput) (*SpaceFacility, error) {
 	if input.SpaceID == uuid.Nil {
 		return nil, errors.New("space id is required")
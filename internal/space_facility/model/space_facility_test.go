@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type SpaceFacilityTestSuite struct {
+	suite.Suite
+}
+
+func TestSpaceFacilitySuite(t *testing.T) {
+	suite.Run(t, new(SpaceFacilityTestSuite))
+}
+
+func (s *SpaceFacilityTestSuite) TestNewSpaceFacilityRequiresIDs() {
+	_, err := NewSpaceFacility(SpaceFacilityInput{FacilityID: uuid.New()})
+	s.Error(err)
+
+	_, err = NewSpaceFacility(SpaceFacilityInput{SpaceID: uuid.New()})
+	s.Error(err)
+}
+
+func (s *SpaceFacilityTestSuite) TestUniqueFacilityIDs() {
+	a, b := uuid.New(), uuid.New()
+	input := ReplaceFacilitiesInput{FacilityIDs: []uuid.UUID{a, uuid.Nil, b, a}}
+	s.Equal([]uuid.UUID{a, b}, input.UniqueFacilityIDs())
+
+	s.Empty(ReplaceFacilitiesInput{FacilityIDs: []uuid.UUID{}}.UniqueFacilityIDs())
+}
@@ -4,6 +4,7 @@ import (
 	"booking/internal/space_facility/model"
 	"booking/pkg/response"
 	"booking/shared/validate"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -26,7 +27,56 @@ func (h *SpaceFacilityHandler) Create(c echo.Context) error {
 	}
 	sf, err := h.sFService.Create(c.Request().Context(), input)
 	if err != nil {
-		return response.BadRequest(c, "failed to create space facility", err)
+		return errorResponse(c, "failed to create space facility", err)
 	}
 	return response.Success(c, http.StatusCreated, "space facility created", sf)
 }
+
+// GetBySpace menampilkan fasilitas milik space pada param :id
+func (h *SpaceFacilityHandler) GetBySpace(c echo.Context) error {
+	facilities, err := h.sFService.GetBySpace(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, "failed to get space facilities", err)
+	}
+	return response.Success(c, http.StatusOK, "space facilities retrieved", facilities)
+}
+
+// Replace mengganti seluruh fasilitas space dengan facility_ids
+func (h *SpaceFacilityHandler) Replace(c echo.Context) error {
+	var input model.ReplaceFacilitiesInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+	facilities, err := h.sFService.Replace(c.Request().Context(), c.Param("id"), input.UniqueFacilityIDs())
+	if err != nil {
+		return errorResponse(c, "failed to replace space facilities", err)
+	}
+	return response.Success(c, http.StatusOK, "space facilities replaced", facilities)
+}
+
+func (h *SpaceFacilityHandler) Remove(c echo.Context) error {
+	if err := h.sFService.Remove(c.Request().Context(), c.Param("id"), c.Param("facilityId")); err != nil {
+		return errorResponse(c, "failed to remove space facility", err)
+	}
+	return response.Success(c, http.StatusOK, "space facility removed", nil)
+}
+
+// GetSpaces menampilkan space yang memiliki fasilitas pada param :id
+func (h *SpaceFacilityHandler) GetSpaces(c echo.Context) error {
+	spaces, err := h.sFService.GetSpaces(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return errorResponse(c, "failed to get facility spaces", err)
+	}
+	return response.Success(c, http.StatusOK, "facility spaces retrieved", spaces)
+}
+
+func errorResponse(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, ErrSpaceNotFound), errors.Is(err, ErrFacilityNotFound), errors.Is(err, ErrFacilityNotAttached):
+		return response.NotFound(c, err.Error(), err)
+	case errors.Is(err, ErrFacilityAttached):
+		return response.Error(c, http.StatusConflict, err.Error(), err)
+	default:
+		return response.BadRequest(c, message, err)
+	}
+}
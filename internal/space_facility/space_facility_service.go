@@ -1,17 +1,32 @@
 package spacefacility
 
 import (
+	facilityModel "booking/internal/facility/model"
+	spaceModel "booking/internal/space/model"
 	spaceFacilityModel "booking/internal/space_facility/model"
 	"booking/pkg/logger"
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSpaceNotFound       = errors.New("space not found")
+	ErrFacilityNotFound    = errors.New("facility not found")
+	ErrFacilityAttached    = errors.New("facility is already attached to this space")
+	ErrFacilityNotAttached = errors.New("facility is not attached to this space")
 )
 
 type SpaceFacilityServiceInterface interface {
 	Create(ctx context.Context, input spaceFacilityModel.SpaceFacilityInput) (*spaceFacilityModel.SpaceFacility, error)
+	GetBySpace(ctx context.Context, spaceID string) ([]facilityModel.Facility, error)
+	Replace(ctx context.Context, spaceID string, facilityIDs []uuid.UUID) ([]facilityModel.Facility, error)
+	Remove(ctx context.Context, spaceID string, facilityID string) error
+	GetSpaces(ctx context.Context, facilityID string) ([]spaceModel.Space, error)
 }
 type SpaceFacilityService struct {
 	db     *gorm.DB
@@ -27,22 +42,18 @@ func NewSpaceFacilityService(db *gorm.DB, logger logger.Logger) *SpaceFacilitySe
 
 func (s *SpaceFacilityService) Create(ctx context.Context, input spaceFacilityModel.SpaceFacilityInput) (*spaceFacilityModel.SpaceFacility, error) {
 	// cek apakah space ada
-	var count int64
-	if err := s.db.WithContext(ctx).Table("spaces").Where("id = ?", input.SpaceID).Count(&count).Error; err != nil {
-		s.logger.Error(ctx, "failed to check space existence", err)
+	if err := s.ensureSpace(ctx, s.db, input.SpaceID); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("space not found")
-	}
 
 	// Cek apakah facility ada
-	if err := s.db.WithContext(ctx).Table("facilities").Where("id = ?", input.FacilityID).Count(&count).Error; err != nil {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&facilityModel.Facility{}).Where("id = ?", input.FacilityID).Count(&count).Error; err != nil {
 		s.logger.Error(ctx, "failed to check facility existence", err)
 		return nil, err
 	}
 	if count == 0 {
-		return nil, errors.New("facility not found")
+		return nil, ErrFacilityNotFound
 	}
 
 	sf, err := spaceFacilityModel.NewSpaceFacility(input)
@@ -55,15 +66,162 @@ func (s *SpaceFacilityService) Create(ctx context.Context, input spaceFacilityMo
 		return nil, err
 	}
 
-	// Simpan ke database
-	if err := s.db.WithContext(ctx).Create(sf).Error; err != nil {
+	// Simpan ke database; relasi yang sudah ada tidak dibuat ulang
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(sf)
+	if result.Error != nil {
 		s.logger.WithFields(logrus.Fields{
 			"method":      "Create",
 			"space_id":    input.SpaceID,
 			"facility_id": input.FacilityID,
-		}).Error(ctx, "failed to save space facility to database", err)
-		return nil, err
+		}).Error(ctx, "failed to save space facility to database", result.Error)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrFacilityAttached
 	}
 
 	return sf, nil
 }
+
+// GetBySpace mengambil fasilitas yang dimiliki sebuah space
+func (s *SpaceFacilityService) GetBySpace(ctx context.Context, spaceID string) ([]facilityModel.Facility, error) {
+	id, err := uuid.Parse(spaceID)
+	if err != nil {
+		return nil, ErrSpaceNotFound
+	}
+	if err := s.ensureSpace(ctx, s.db, id); err != nil {
+		return nil, err
+	}
+	return s.facilitiesOf(ctx, s.db, id)
+}
+
+// Replace mengganti seluruh fasilitas space dengan daftar baru dalam satu transaksi
+func (s *SpaceFacilityService) Replace(ctx context.Context, spaceID string, facilityIDs []uuid.UUID) ([]facilityModel.Facility, error) {
+	id, err := uuid.Parse(spaceID)
+	if err != nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	var facilities []facilityModel.Facility
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Kunci baris space supaya replace yang berjalan bersamaan tidak saling menimpa
+		var space spaceModel.Space
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&space, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSpaceNotFound
+			}
+			return err
+		}
+
+		if len(facilityIDs) > 0 {
+			var count int64
+			if err := tx.Model(&facilityModel.Facility{}).Where("id IN ?", facilityIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) != len(facilityIDs) {
+				return ErrFacilityNotFound
+			}
+		}
+
+		remove := tx.Where("space_id = ?", id)
+		if len(facilityIDs) > 0 {
+			remove = remove.Where("facility_id NOT IN ?", facilityIDs)
+		}
+		if err := remove.Delete(&spaceFacilityModel.SpaceFacility{}).Error; err != nil {
+			return err
+		}
+
+		if len(facilityIDs) > 0 {
+			rows := make([]spaceFacilityModel.SpaceFacility, 0, len(facilityIDs))
+			for _, facilityID := range facilityIDs {
+				rows = append(rows, spaceFacilityModel.SpaceFacility{SpaceID: id, FacilityID: facilityID})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		facilities, err = s.facilitiesOf(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, ErrSpaceNotFound) && !errors.Is(err, ErrFacilityNotFound) {
+			s.logger.WithFields(logrus.Fields{
+				"method":   "Replace",
+				"space_id": spaceID,
+			}).Error(ctx, "failed to replace space facilities", err)
+		}
+		return nil, err
+	}
+
+	return facilities, nil
+}
+
+// Remove melepas satu fasilitas dari space
+func (s *SpaceFacilityService) Remove(ctx context.Context, spaceID string, facilityID string) error {
+	id, err := uuid.Parse(spaceID)
+	if err != nil {
+		return ErrSpaceNotFound
+	}
+	if err := s.ensureSpace(ctx, s.db, id); err != nil {
+		return err
+	}
+
+	result := s.db.WithContext(ctx).
+		Where("space_id = ? AND facility_id = ?", id, facilityID).
+		Delete(&spaceFacilityModel.SpaceFacility{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFacilityNotAttached
+	}
+	return nil
+}
+
+// GetSpaces mencari semua space yang memiliki fasilitas tertentu
+func (s *SpaceFacilityService) GetSpaces(ctx context.Context, facilityID string) ([]spaceModel.Space, error) {
+	var facility facilityModel.Facility
+	if err := s.db.WithContext(ctx).Select("id").First(&facility, "id = ?", facilityID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFacilityNotFound
+		}
+		return nil, err
+	}
+
+	var spaces []spaceModel.Space
+	err := s.db.WithContext(ctx).
+		Joins("JOIN space_facilities AS sf ON sf.space_id = spaces.id").
+		Where("sf.facility_id = ?", facility.ID).
+		Order("spaces.name").
+		Find(&spaces).Error
+	if err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+func (s *SpaceFacilityService) ensureSpace(ctx context.Context, db *gorm.DB, spaceID uuid.UUID) error {
+	var count int64
+	if err := db.WithContext(ctx).Model(&spaceModel.Space{}).Where("id = ?", spaceID).Count(&count).Error; err != nil {
+		s.logger.Error(ctx, "failed to check space existence", err)
+		return err
+	}
+	if count == 0 {
+		return ErrSpaceNotFound
+	}
+	return nil
+}
+
+func (s *SpaceFacilityService) facilitiesOf(ctx context.Context, db *gorm.DB, spaceID uuid.UUID) ([]facilityModel.Facility, error) {
+	facilities := []facilityModel.Facility{}
+	err := db.WithContext(ctx).
+		Joins("JOIN space_facilities AS sf ON sf.facility_id = facilities.id").
+		Where("sf.space_id = ?", spaceID).
+		Order("facilities.name").
+		Find(&facilities).Error
+	if err != nil {
+		return nil, err
+	}
+	return facilities, nil
+}
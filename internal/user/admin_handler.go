@@ -0,0 +1,144 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/role"
+	roleModel "booking/internal/role/model"
+	"booking/internal/user/model"
+	"booking/pkg/response"
+	errs "booking/shared/errors"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// AdminHandler menangani tindakan admin terhadap akun user (permission user:manage)
+type AdminHandler struct {
+	adminService AdminServiceInterface
+	userService  UserServiceInterface
+	roleService  role.RoleServiceInterface
+}
+
+func NewAdminHandler(adminService AdminServiceInterface, userService UserServiceInterface, roleService role.RoleServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		userService:  userService,
+		roleService:  roleService,
+	}
+}
+
+// Suspend menonaktifkan akun user dan mencabut semua session-nya
+func (h *AdminHandler) Suspend(c echo.Context) error {
+	actor, input, err := h.authorize(c)
+	if actor == nil {
+		return err
+	}
+
+	user, err := h.adminService.Suspend(c.Request().Context(), actor, c.Param("id"), input.Reason, c.RealIP())
+	if err != nil {
+		return adminError(c, "failed to suspend user", err)
+	}
+
+	return response.Success(c, http.StatusOK, "User suspended successfully", user)
+}
+
+// Unsuspend mengaktifkan kembali akun user
+func (h *AdminHandler) Unsuspend(c echo.Context) error {
+	actor, input, err := h.authorize(c)
+	if actor == nil {
+		return err
+	}
+
+	user, err := h.adminService.Unsuspend(c.Request().Context(), actor, c.Param("id"), input.Reason, c.RealIP())
+	if err != nil {
+		return adminError(c, "failed to unsuspend user", err)
+	}
+
+	return response.Success(c, http.StatusOK, "User unsuspended successfully", user)
+}
+
+// ForcePasswordReset mewajibkan user mengganti password lewat link yang dikirim ke email
+func (h *AdminHandler) ForcePasswordReset(c echo.Context) error {
+	actor, input, err := h.authorize(c)
+	if actor == nil {
+		return err
+	}
+
+	if err := h.adminService.ForcePasswordReset(c.Request().Context(), actor, c.Param("id"), input.Reason, c.RealIP()); err != nil {
+		return adminError(c, "failed to force password reset", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Password reset required; a reset link has been sent to the user", nil)
+}
+
+// Impersonate membuat access token untuk bertindak sebagai user (khusus superadmin). Token
+// ditandai claim "act" dan setiap response dengan token ini berisi header X-Impersonated-By.
+func (h *AdminHandler) Impersonate(c echo.Context) error {
+	actor, input, err := h.authorize(c)
+	if actor == nil {
+		return err
+	}
+
+	tokens, err := h.adminService.Impersonate(c.Request().Context(), actor, c.Param("id"), input.Reason, c.RealIP())
+	if err != nil {
+		return adminError(c, "failed to impersonate user", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Impersonation started; the token expires without refresh", tokens)
+}
+
+// AuditLogs menampilkan riwayat tindakan admin terkait user
+func (h *AdminHandler) AuditLogs(c echo.Context) error {
+	logs, err := h.adminService.AuditLogs(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.InternalServerError(c, "failed to get audit logs", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Audit logs retrieved successfully", logs)
+}
+
+// authorize membaca alasan tindakan dan memastikan admin memiliki semua permission role
+// target, seperti saat mengubah role. Jika actor nil, response sudah dikirim.
+func (h *AdminHandler) authorize(c echo.Context) (*model.Principal, *model.AdminActionInput, error) {
+	actor, ok := c.Get("principal").(*model.Principal)
+	granted, hasPerms := c.Get("permissions").(roleModel.PermissionSet)
+	if !ok || actor == nil || !hasPerms {
+		return nil, nil, response.Unauthorized(c, "unauthorized", nil)
+	}
+
+	var input model.AdminActionInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return nil, nil, err
+	}
+
+	ctx := c.Request().Context()
+	target, err := h.userService.GetUserByID(ctx, c.Param("id"))
+	if err != nil {
+		return nil, nil, response.NotFound(c, "user not found", err)
+	}
+	current, err := h.roleService.Permissions(ctx, target.Role)
+	if err != nil {
+		return nil, nil, response.InternalServerError(c, "failed to get role", err)
+	}
+	if !granted.Covers(current) {
+		return nil, nil, response.Forbidden(c, "access denied: user has permissions you do not have", nil)
+	}
+
+	return actor, &input, nil
+}
+
+// adminError memetakan error tindakan admin
+func adminError(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return response.NotFound(c, "user not found", err)
+	case errors.Is(err, errs.ErrSelfAdminAction), errors.Is(err, errs.ErrImpersonationDenied):
+		return response.Forbidden(c, err.Error(), err)
+	case errors.Is(err, errs.ErrAccountSuspended):
+		return response.Error(c, http.StatusConflict, err.Error(), err)
+	}
+	return response.InternalServerError(c, message, err)
+}
@@ -0,0 +1,194 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/session"
+	sessionModel "booking/internal/session/model"
+	"booking/internal/user/model"
+	"booking/pkg/logger"
+	"booking/shared/constants"
+	userErr "booking/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxAuditLogs adalah jumlah maksimum entri audit yang ditampilkan per user
+const maxAuditLogs = 200
+
+// AdminServiceInterface mendefinisikan kontrak untuk AdminService
+type AdminServiceInterface interface {
+	Suspend(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) (*model.User, error)
+	Unsuspend(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) (*model.User, error)
+	ForcePasswordReset(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) error
+	Impersonate(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) (*sessionModel.TokenPair, error)
+	AuditLogs(ctx context.Context, userID string) ([]model.AdminAuditLog, error)
+}
+
+// AdminService menjalankan tindakan admin terhadap akun user lain; setiap tindakan dicatat
+// di audit log dalam transaksi yang sama dengan perubahannya
+type AdminService struct {
+	db              *gorm.DB
+	logger          logger.Logger
+	sessionService  session.SessionServiceInterface
+	passwordService PasswordServiceInterface
+}
+
+func NewAdminService(db *gorm.DB, logger logger.Logger, sessionService session.SessionServiceInterface, passwordService PasswordServiceInterface) *AdminService {
+	return &AdminService{
+		db:              db,
+		logger:          logger,
+		sessionService:  sessionService,
+		passwordService: passwordService,
+	}
+}
+
+// Suspend menonaktifkan akun: login ditolak, semua session dicabut dan access token yang
+// sudah diterbitkan (termasuk token impersonasi) tidak berlaku lagi
+func (s *AdminService) Suspend(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) (*model.User, error) {
+	user, err := s.apply(ctx, actor, targetUserID, model.AuditSuspend, reason, ipAddress, func(tx *gorm.DB, user *model.User) error {
+		user.Suspend(reason, time.Now())
+		return tx.Model(user).Updates(map[string]interface{}{
+			"suspended_at":      user.SuspendedAt,
+			"suspension_reason": user.SuspensionReason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionService.RevokeAll(ctx, user.ID.String(), sessionModel.RevokeSuspended); err != nil {
+		return nil, err
+	}
+	if err := s.sessionService.InvalidateAccessTokens(ctx, user.ID.String()); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"actor_id":       actor.UserID,
+		"target_user_id": user.ID,
+	}).Warn("Akun user dinonaktifkan admin")
+	return user, nil
+}
+
+// Unsuspend mengaktifkan kembali akun; user perlu login ulang
+func (s *AdminService) Unsuspend(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) (*model.User, error) {
+	return s.apply(ctx, actor, targetUserID, model.AuditUnsuspend, reason, ipAddress, func(tx *gorm.DB, user *model.User) error {
+		user.Unsuspend()
+		return tx.Model(user).Updates(map[string]interface{}{
+			"suspended_at":      nil,
+			"suspension_reason": "",
+		}).Error
+	})
+}
+
+// ForcePasswordReset mewajibkan user mengganti password: login dengan password ditolak,
+// semua session dicabut, access token yang sudah diterbitkan (termasuk token impersonasi)
+// tidak berlaku lagi, lalu link reset dikirim ke email user
+func (s *AdminService) ForcePasswordReset(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) error {
+	user, err := s.apply(ctx, actor, targetUserID, model.AuditForcePasswordReset, reason, ipAddress, func(tx *gorm.DB, user *model.User) error {
+		user.PasswordResetRequired = true
+		return tx.Model(user).Update("password_reset_required", true).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionService.RevokeAll(ctx, user.ID.String(), sessionModel.RevokeAdminReset); err != nil {
+		return err
+	}
+	if err := s.sessionService.InvalidateAccessTokens(ctx, user.ID.String()); err != nil {
+		return err
+	}
+	return s.passwordService.SendReset(ctx, user)
+}
+
+// Impersonate membuat access token agar superadmin bisa bertindak sebagai user lain.
+// Superadmin lain dan akun yang dinonaktifkan tidak bisa diimpersonasi.
+func (s *AdminService) Impersonate(ctx context.Context, actor *model.Principal, targetUserID string, reason string, ipAddress string) (*sessionModel.TokenPair, error) {
+	if actor.Role != constants.RoleSuperAdmin {
+		return nil, userErr.ErrImpersonationDenied
+	}
+
+	user, err := s.apply(ctx, actor, targetUserID, model.AuditImpersonate, reason, ipAddress, func(tx *gorm.DB, user *model.User) error {
+		if user.Role == constants.RoleSuperAdmin {
+			return userErr.ErrImpersonationDenied
+		}
+		if user.IsSuspended() {
+			return userErr.ErrAccountSuspended
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.Impersonate(ctx, actor.UserID, actor.SessionID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"actor_id":       actor.UserID,
+		"target_user_id": user.ID,
+		"ip_address":     ipAddress,
+	}).Warn("Superadmin memulai impersonasi user")
+	return tokens, nil
+}
+
+// AuditLogs menampilkan tindakan admin terbaru terhadap user atau yang dilakukan user tersebut
+func (s *AdminService) AuditLogs(ctx context.Context, userID string) ([]model.AdminAuditLog, error) {
+	logs := []model.AdminAuditLog{}
+	err := s.db.WithContext(ctx).
+		Where("target_user_id = ? OR actor_id = ?", userID, userID).
+		Order("created_at DESC").Limit(maxAuditLogs).
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// apply memuat target dengan lock, menjalankan perubahan, dan mencatat audit log dalam satu
+// transaksi. Admin tidak bisa menjalankan tindakan ini terhadap akunnya sendiri.
+func (s *AdminService) apply(ctx context.Context, actor *model.Principal, targetUserID string, action string, reason string, ipAddress string, change func(tx *gorm.DB, user *model.User) error) (*model.User, error) {
+	var user model.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", targetUserID).Error; err != nil {
+			return err
+		}
+		if user.ID == actor.UserID {
+			return userErr.ErrSelfAdminAction
+		}
+		if err := change(tx, &user); err != nil {
+			return err
+		}
+		return tx.Create(model.NewAdminAuditLog(actor.UserID, user.ID, action, reason, ipAddress)).Error
+	})
+	if err != nil {
+		if !isAdminRuleError(err) {
+			s.logger.WithFields(logrus.Fields{
+				"actor_id":       actor.UserID,
+				"target_user_id": targetUserID,
+				"action":         action,
+				"error":          err.Error(),
+			}).Error("Gagal menjalankan tindakan admin")
+		}
+		return nil, err
+	}
+
+	user.Password = ""
+	return &user, nil
+}
+
+// isAdminRuleError mengecek error yang berasal dari aturan tindakan admin (bukan kegagalan sistem)
+func isAdminRuleError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) ||
+		errors.Is(err, userErr.ErrSelfAdminAction) ||
+		errors.Is(err, userErr.ErrImpersonationDenied) ||
+		errors.Is(err, userErr.ErrAccountSuspended)
+}
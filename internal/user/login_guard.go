@@ -0,0 +1,198 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"booking/pkg/tenant"
+)
+
+const (
+	// DefaultLoginMaxFailures adalah jumlah gagal login per email sebelum akun dikunci sementara
+	DefaultLoginMaxFailures = 5
+	// DefaultLoginIPMaxFailures adalah jumlah gagal login per IP (semua email) dalam satu window
+	DefaultLoginIPMaxFailures = 50
+	DefaultLoginFailureWindow = 15 * time.Minute
+	DefaultLoginLockout       = 15 * time.Minute
+
+	// loginDelayAfter adalah jumlah gagal sebelum jeda bertahap mulai berlaku
+	loginDelayAfter = 2
+	loginBaseDelay  = time.Second
+	loginMaxDelay   = 30 * time.Second
+)
+
+// AttemptStore menyimpan percobaan login dalam sliding window dan status kunci akun.
+// Implementasi produksi adalah Redis; MemoryAttemptStore dipakai untuk test.
+type AttemptStore interface {
+	RecordAttempt(ctx context.Context, key string, now time.Time, window time.Duration) (int, error)
+	RecentAttempts(ctx context.Context, key string, now time.Time, window time.Duration) (int, time.Time, error)
+	ClearAttempts(ctx context.Context, keys ...string) error
+	Lock(ctx context.Context, key string, until time.Time) error
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+}
+
+// LoginThrottledError dikembalikan jika login ditahan (jeda bertahap, akun dikunci atau IP
+// terlalu banyak gagal). Pesannya sama untuk semua kasus dan untuk email yang tidak terdaftar.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "too many failed login attempts, please try again later"
+}
+
+type LoginGuardConfig struct {
+	MaxFailures   int
+	IPMaxFailures int
+	Window        time.Duration
+	Lockout       time.Duration
+}
+
+// LoginGuard membatasi percobaan login per email (dalam organisasi) dan per IP
+type LoginGuard struct {
+	store AttemptStore
+	cfg   LoginGuardConfig
+	now   func() time.Time
+}
+
+func NewLoginGuard(store AttemptStore, cfg LoginGuardConfig) *LoginGuard {
+	if store == nil {
+		panic("attempt store is required")
+	}
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = DefaultLoginMaxFailures
+	}
+	if cfg.IPMaxFailures <= 0 {
+		cfg.IPMaxFailures = DefaultLoginIPMaxFailures
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultLoginFailureWindow
+	}
+	if cfg.Lockout <= 0 {
+		cfg.Lockout = DefaultLoginLockout
+	}
+
+	return &LoginGuard{
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Check dipanggil sebelum password dicek; mengembalikan *LoginThrottledError jika login ditahan
+func (g *LoginGuard) Check(ctx context.Context, email string, ipAddress string) error {
+	now := g.now()
+	emailKey, lockKey := g.keys(ctx, email)
+
+	until, err := g.store.LockedUntil(ctx, lockKey)
+	if err != nil {
+		return err
+	}
+	if until.After(now) {
+		return &LoginThrottledError{RetryAfter: until.Sub(now)}
+	}
+
+	count, last, err := g.store.RecentAttempts(ctx, emailKey, now, g.cfg.Window)
+	if err != nil {
+		return err
+	}
+	if next := last.Add(loginDelay(count)); count > 0 && next.After(now) {
+		return &LoginThrottledError{RetryAfter: next.Sub(now)}
+	}
+
+	if ipAddress != "" {
+		count, last, err := g.store.RecentAttempts(ctx, ipKey(ipAddress), now, g.cfg.Window)
+		if err != nil {
+			return err
+		}
+		if count >= g.cfg.IPMaxFailures {
+			return &LoginThrottledError{RetryAfter: last.Add(g.cfg.Window).Sub(now)}
+		}
+	}
+	return nil
+}
+
+// Fail mencatat login gagal; locked bernilai true jika kegagalan ini membuat akun terkunci
+func (g *LoginGuard) Fail(ctx context.Context, email string, ipAddress string) (bool, error) {
+	now := g.now()
+	emailKey, lockKey := g.keys(ctx, email)
+
+	if ipAddress != "" {
+		if _, err := g.store.RecordAttempt(ctx, ipKey(ipAddress), now, g.cfg.Window); err != nil {
+			return false, err
+		}
+	}
+
+	count, err := g.store.RecordAttempt(ctx, emailKey, now, g.cfg.Window)
+	if err != nil {
+		return false, err
+	}
+	if count < g.cfg.MaxFailures {
+		return false, nil
+	}
+
+	// counter dikosongkan agar setelah kunci berakhir user mendapat jatah percobaan baru
+	if err := g.store.Lock(ctx, lockKey, now.Add(g.cfg.Lockout)); err != nil {
+		return false, err
+	}
+	return true, g.store.ClearAttempts(ctx, emailKey)
+}
+
+// Succeed mengosongkan counter email setelah login berhasil; counter IP tetap berjalan
+func (g *LoginGuard) Succeed(ctx context.Context, email string) error {
+	emailKey, _ := g.keys(ctx, email)
+	return g.store.ClearAttempts(ctx, emailKey)
+}
+
+// Unlock membuka kunci akun dan mengosongkan counter-nya (dipakai admin)
+func (g *LoginGuard) Unlock(ctx context.Context, email string) error {
+	emailKey, lockKey := g.keys(ctx, email)
+	return g.store.ClearAttempts(ctx, emailKey, lockKey)
+}
+
+// LockedUntil mengembalikan waktu berakhirnya kunci akun; waktu nol jika tidak terkunci
+func (g *LoginGuard) LockedUntil(ctx context.Context, email string) (time.Time, error) {
+	_, lockKey := g.keys(ctx, email)
+	until, err := g.store.LockedUntil(ctx, lockKey)
+	if err != nil || !until.After(g.now()) {
+		return time.Time{}, err
+	}
+	return until, nil
+}
+
+// LockoutDuration adalah lama akun dikunci setelah terlalu banyak gagal
+func (g *LoginGuard) LockoutDuration() time.Duration {
+	return g.cfg.Lockout
+}
+
+// keys membentuk key counter dan kunci per email; email sama di organisasi lain adalah akun lain
+func (g *LoginGuard) keys(ctx context.Context, email string) (string, string) {
+	organization := "-"
+	if id, ok := tenant.FromContext(ctx); ok {
+		organization = id.String()
+	}
+	subject := fmt.Sprintf("%s:%s", organization, strings.ToLower(strings.TrimSpace(email)))
+	return "login:fail:" + subject, "login:lock:" + subject
+}
+
+func ipKey(ipAddress string) string {
+	return "login:ip:" + ipAddress
+}
+
+// loginDelay adalah jeda minimal sejak gagal terakhir: 0 sampai loginDelayAfter kegagalan,
+// lalu berlipat dua setiap kegagalan berikutnya sampai loginMaxDelay
+func loginDelay(failures int) time.Duration {
+	if failures < loginDelayAfter {
+		return 0
+	}
+	delay := loginBaseDelay
+	for i := loginDelayAfter; i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > loginMaxDelay {
+		return loginMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"booking/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginGuardTestSuite struct {
+	suite.Suite
+	store *MemoryAttemptStore
+	guard *LoginGuard
+	now   time.Time
+	ctx   context.Context
+}
+
+func TestLoginGuardSuite(t *testing.T) {
+	suite.Run(t, new(LoginGuardTestSuite))
+}
+
+func (s *LoginGuardTestSuite) SetupTest() {
+	s.store = NewMemoryAttemptStore()
+	s.guard = NewLoginGuard(s.store, LoginGuardConfig{MaxFailures: 4, IPMaxFailures: 6, Window: 15 * time.Minute, Lockout: 10 * time.Minute})
+	s.now = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	s.guard.now = func() time.Time { return s.now }
+	s.ctx = tenant.WithOrganization(context.Background(), uuid.New())
+}
+
+func (s *LoginGuardTestSuite) fail(email, ip string) bool {
+	locked, err := s.guard.Fail(s.ctx, email, ip)
+	s.Require().NoError(err)
+	return locked
+}
+
+func (s *LoginGuardTestSuite) retryAfter(err error) time.Duration {
+	var throttled *LoginThrottledError
+	s.Require().True(errors.As(err, &throttled), "expected throttled error, got %v", err)
+	return throttled.RetryAfter
+}
+
+func (s *LoginGuardTestSuite) TestProgressiveDelayThenLockout() {
+	s.False(s.fail("ana@example.com", "10.0.0.1"))
+	s.NoError(s.guard.Check(s.ctx, "ana@example.com", "10.0.0.1"))
+
+	s.False(s.fail("ana@example.com", "10.0.0.1"))
+	s.Equal(time.Second, s.retryAfter(s.guard.Check(s.ctx, "ana@example.com", "10.0.0.1")))
+
+	s.now = s.now.Add(time.Second)
+	s.NoError(s.guard.Check(s.ctx, "ana@example.com", "10.0.0.1"))
+	s.False(s.fail("ana@example.com", "10.0.0.1"))
+	s.Equal(2*time.Second, s.retryAfter(s.guard.Check(s.ctx, "ana@example.com", "10.0.0.1")))
+
+	s.now = s.now.Add(2 * time.Second)
+	s.True(s.fail("ANA@example.com ", "10.0.0.1"))
+	s.Equal(10*time.Minute, s.retryAfter(s.guard.Check(s.ctx, "ana@example.com", "10.0.0.2")))
+
+	until, err := s.guard.LockedUntil(s.ctx, "ana@example.com")
+	s.NoError(err)
+	s.Equal(s.now.Add(10*time.Minute), until)
+
+	// setelah kunci berakhir user mendapat jatah percobaan baru
+	s.now = s.now.Add(10 * time.Minute)
+	s.NoError(s.guard.Check(s.ctx, "ana@example.com", "10.0.0.3"))
+}
+
+func (s *LoginGuardTestSuite) TestSlidingWindowForgetsOldFailures() {
+	s.fail("ana@example.com", "")
+	s.fail("ana@example.com", "")
+	s.fail("ana@example.com", "")
+
+	s.now = s.now.Add(16 * time.Minute)
+	s.NoError(s.guard.Check(s.ctx, "ana@example.com", ""))
+	s.False(s.fail("ana@example.com", ""))
+}
+
+func (s *LoginGuardTestSuite) TestIPLimitAcrossEmails() {
+	for i := 0; i < 6; i++ {
+		s.fail(uuid.NewString()+"@example.com", "10.0.0.9")
+	}
+
+	s.Error(s.guard.Check(s.ctx, "fresh@example.com", "10.0.0.9"))
+	s.NoError(s.guard.Check(s.ctx, "fresh@example.com", "10.0.0.10"))
+}
+
+func (s *LoginGuardTestSuite) TestSucceedAndUnlock() {
+	s.fail("ana@example.com", "")
+	s.fail("ana@example.com", "")
+	s.NoError(s.guard.Succeed(s.ctx, "ana@example.com"))
+	s.NoError(s.guard.Check(s.ctx, "ana@example.com", ""))
+
+	for i := 0; i < 4; i++ {
+		s.now = s.now.Add(time.Minute)
+		s.fail("ana@example.com", "")
+	}
+	s.Error(s.guard.Check(s.ctx, "ana@example.com", ""))
+
+	s.NoError(s.guard.Unlock(s.ctx, "ana@example.com"))
+	s.NoError(s.guard.Check(s.ctx, "ana@example.com", ""))
+}
+
+func (s *LoginGuardTestSuite) TestCountersArePerOrganization() {
+	for i := 0; i < 4; i++ {
+		s.now = s.now.Add(time.Minute)
+		s.fail("ana@example.com", "")
+	}
+
+	other := tenant.WithOrganization(context.Background(), uuid.New())
+	s.Error(s.guard.Check(s.ctx, "ana@example.com", ""))
+	s.NoError(s.guard.Check(other, "ana@example.com", ""))
+}
+
+func (s *LoginGuardTestSuite) TestLoginDelay() {
+	s.Equal(time.Duration(0), loginDelay(1))
+	s.Equal(time.Second, loginDelay(2))
+	s.Equal(4*time.Second, loginDelay(4))
+	s.Equal(loginMaxDelay, loginDelay(40))
+}
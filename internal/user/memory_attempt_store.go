@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryAttemptStore menyimpan percobaan login di memori proses; untuk test dan pengembangan
+// lokal saja karena tidak dibagi antar instance
+type MemoryAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+	locks    map[string]time.Time
+}
+
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{
+		attempts: map[string][]time.Time{},
+		locks:    map[string]time.Time{},
+	}
+}
+
+func (m *MemoryAttemptStore) RecordAttempt(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.attempts[key] = append(m.prune(key, now, window), now)
+	return len(m.attempts[key]), nil
+}
+
+func (m *MemoryAttemptStore) RecentAttempts(ctx context.Context, key string, now time.Time, window time.Duration) (int, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	recent := m.prune(key, now, window)
+	if len(recent) == 0 {
+		return 0, time.Time{}, nil
+	}
+	return len(recent), recent[len(recent)-1], nil
+}
+
+func (m *MemoryAttemptStore) ClearAttempts(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.attempts, key)
+		delete(m.locks, key)
+	}
+	return nil
+}
+
+func (m *MemoryAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locks[key] = until
+	return nil
+}
+
+func (m *MemoryAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.locks[key], nil
+}
+
+// prune membuang percobaan di luar window; mu harus sudah dikunci
+func (m *MemoryAttemptStore) prune(key string, now time.Time, window time.Duration) []time.Time {
+	cutoff := now.Add(-window)
+	recent := m.attempts[key][:0]
+	for _, at := range m.attempts[key] {
+		if !at.Before(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) == 0 {
+		delete(m.attempts, key)
+		return nil
+	}
+	m.attempts[key] = recent
+	return recent
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+
+	DefaultUsersPerPage = 20
+	MaxUsersPerPage     = 100
+)
+
+// Aksi admin yang dicatat di audit log
+const (
+	AuditSuspend            = "suspend"
+	AuditUnsuspend          = "unsuspend"
+	AuditForcePasswordReset = "force_password_reset"
+	AuditImpersonate        = "impersonate"
+
+	maxAuditReasonLen    = 255
+	maxAuditIPAddressLen = 45
+)
+
+// IsSuspended mengecek apakah akun sedang dinonaktifkan admin
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+func (u *User) Suspend(reason string, now time.Time) {
+	u.SuspendedAt = &now
+	u.SuspensionReason = truncate(strings.TrimSpace(reason), maxAuditReasonLen)
+}
+
+func (u *User) Unsuspend() {
+	u.SuspendedAt = nil
+	u.SuspensionReason = ""
+}
+
+// UserSearchInput adalah query string pencarian user di halaman admin; q dicocokkan ke
+// nama atau email
+type UserSearchInput struct {
+	Q       string `query:"q"`
+	Role    string `query:"role"`
+	Status  string `query:"status"`
+	Page    int    `query:"page"`
+	PerPage int    `query:"per_page"`
+}
+
+// UserFilter adalah UserSearchInput yang sudah divalidasi
+type UserFilter struct {
+	Query   string
+	Role    constants.Role
+	Status  string
+	Page    int
+	PerPage int
+}
+
+type UserSearchResult struct {
+	Data       []User `json:"data"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+}
+
+// Filter memvalidasi input dan mengisi nilai default
+func (in UserSearchInput) Filter() (*UserFilter, error) {
+	filter := &UserFilter{
+		Query:   strings.TrimSpace(in.Q),
+		Role:    constants.Role(strings.TrimSpace(in.Role)),
+		Status:  strings.TrimSpace(in.Status),
+		Page:    in.Page,
+		PerPage: in.PerPage,
+	}
+
+	switch filter.Status {
+	case "", UserStatusActive, UserStatusSuspended:
+	default:
+		return nil, errors.New("invalid status, use active or suspended")
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = DefaultUsersPerPage
+	}
+	if filter.PerPage > MaxUsersPerPage {
+		filter.PerPage = MaxUsersPerPage
+	}
+	return filter, nil
+}
+
+func (f *UserFilter) Offset() int {
+	return (f.Page - 1) * f.PerPage
+}
+
+// LikePattern membuat pola LIKE untuk Query; karakter wildcard dari input di-escape
+func (f *UserFilter) LikePattern() string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(f.Query)
+	return "%" + escaped + "%"
+}
+
+func NewUserSearchResult(data []User, filter *UserFilter, total int64) *UserSearchResult {
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+	return &UserSearchResult{
+		Data:       data,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// AdminAuditLog mencatat tindakan admin terhadap akun user lain
+type AdminAuditLog struct {
+	ID             uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:char(36);not null;index"`
+	ActorID        uuid.UUID `json:"actor_id" gorm:"type:char(36);not null;index"`
+	TargetUserID   uuid.UUID `json:"target_user_id" gorm:"type:char(36);not null;index"`
+	Action         string    `json:"action" gorm:"size:32;not null"`
+	Reason         string    `json:"reason" gorm:"size:255"`
+	IPAddress      string    `json:"ip_address" gorm:"size:45"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+}
+
+func NewAdminAuditLog(actorID, targetUserID uuid.UUID, action, reason, ipAddress string) *AdminAuditLog {
+	return &AdminAuditLog{
+		ID:           uuid.New(),
+		ActorID:      actorID,
+		TargetUserID: targetUserID,
+		Action:       action,
+		Reason:       truncate(strings.TrimSpace(reason), maxAuditReasonLen),
+		IPAddress:    truncate(ipAddress, maxAuditIPAddressLen),
+	}
+}
+
+// DTO: alasan tindakan admin (dicatat di audit log)
+type AdminActionInput struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
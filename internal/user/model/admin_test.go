@@ -0,0 +1,70 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminTestSuite struct {
+	suite.Suite
+}
+
+func TestAdminSuite(t *testing.T) {
+	suite.Run(t, new(AdminTestSuite))
+}
+
+func (s *AdminTestSuite) TestUserSearchFilter() {
+	filter, err := UserSearchInput{Q: "  budi ", Role: "host", PerPage: 500}.Filter()
+	s.Require().NoError(err)
+	s.Equal("budi", filter.Query)
+	s.Equal("host", string(filter.Role))
+	s.Equal(1, filter.Page)
+	s.Equal(MaxUsersPerPage, filter.PerPage)
+	s.Equal(0, filter.Offset())
+
+	filter, err = UserSearchInput{Status: UserStatusSuspended, Page: 3}.Filter()
+	s.Require().NoError(err)
+	s.Equal(DefaultUsersPerPage, filter.PerPage)
+	s.Equal(40, filter.Offset())
+
+	_, err = UserSearchInput{Status: "deleted"}.Filter()
+	s.Error(err)
+}
+
+func (s *AdminTestSuite) TestLikePatternEscapesWildcards() {
+	filter := &UserFilter{Query: `50%_off\`}
+	s.Equal(`%50\%\_off\\%`, filter.LikePattern())
+}
+
+func (s *AdminTestSuite) TestUserSearchResultPages() {
+	filter := &UserFilter{Page: 2, PerPage: 20}
+	result := NewUserSearchResult([]User{}, filter, 41)
+	s.Equal(3, result.TotalPages)
+	s.Equal(int64(41), result.Total)
+}
+
+func (s *AdminTestSuite) TestSuspend() {
+	user := &User{}
+	s.False(user.IsSuspended())
+
+	user.Suspend("  spam  ", time.Now())
+	s.True(user.IsSuspended())
+	s.Equal("spam", user.SuspensionReason)
+
+	user.Unsuspend()
+	s.False(user.IsSuspended())
+	s.Empty(user.SuspensionReason)
+}
+
+func (s *AdminTestSuite) TestNewAdminAuditLog() {
+	actor, target := uuid.New(), uuid.New()
+	log := NewAdminAuditLog(actor, target, AuditImpersonate, strings.Repeat("x", 300), "127.0.0.1")
+	s.NotEqual(uuid.Nil, log.ID)
+	s.Equal(actor, log.ActorID)
+	s.Equal(target, log.TargetUserID)
+	s.Len(log.Reason, 255)
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity menghubungkan user dengan akun di provider OpenID Connect (misalnya Google).
+// Subject dari provider bersifat tetap, sedangkan email bisa berubah.
+type UserIdentity struct {
+	ID             uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:char(36);not null;uniqueIndex:idx_user_identities_provider_subject,priority:1"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:char(36);not null;index"`
+	Provider       string    `json:"provider" gorm:"size:50;not null;uniqueIndex:idx_user_identities_provider_subject,priority:2"`
+	Subject        string    `json:"subject" gorm:"size:191;not null;uniqueIndex:idx_user_identities_provider_subject,priority:3"`
+	Email          string    `json:"email" gorm:"size:191"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ExternalProfile adalah data user dari ID token provider yang sudah diverifikasi
+type ExternalProfile struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+func NewUserIdentity(userID uuid.UUID, profile ExternalProfile) *UserIdentity {
+	return &UserIdentity{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Provider:  profile.Provider,
+		Subject:   profile.Subject,
+		Email:     profile.Email,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewExternalUser membuat user dari profil provider. User ini tidak punya password dan
+// emailnya sudah diverifikasi provider; password bisa dibuat lewat lupa password.
+func NewExternalUser(profile ExternalProfile, now time.Time) *User {
+	name := strings.TrimSpace(profile.Name)
+	if len([]rune(name)) < 2 {
+		name = strings.Split(profile.Email, "@")[0]
+	}
+	if runes := []rune(name); len(runes) > 50 {
+		name = string(runes[:50])
+	}
+
+	return &User{
+		ID:              uuid.New(),
+		Name:            name,
+		Email:           profile.Email,
+		Role:            constants.RoleUser,
+		Locale:          constants.DefaultLocale,
+		EmailVerifiedAt: &now,
+	}
+}
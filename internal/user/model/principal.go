@@ -0,0 +1,41 @@
+package model
+
+import (
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+// Principal adalah identitas user yang sedang login. Untuk access token diisi dari claims
+// sehingga middleware dan handler tidak perlu membaca user dari database di setiap request.
+// SessionID dan TokenID kosong untuk request dengan API key. ImpersonatorID terisi jika
+// request memakai token impersonasi.
+type Principal struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+	Role           constants.Role
+	TwoFactor      bool
+	SessionID      string
+	TokenID        string
+	ImpersonatorID uuid.UUID
+}
+
+// Principal membuat principal dari data user terbaru (dipakai untuk API key)
+func (u *User) Principal() *Principal {
+	return &Principal{
+		UserID:         u.ID,
+		OrganizationID: u.OrganizationID,
+		Role:           u.Role,
+		TwoFactor:      u.TwoFactorEnabled(),
+	}
+}
+
+// Impersonated mengecek apakah request dilakukan admin atas nama user ini
+func (p *Principal) Impersonated() bool {
+	return p.ImpersonatorID != uuid.Nil
+}
+
+// TwoFactorEnabled mengecek apakah user sudah mengaktifkan 2FA saat token diterbitkan
+func (p *Principal) TwoFactorEnabled() bool {
+	return p.TwoFactor
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorTestSuite struct {
+	suite.Suite
+}
+
+func TestTwoFactorSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
+
+func (s *TwoFactorTestSuite) TestNewRecoveryCodes() {
+	userID := uuid.New()
+	codes, raws, err := NewRecoveryCodes(userID, time.Now())
+	s.Require().NoError(err)
+	s.Len(codes, RecoveryCodeCount)
+	s.Len(raws, RecoveryCodeCount)
+
+	seen := map[string]bool{}
+	for i, raw := range raws {
+		s.Len(raw, 9)
+		s.Equal(byte('-'), raw[4])
+		s.Equal(userID, codes[i].UserID)
+		s.Equal(HashRecoveryCode(raw), codes[i].CodeHash)
+		s.NotContains(codes[i].CodeHash, strings.ReplaceAll(raw, "-", ""))
+		s.False(seen[raw])
+		seen[raw] = true
+	}
+}
+
+func (s *TwoFactorTestSuite) TestHashRecoveryCodeIgnoresFormatting() {
+	s.Equal(HashRecoveryCode("abcd-efgh"), HashRecoveryCode("ABCD EFGH"))
+	s.Equal(HashRecoveryCode("abcd-efgh"), HashRecoveryCode("abcdefgh"))
+	s.NotEqual(HashRecoveryCode("abcd-efgh"), HashRecoveryCode("abcd-efgi"))
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactorEnabled() {
+	user := &User{TwoFactorSecret: "SECRET"}
+	s.False(user.TwoFactorEnabled())
+
+	now := time.Now()
+	user.TwoFactorEnabledAt = &now
+	s.True(user.TwoFactorEnabled())
+}
@@ -10,14 +10,28 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// User menyimpan akun dalam satu organisasi. TwoFactorSecret terisi sejak setup 2FA, tetapi
+// 2FA baru aktif setelah dikonfirmasi (TwoFactorEnabledAt). TwoFactorLastStep adalah langkah
+// TOTP terakhir yang dipakai agar kode yang sama tidak bisa dipakai dua kali. Akun yang
+// SuspendedAt-nya terisi tidak bisa login; PasswordResetRequired diisi admin dan menolak
+// login dengan password sampai user mereset password.
 type User struct {
-	ID        uuid.UUID      `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
-	Name      string         `json:"name" gorm:"size:50" validate:"required,min=2,max=50"`
-	Email     string         `json:"email" gorm:"unique" validate:"required,email"`
-	Password  string         `json:"-"`
-	Role      constants.Role `json:"role"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID                    uuid.UUID        `json:"id" gorm:"type:char(36);primary_key;default:(UUID())"`
+	OrganizationID        uuid.UUID        `json:"organization_id" gorm:"type:char(36);not null;uniqueIndex:idx_users_org_email,priority:1"`
+	Name                  string           `json:"name" gorm:"size:50" validate:"required,min=2,max=50"`
+	Email                 string           `json:"email" gorm:"size:191;uniqueIndex:idx_users_org_email,priority:2" validate:"required,email"`
+	Password              string           `json:"-"`
+	Role                  constants.Role   `json:"role"`
+	Locale                constants.Locale `json:"locale" gorm:"type:varchar(5);not null;default:'id'"`
+	EmailVerifiedAt       *time.Time       `json:"email_verified_at"`
+	TwoFactorSecret       string           `json:"-" gorm:"size:64"`
+	TwoFactorEnabledAt    *time.Time       `json:"two_factor_enabled_at"`
+	TwoFactorLastStep     int64            `json:"-" gorm:"not null;default:0"`
+	SuspendedAt           *time.Time       `json:"suspended_at"`
+	SuspensionReason      string           `json:"suspension_reason,omitempty" gorm:"size:255"`
+	PasswordResetRequired bool             `json:"password_reset_required" gorm:"not null;default:false"`
+	CreatedAt             time.Time        `json:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at"`
 }
 
 // DTO: Register input
@@ -25,6 +39,7 @@ type RegisterInput struct {
 	Name     string `json:"name" validate:"required,min=2,max=50"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	Locale   string `json:"locale" validate:"omitempty,oneof=id en"`
 }
 
 // DTO: Login input
@@ -33,12 +48,34 @@ type LoginInput struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// DTO: Verify email input
+type VerifyEmailInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // DTO: Update profile input
 type UpdateProfileInput struct {
-	Name     string `json:"name" validate:"required,min=2,max=50"`
-	Email    string `json:"email" validate:"omitempty,email"`
-	Role     string `json:"role" validate:"omitempty`
-	Password string `json:"password,omitempty" validate:"omitempty,min=6"`
+	Name   string `json:"name" validate:"required,min=2,max=50"`
+	Email  string `json:"email" validate:"omitempty,email"`
+	Role   string `json:"role" validate:"omitempty"`
+	Locale string `json:"locale,omitempty" validate:"omitempty,oneof=id en"`
+}
+
+// DTO: Forgot password input
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// DTO: Reset password input
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// DTO: Change password input
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
 }
 
 // Factory: Create new user from register input
@@ -48,10 +85,14 @@ func NewUser(input RegisterInput) (*User, error) {
 	}
 
 	user := &User{
-		ID:    uuid.New(),
-		Name:  input.Name,
-		Email: input.Email,
-		Role:  constants.RoleUser,
+		ID:     uuid.New(),
+		Name:   input.Name,
+		Email:  input.Email,
+		Role:   constants.RoleUser,
+		Locale: constants.DefaultLocale,
+	}
+	if input.Locale != "" {
+		user.Locale = constants.Locale(input.Locale)
 	}
 
 	if err := user.SetPassword(input.Password); err != nil {
@@ -87,3 +128,16 @@ func (u *User) IsAdmin() bool {
 func (u *User) IsSuperAdmin() bool {
 	return u.Role == constants.RoleSuperAdmin
 }
+func (u *User) IsHost() bool {
+	return u.Role == constants.RoleHost
+}
+
+// IsEmailVerified mengecek apakah user sudah memverifikasi email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// MarkEmailVerified menandai email sudah diverifikasi
+func (u *User) MarkEmailVerified(now time.Time) {
+	u.EmailVerifiedAt = &now
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"booking/shared/constants"
 	errs "booking/shared/errors"
@@ -177,3 +178,17 @@ func (s *UserTestSuite) TestIsAdmin() {
 		})
 	}
 }
+
+func (s *UserTestSuite) TestNewExternalUser() {
+	now := time.Now()
+
+	user := NewExternalUser(ExternalProfile{Provider: "google", Subject: "123", Email: "ana@example.com", Name: "Ana Putri"}, now)
+	s.Equal("Ana Putri", user.Name)
+	s.Equal(constants.RoleUser, user.Role)
+	s.Empty(user.Password)
+	s.True(user.IsEmailVerified())
+
+	// nama kosong diganti bagian depan email
+	user = NewExternalUser(ExternalProfile{Email: "budi@example.com"}, now)
+	s.Equal("budi", user.Name)
+}
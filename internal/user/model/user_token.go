@@ -0,0 +1,58 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPurpose membedakan kegunaan token yang dikirim lewat email
+type TokenPurpose string
+
+const (
+	PurposeEmailVerification TokenPurpose = "email_verification"
+	PurposePasswordReset     TokenPurpose = "password_reset"
+)
+
+// UserToken mencatat token sekali pakai yang dikirim ke email user. Token yang dikirim
+// ke user adalah JWT bertanda tangan berisi ID baris ini; baris ini hanya menyimpan hash
+// token, memastikan token hanya bisa dipakai sekali dan bisa dibatalkan ketika token baru dikirim.
+type UserToken struct {
+	ID             uuid.UUID    `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID    `json:"organization_id" gorm:"type:char(36);not null;index"`
+	UserID         uuid.UUID    `json:"user_id" gorm:"type:char(36);not null;index:idx_user_tokens_user_purpose,priority:1"`
+	Purpose        TokenPurpose `json:"purpose" gorm:"type:varchar(30);not null;index:idx_user_tokens_user_purpose,priority:2"`
+	TokenHash      string       `json:"-" gorm:"type:char(64);not null;default:''"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	UsedAt         *time.Time   `json:"used_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+func NewUserToken(userID uuid.UUID, purpose TokenPurpose, ttl time.Duration, now time.Time) *UserToken {
+	return &UserToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// Usable mengecek apakah token belum dipakai dan belum kedaluwarsa
+func (t *UserToken) Usable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// Matches membandingkan token dengan hash yang tersimpan
+func (t *UserToken) Matches(raw string) bool {
+	return t.TokenHash != "" && subtle.ConstantTimeCompare([]byte(t.TokenHash), []byte(HashToken(raw))) == 1
+}
+
+// HashToken menghitung hash SHA-256 (hex) dari token rahasia
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/session"
+	"booking/pkg/response"
+	"booking/pkg/tenant"
+	errs "booking/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OIDCHandler struct {
+	oidcService      OIDCServiceInterface
+	sessionService   session.SessionServiceInterface
+	twoFactorService TwoFactorServiceInterface
+}
+
+func NewOIDCHandler(oidcService OIDCServiceInterface, sessionService session.SessionServiceInterface, twoFactorService TwoFactorServiceInterface) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService:      oidcService,
+		sessionService:   sessionService,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Providers menampilkan provider login yang tersedia
+func (h *OIDCHandler) Providers(c echo.Context) error {
+	return response.Success(c, http.StatusOK, "Login providers retrieved successfully", h.oidcService.Providers())
+}
+
+// Begin mengarahkan browser ke halaman login provider
+func (h *OIDCHandler) Begin(c echo.Context) error {
+	authURL, err := h.oidcService.Begin(c.Request().Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, errs.ErrUnknownProvider) {
+			return response.NotFound(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to start login", err)
+	}
+
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback menerima redirect dari provider lalu membuat session seperti login biasa
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		return response.BadRequest(c, "login was cancelled or rejected by the provider", errors.New(providerErr))
+	}
+	state, code := c.QueryParam("state"), c.QueryParam("code")
+	if state == "" || code == "" {
+		return response.BadRequest(c, "state and code are required", nil)
+	}
+
+	user, err := h.oidcService.Complete(c.Request().Context(), c.Param("provider"), state, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUnknownProvider):
+			return response.NotFound(c, err.Error(), err)
+		case errors.Is(err, errs.ErrInvalidOAuthState), errors.Is(err, errs.ErrInvalidToken):
+			return response.Unauthorized(c, err.Error(), err)
+		case errors.Is(err, errs.ErrProviderEmailInvalid):
+			return response.Forbidden(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to complete login", err)
+	}
+
+	if user.IsSuspended() {
+		return response.Forbidden(c, errs.ErrAccountSuspended.Error(), errs.ErrAccountSuspended)
+	}
+
+	// User dengan 2FA tetap harus memasukkan kode lewat /login/2fa
+	if user.TwoFactorEnabled() {
+		challenge, err := h.twoFactorService.Challenge(user)
+		if err != nil {
+			return response.InternalServerError(c, "failed to create two-factor challenge", err)
+		}
+		return response.Success(c, http.StatusOK, "Two-factor authentication required", challenge)
+	}
+
+	// Callback tidak lewat tenant middleware; organisasi diambil dari user
+	ctx := tenant.WithOrganization(c.Request().Context(), user.OrganizationID)
+	tokens, err := h.sessionService.Start(ctx, user.ID, session.Device(c))
+	if err != nil {
+		return startSessionError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Login successful", tokens)
+}
@@ -0,0 +1,231 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"booking/internal/session"
+	sessionModel "booking/internal/session/model"
+	"booking/internal/user/model"
+	"booking/pkg/logger"
+	"booking/pkg/oidc"
+	"booking/pkg/redis"
+	"booking/pkg/tenant"
+	userErr "booking/shared/errors"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// oauthStateTTL adalah waktu yang diberikan untuk menyelesaikan login di provider
+const oauthStateTTL = 10 * time.Minute
+
+// OIDCServiceInterface mendefinisikan kontrak untuk OIDCService
+type OIDCServiceInterface interface {
+	Providers() []string
+	Begin(ctx context.Context, provider string) (string, error)
+	Complete(ctx context.Context, provider string, state string, code string) (*model.User, error)
+}
+
+// oauthState disimpan di Redis selama login berjalan; organisasi diambil dari sini saat
+// callback karena redirect dari provider tidak membawa subdomain / header organisasi
+type oauthState struct {
+	Provider       string    `json:"provider"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Nonce          string    `json:"nonce"`
+	Verifier       string    `json:"verifier"`
+}
+
+type OIDCService struct {
+	db             *gorm.DB
+	redisClient    *redis.RedisClient
+	logger         logger.Logger
+	sessionService session.SessionServiceInterface
+	providers      map[string]*oidc.Provider
+}
+
+func NewOIDCService(db *gorm.DB, redisClient *redis.RedisClient, logger logger.Logger, sessionService session.SessionServiceInterface, providers []*oidc.Provider) *OIDCService {
+	byName := make(map[string]*oidc.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &OIDCService{
+		db:             db,
+		redisClient:    redisClient,
+		logger:         logger,
+		sessionService: sessionService,
+		providers:      byName,
+	}
+}
+
+// Providers mengembalikan nama provider yang dikonfigurasi
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Begin menyiapkan state, nonce dan verifier PKCE lalu mengembalikan URL login di provider
+func (s *OIDCService) Begin(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", userErr.ErrUnknownProvider
+	}
+	organizationID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", errors.New("organization is required")
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	stored := oauthState{Provider: providerName, OrganizationID: organizationID}
+	if stored.Nonce, err = oidc.RandomString(); err != nil {
+		return "", err
+	}
+	if stored.Verifier, err = oidc.RandomString(); err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthURL(ctx, state, stored.Nonce, stored.Verifier)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := json.Marshal(stored)
+	if err != nil {
+		return "", err
+	}
+	if err := s.redisClient.SetOAuthState(ctx, state, string(value), oauthStateTTL); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// Complete menyelesaikan callback: state dipakai sekali, code ditukar dengan ID token, lalu
+// ID token diverifikasi sebelum user ditautkan atau dibuat
+func (s *OIDCService) Complete(ctx context.Context, providerName string, state string, code string) (*model.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, userErr.ErrUnknownProvider
+	}
+
+	raw, err := s.redisClient.TakeOAuthState(ctx, state)
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, userErr.ErrInvalidOAuthState
+		}
+		return nil, err
+	}
+	var stored oauthState
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil || stored.Provider != providerName {
+		return nil, userErr.ErrInvalidOAuthState
+	}
+	ctx = tenant.WithOrganization(ctx, stored.OrganizationID)
+
+	token, err := provider.Exchange(ctx, code, stored.Verifier)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"provider": providerName,
+			"error":    err.Error(),
+		}).Error("Gagal menukar authorization code")
+		return nil, userErr.ErrInvalidOAuthState
+	}
+	claims, err := provider.Verify(ctx, token.IDToken, stored.Nonce)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"provider": providerName,
+			"error":    err.Error(),
+		}).Warn("ID token tidak valid")
+		return nil, userErr.ErrInvalidToken
+	}
+
+	return s.link(ctx, model.ExternalProfile{
+		Provider:      providerName,
+		Subject:       claims.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	})
+}
+
+// link mencari user dari identitas provider; jika belum ada, menautkan ke user dengan email
+// yang sama atau membuat user baru. Email harus sudah diverifikasi provider.
+func (s *OIDCService) link(ctx context.Context, profile model.ExternalProfile) (*model.User, error) {
+	var (
+		user      model.User
+		takenOver bool
+	)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var identity model.UserIdentity
+		err := tx.Where("provider = ? AND subject = ?", profile.Provider, profile.Subject).First(&identity).Error
+		if err == nil {
+			return tx.First(&user, "id = ?", identity.UserID).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if profile.Email == "" || !profile.EmailVerified {
+			return userErr.ErrProviderEmailInvalid
+		}
+
+		err = tx.Where("email = ?", profile.Email).First(&user).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			user = *model.NewExternalUser(profile, time.Now())
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case !user.IsEmailVerified():
+			// Akun lokal yang belum diverifikasi bisa saja didaftarkan orang lain dengan email
+			// ini; pemilik email yang sebenarnya mengambil alih, password dan 2FA lama dihapus
+			user.Password = ""
+			user.TwoFactorSecret = ""
+			user.TwoFactorEnabledAt = nil
+			user.MarkEmailVerified(time.Now())
+			err := tx.Model(&user).Updates(map[string]interface{}{
+				"password":              "",
+				"two_factor_secret":     "",
+				"two_factor_enabled_at": nil,
+				"email_verified_at":     user.EmailVerifiedAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error; err != nil {
+				return err
+			}
+			takenOver = true
+		}
+
+		return tx.Create(model.NewUserIdentity(user.ID, profile)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if takenOver {
+		s.logger.WithFields(logrus.Fields{
+			"user_id":  user.ID,
+			"provider": profile.Provider,
+		}).Warn("Akun belum terverifikasi diambil alih oleh pemilik email lewat provider login")
+		if err := s.sessionService.RevokeAll(ctx, user.ID.String(), sessionModel.RevokeAccountLink); err != nil {
+			return nil, err
+		}
+	}
+	return &user, nil
+}
@@ -0,0 +1,186 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"booking/internal/notification"
+	notificationModel "booking/internal/notification/model"
+	"booking/internal/session"
+	sessionModel "booking/internal/session/model"
+	"booking/internal/user/model"
+	"booking/pkg/logger"
+	"booking/pkg/mailer"
+	"booking/pkg/tenant"
+	"booking/shared/constants"
+	userErr "booking/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// passwordResetTTL adalah masa berlaku link reset password
+const passwordResetTTL = time.Hour
+
+// PasswordServiceInterface mendefinisikan kontrak untuk PasswordService
+type PasswordServiceInterface interface {
+	Forgot(ctx context.Context, email string) error
+	Reset(ctx context.Context, token string, password string) error
+	Change(ctx context.Context, userID string, sessionID string, input model.ChangePasswordInput) error
+	SendReset(ctx context.Context, user *model.User) error
+}
+
+type PasswordService struct {
+	db             *gorm.DB
+	logger         logger.Logger
+	mailer         mailer.Mailer
+	sessionService session.SessionServiceInterface
+	tokens         accountTokens
+	appURL         string
+}
+
+func NewPasswordService(db *gorm.DB, logger logger.Logger, mailer mailer.Mailer, sessionService session.SessionServiceInterface, jwtSecret string, appURL string) *PasswordService {
+	if jwtSecret == "" {
+		panic("jwt secret is required")
+	}
+
+	return &PasswordService{
+		db:             db,
+		logger:         logger,
+		mailer:         mailer,
+		sessionService: sessionService,
+		tokens:         accountTokens{secret: jwtSecret},
+		appURL:         strings.TrimRight(appURL, "/"),
+	}
+}
+
+// Forgot mengirim link reset password. Email yang tidak terdaftar atau permintaan yang
+// terlalu sering diabaikan tanpa error agar endpoint tidak bisa dipakai menebak akun.
+func (s *PasswordService) Forgot(ctx context.Context, email string) error {
+	var user model.User
+	if err := s.db.WithContext(ctx).Where("email = ?", strings.TrimSpace(email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := s.tokens.throttle(s.db.WithContext(ctx), user.ID, model.PurposePasswordReset); err != nil {
+		if errors.Is(err, userErr.ErrTooManyRequests) {
+			s.logger.WithFields(logrus.Fields{
+				"user_id": user.ID,
+			}).Warn("Permintaan reset password terlalu sering")
+			return nil
+		}
+		return err
+	}
+
+	return s.SendReset(ctx, &user)
+}
+
+// SendReset membuat token reset password dan mengirim link-nya ke email user tanpa batasan
+// frekuensi (dipakai Forgot setelah throttle dan reset paksa oleh admin)
+func (s *PasswordService) SendReset(ctx context.Context, user *model.User) error {
+	var raw string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		raw, err = s.tokens.issue(tx, user, model.PurposePasswordReset, passwordResetTTL)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	locale := user.Locale
+	if locale == "" {
+		locale = constants.DefaultLocale
+	}
+	subject, body, err := notification.RenderAccount(constants.EventPasswordReset, locale, notificationModel.AccountMessageData{
+		Name:      user.Name,
+		Link:      s.appURL + "/reset-password?token=" + url.QueryEscape(raw),
+		ExpiresIn: formatTTL(locale, passwordResetTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, mailer.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal mengirim email reset password")
+		return err
+	}
+	return nil
+}
+
+// Reset memakai token reset password, mengganti password, lalu mencabut semua session user.
+// Organisasi diambil dari token yang sudah ditandatangani seperti pada verifikasi email.
+func (s *PasswordService) Reset(ctx context.Context, raw string, password string) error {
+	claims, organizationID, err := s.tokens.parse(raw, model.PurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	ctx = tenant.WithOrganization(ctx, organizationID)
+
+	var user model.User
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := s.tokens.consume(tx, raw, claims, model.PurposePasswordReset); err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", claims.Subject).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return userErr.ErrInvalidToken
+			}
+			return err
+		}
+		if err := user.SetPassword(password); err != nil {
+			return userErr.ErrHashingPassword
+		}
+
+		updates := map[string]interface{}{"password": user.Password, "password_reset_required": false}
+		// link dari email membuktikan user menguasai alamat email tersebut
+		if !user.IsEmailVerified() {
+			user.MarkEmailVerified(time.Now())
+			updates["email_verified_at"] = user.EmailVerifiedAt
+		}
+		return tx.Model(&user).Updates(updates).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeAll(ctx, user.ID.String(), sessionModel.RevokePassword)
+}
+
+// Change mengganti password user yang sedang login setelah password lama dicocokkan.
+// Session lain dicabut, session yang dipakai untuk mengganti password tetap aktif.
+func (s *PasswordService) Change(ctx context.Context, userID string, sessionID string, input model.ChangePasswordInput) error {
+	var user model.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	if err := user.CheckPassword(input.CurrentPassword); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": userID,
+		}).Warn("Password lama tidak cocok saat mengganti password")
+		return userErr.ErrInvalidPassword
+	}
+	if input.NewPassword == input.CurrentPassword {
+		return userErr.ErrSamePassword
+	}
+
+	if err := user.SetPassword(input.NewPassword); err != nil {
+		return userErr.ErrHashingPassword
+	}
+	updates := map[string]interface{}{"password": user.Password, "password_reset_required": false}
+	if err := s.db.WithContext(ctx).Model(&user).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeOthers(ctx, userID, sessionID, sessionModel.RevokePassword)
+}
@@ -0,0 +1,249 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/user/model"
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+	"booking/pkg/tenant"
+	"booking/pkg/totp"
+	userErr "booking/shared/errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// twoFactorChallengeTTL adalah waktu yang diberikan untuk memasukkan kode setelah password benar
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// TwoFactorServiceInterface mendefinisikan kontrak untuk TwoFactorService
+type TwoFactorServiceInterface interface {
+	Setup(ctx context.Context, userID string, issuer string) (*model.TwoFactorSetup, error)
+	Confirm(ctx context.Context, userID string, code string) ([]string, error)
+	Disable(ctx context.Context, userID string, code string) error
+	Challenge(user *model.User) (*model.LoginChallenge, error)
+	VerifyLogin(ctx context.Context, challengeToken string, code string, ipAddress string) (*model.User, error)
+}
+
+type TwoFactorService struct {
+	db        *gorm.DB
+	logger    logger.Logger
+	guard     *LoginGuard
+	jwtSecret string
+}
+
+func NewTwoFactorService(db *gorm.DB, logger logger.Logger, guard *LoginGuard, jwtSecret string) *TwoFactorService {
+	if jwtSecret == "" {
+		panic("jwt secret is required")
+	}
+	if guard == nil {
+		panic("login guard is required")
+	}
+
+	return &TwoFactorService{
+		db:        db,
+		logger:    logger,
+		guard:     guard,
+		jwtSecret: jwtSecret,
+	}
+}
+
+// Setup membuat secret TOTP baru untuk didaftarkan di aplikasi authenticator. 2FA belum
+// aktif sampai Confirm dipanggil dengan kode dari secret ini.
+func (s *TwoFactorService) Setup(ctx context.Context, userID string, issuer string) (*model.TwoFactorSetup, error) {
+	var user model.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, userErr.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Model(&user).Update("two_factor_secret", secret).Error; err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.URI(issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm mengaktifkan 2FA setelah kode pertama dari authenticator cocok, lalu membuat
+// recovery code. Kode asli hanya dikembalikan sekali di sini.
+func (s *TwoFactorService) Confirm(ctx context.Context, userID string, code string) ([]string, error) {
+	var raws []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled() {
+			return userErr.ErrTwoFactorEnabled
+		}
+		if user.TwoFactorSecret == "" {
+			return userErr.ErrTwoFactorNotSetup
+		}
+
+		now := time.Now()
+		step, ok := totp.Validate(user.TwoFactorSecret, code, now)
+		if !ok {
+			return userErr.ErrInvalidTwoFactorCode
+		}
+		err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled_at": now,
+			"two_factor_last_step":  step,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var codes []model.RecoveryCode
+		codes, raws, err = model.NewRecoveryCodes(user.ID, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return raws, nil
+}
+
+// Disable menonaktifkan 2FA; wajib memakai kode TOTP atau recovery code yang valid
+func (s *TwoFactorService) Disable(ctx context.Context, userID string, code string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled() {
+			return userErr.ErrTwoFactorNotEnabled
+		}
+		if err := s.verifyCode(tx, &user, code); err != nil {
+			return err
+		}
+
+		err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_secret":     "",
+			"two_factor_enabled_at": nil,
+			"two_factor_last_step":  0,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error
+	})
+}
+
+// Challenge membuat challenge token untuk langkah kedua login. Token ini ditandatangani
+// dengan audience tersendiri sehingga tidak bisa dipakai sebagai access token.
+func (s *TwoFactorService) Challenge(user *model.User) (*model.LoginChallenge, error) {
+	token, err := jwt.GenerateActionToken(string(model.PurposeTwoFactorLogin), user.ID.String(), uuid.NewString(), user.OrganizationID.String(), s.jwtSecret, twoFactorChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int(twoFactorChallengeTTL.Seconds()),
+	}, nil
+}
+
+// VerifyLogin menyelesaikan login dua langkah: challenge token harus valid untuk organisasi
+// request ini dan kode TOTP / recovery code harus cocok. Kode yang salah dihitung sebagai
+// login gagal sehingga kode tidak bisa ditebak berulang kali.
+func (s *TwoFactorService) VerifyLogin(ctx context.Context, challengeToken string, code string, ipAddress string) (*model.User, error) {
+	claims, err := jwt.ValidateActionToken(challengeToken, string(model.PurposeTwoFactorLogin), s.jwtSecret)
+	if err != nil {
+		return nil, userErr.ErrInvalidToken
+	}
+	if organizationID, ok := tenant.FromContext(ctx); !ok || organizationID.String() != claims.OrganizationID {
+		return nil, userErr.ErrInvalidToken
+	}
+
+	var user model.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", claims.Subject).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, userErr.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if err := s.guard.Check(ctx, user.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled() {
+			return userErr.ErrInvalidToken
+		}
+		return s.verifyCode(tx, &user, code)
+	})
+	if err != nil {
+		if !errors.Is(err, userErr.ErrInvalidTwoFactorCode) {
+			return nil, err
+		}
+		s.logger.WithFields(logrus.Fields{
+			"user_id":    user.ID,
+			"ip_address": ipAddress,
+		}).Warn("Kode 2FA tidak valid saat login")
+		locked, failErr := s.guard.Fail(ctx, user.Email, ipAddress)
+		if failErr != nil {
+			return nil, failErr
+		}
+		if locked {
+			return nil, &LoginThrottledError{RetryAfter: s.guard.LockoutDuration()}
+		}
+		return nil, err
+	}
+
+	if err := s.guard.Succeed(ctx, user.Email); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Warn("Gagal mereset counter login")
+	}
+	return &user, nil
+}
+
+// verifyCode menerima kode TOTP yang belum pernah dipakai atau recovery code yang belum
+// dipakai; keduanya ditandai terpakai di dalam transaksi pemanggil
+func (s *TwoFactorService) verifyCode(tx *gorm.DB, user *model.User, code string) error {
+	now := time.Now()
+	if step, ok := totp.Validate(user.TwoFactorSecret, code, now); ok {
+		if step <= user.TwoFactorLastStep {
+			return userErr.ErrInvalidTwoFactorCode
+		}
+		user.TwoFactorLastStep = step
+		return tx.Model(user).Update("two_factor_last_step", step).Error
+	}
+
+	var recovery model.RecoveryCode
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, model.HashRecoveryCode(code)).
+		First(&recovery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return userErr.ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+	return tx.Model(&recovery).Update("used_at", now).Error
+}
@@ -1,23 +1,43 @@
 package user
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	organizationModel "booking/internal/organization/model"
+	"booking/internal/role"
+	roleModel "booking/internal/role/model"
+	"booking/internal/session"
+	sessionModel "booking/internal/session/model"
 	"booking/internal/user/model"
 	"booking/pkg/response"
 	"booking/shared/constants"
+	errs "booking/shared/errors"
 	"booking/shared/validate"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
-	userService UserServiceInterface
+	userService         UserServiceInterface
+	roleService         role.RoleServiceInterface
+	sessionService      session.SessionServiceInterface
+	verificationService VerificationServiceInterface
+	passwordService     PasswordServiceInterface
+	twoFactorService    TwoFactorServiceInterface
 }
 
-func NewUserHandler(userService UserServiceInterface) *UserHandler {
+func NewUserHandler(userService UserServiceInterface, roleService role.RoleServiceInterface, sessionService session.SessionServiceInterface, verificationService VerificationServiceInterface, passwordService PasswordServiceInterface, twoFactorService TwoFactorServiceInterface) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:         userService,
+		roleService:         roleService,
+		sessionService:      sessionService,
+		verificationService: verificationService,
+		passwordService:     passwordService,
+		twoFactorService:    twoFactorService,
 	}
 }
 
@@ -41,7 +61,109 @@ func (h *UserHandler) Register(c echo.Context) error {
 		return response.BadRequest(c, "registration failed", err)
 	}
 
-	return response.Success(c, http.StatusCreated, "User registered successfully", user)
+	// Gagal kirim email tidak membatalkan registrasi; user bisa meminta kirim ulang
+	message := "User registered successfully, please check your email to verify your account"
+	if err := h.verificationService.Send(c.Request().Context(), user); err != nil {
+		message = "User registered successfully, but the verification email could not be sent; please request a new one"
+	}
+
+	return response.Success(c, http.StatusCreated, message, user)
+}
+
+// VerifyEmail memverifikasi email memakai token dari link yang dikirim ke email user
+func (h *UserHandler) VerifyEmail(c echo.Context) error {
+	var input model.VerifyEmailInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	user, err := h.verificationService.Verify(c.Request().Context(), input.Token)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidToken) {
+			return response.BadRequest(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to verify email", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Email verified successfully", user)
+}
+
+// ResendVerification mengirim ulang link verifikasi ke email user yang sedang login
+func (h *UserHandler) ResendVerification(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	if err := h.verificationService.Resend(c.Request().Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrEmailAlreadyVerified):
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		case errors.Is(err, errs.ErrTooManyRequests):
+			return response.Error(c, http.StatusTooManyRequests, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to send verification email", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Verification email sent", nil)
+}
+
+// ForgotPassword mengirim link reset password. Respons selalu sama, baik email terdaftar
+// maupun tidak, agar endpoint ini tidak bisa dipakai untuk menebak akun
+func (h *UserHandler) ForgotPassword(c echo.Context) error {
+	var input model.ForgotPasswordInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	if err := h.passwordService.Forgot(c.Request().Context(), input.Email); err != nil {
+		return response.InternalServerError(c, "failed to send password reset email", err)
+	}
+
+	return response.Success(c, http.StatusOK, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ResetPassword mengganti password memakai token dari link reset; semua session dicabut
+func (h *UserHandler) ResetPassword(c echo.Context) error {
+	var input model.ResetPasswordInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	if err := h.passwordService.Reset(c.Request().Context(), input.Token, input.Password); err != nil {
+		if errors.Is(err, errs.ErrInvalidToken) {
+			return response.BadRequest(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to reset password", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Password reset successfully, please log in again", nil)
+}
+
+// ChangePassword mengganti password user yang sedang login; password lama wajib diisi
+func (h *UserHandler) ChangePassword(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	var input model.ChangePasswordInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	sessionID, _ := c.Get("session_id").(string)
+	if err := h.passwordService.Change(c.Request().Context(), userID, sessionID, input); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrInvalidPassword):
+			return response.BadRequest(c, "current password is incorrect", err)
+		case errors.Is(err, errs.ErrSamePassword):
+			return response.BadRequest(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to change password", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Password changed successfully, other devices have been logged out", nil)
 }
 
 func (h *UserHandler) Login(c echo.Context) error {
@@ -50,27 +172,155 @@ func (h *UserHandler) Login(c echo.Context) error {
 		return err
 	}
 
-	token, err := h.userService.Login(c.Request().Context(), input)
+	user, err := h.userService.Login(c.Request().Context(), input, c.RealIP())
 	if err != nil {
-		return response.Unauthorized(c, "invalid credentials", err)
+		return loginError(c, err)
+	}
+
+	// User dengan 2FA mendapat challenge token; session dibuat di LoginTwoFactor
+	if user.TwoFactorEnabled() {
+		challenge, err := h.twoFactorService.Challenge(user)
+		if err != nil {
+			return response.InternalServerError(c, "failed to create two-factor challenge", err)
+		}
+		return response.Success(c, http.StatusOK, "Two-factor authentication required", challenge)
 	}
 
-	return response.Success(c, http.StatusOK, "Login successful", map[string]string{
-		"token": token,
+	tokens, err := h.sessionService.Start(c.Request().Context(), user.ID, session.Device(c))
+	if err != nil {
+		return startSessionError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Login successful", tokens)
+}
+
+// LoginTwoFactor menyelesaikan login dua langkah dengan challenge token dan kode TOTP / recovery code
+func (h *UserHandler) LoginTwoFactor(c echo.Context) error {
+	var input model.TwoFactorLoginInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	user, err := h.twoFactorService.VerifyLogin(c.Request().Context(), input.ChallengeToken, input.Code, c.RealIP())
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	tokens, err := h.sessionService.Start(c.Request().Context(), user.ID, session.Device(c))
+	if err != nil {
+		return startSessionError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Login successful", tokens)
+}
+
+// SetupTwoFactor membuat secret TOTP dan provisioning URI untuk ditampilkan sebagai QR code
+func (h *UserHandler) SetupTwoFactor(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	// Nama organisasi tampil sebagai issuer di aplikasi authenticator
+	issuer := "Booking"
+	if org, ok := c.Get("organization").(*organizationModel.Organization); ok && org != nil {
+		issuer = org.Name
+	}
+
+	setup, err := h.twoFactorService.Setup(c.Request().Context(), userID, issuer)
+	if err != nil {
+		if errors.Is(err, errs.ErrTwoFactorEnabled) {
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to set up two-factor authentication", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Scan the QR code with your authenticator app, then confirm with a code", setup)
+}
+
+// ConfirmTwoFactor mengaktifkan 2FA dan mengembalikan recovery code (hanya ditampilkan sekali)
+func (h *UserHandler) ConfirmTwoFactor(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return response.Unauthorized(c, "unauthorized", err)
+	}
+
+	var input model.TwoFactorCodeInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	codes, err := h.twoFactorService.Confirm(c.Request().Context(), userID, input.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrTwoFactorEnabled):
+			return response.Error(c, http.StatusConflict, err.Error(), err)
+		case errors.Is(err, errs.ErrTwoFactorNotSetup), errors.Is(err, errs.ErrInvalidTwoFactorCode):
+			return response.BadRequest(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to enable two-factor authentication", err)
+	}
+	h.invalidateAccessTokens(c, userID)
+
+	return response.Success(c, http.StatusOK, "Two-factor authentication enabled; store these recovery codes safely", map[string]interface{}{
+		"recovery_codes": codes,
 	})
 }
 
-func (h *UserHandler) Logout(c echo.Context) error {
+// DisableTwoFactor menonaktifkan 2FA; wajib menyertakan kode TOTP atau recovery code
+func (h *UserHandler) DisableTwoFactor(c echo.Context) error {
 	userID, err := getUserID(c)
 	if err != nil {
 		return response.Unauthorized(c, "unauthorized", err)
 	}
 
-	if err := h.userService.Logout(c.Request().Context(), userID); err != nil {
-		return response.InternalServerError(c, "logout failed", err)
+	var input model.TwoFactorCodeInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	if err := h.twoFactorService.Disable(c.Request().Context(), userID, input.Code); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrTwoFactorNotEnabled), errors.Is(err, errs.ErrInvalidTwoFactorCode):
+			return response.BadRequest(c, err.Error(), err)
+		}
+		return response.InternalServerError(c, "failed to disable two-factor authentication", err)
+	}
+	h.invalidateAccessTokens(c, userID)
+
+	return response.Success(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// invalidateAccessTokens membuat access token user yang sudah diterbitkan ditolak karena
+// role/status 2FA di claims tidak lagi sesuai; client refresh untuk mendapat token baru.
+// Perubahan di database sudah tersimpan, sehingga kegagalan hanya dicatat oleh service.
+func (h *UserHandler) invalidateAccessTokens(c echo.Context, userID string) {
+	_ = h.sessionService.InvalidateAccessTokens(c.Request().Context(), userID)
+}
+
+// loginError memetakan error login; semua kegagalan kredensial memakai pesan yang sama
+func loginError(c echo.Context, err error) error {
+	var throttled *LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return response.Error(c, http.StatusTooManyRequests, throttled.Error(), err)
+	case errors.Is(err, errs.ErrInvalidCredentials):
+		return response.Unauthorized(c, "invalid credentials", err)
+	case errors.Is(err, errs.ErrInvalidToken), errors.Is(err, errs.ErrInvalidTwoFactorCode):
+		return response.Unauthorized(c, err.Error(), err)
+	case errors.Is(err, errs.ErrAccountSuspended), errors.Is(err, errs.ErrPasswordResetRequired):
+		return response.Forbidden(c, err.Error(), err)
 	}
+	return response.InternalServerError(c, "login failed", err)
+}
 
-	return response.Success(c, http.StatusOK, "Logout successful", nil)
+// startSessionError memetakan error saat membuat session setelah kredensial valid
+func startSessionError(c echo.Context, err error) error {
+	if errors.Is(err, errs.ErrAccountSuspended) {
+		return response.Forbidden(c, err.Error(), err)
+	}
+	return response.InternalServerError(c, "failed to start session", err)
 }
 
 func (h *UserHandler) GetMe(c echo.Context) error {
@@ -98,11 +348,23 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 		return err
 	}
 
+	previous, err := h.userService.GetUserByID(c.Request().Context(), userID)
+	if err != nil {
+		return response.NotFound(c, "user not found", err)
+	}
+
 	user, err := h.userService.UpdateProfile(c.Request().Context(), userID, input)
 	if err != nil {
 		return response.BadRequest(c, "failed to update profile", err)
 	}
 
+	// Email berubah: kirim link verifikasi ke alamat baru
+	if previous != nil && previous.Email != user.Email && !user.IsEmailVerified() {
+		if err := h.verificationService.Send(c.Request().Context(), user); err != nil {
+			return response.Success(c, http.StatusOK, "Profile updated, but the verification email could not be sent; please request a new one", user)
+		}
+	}
+
 	return response.Success(c, http.StatusOK, "Profile updated successfully", user)
 }
 
@@ -115,11 +377,25 @@ func (h *UserHandler) DeleteAccount(c echo.Context) error {
 	if err := h.userService.DeleteAccount(c.Request().Context(), userID); err != nil {
 		return response.InternalServerError(c, "failed to delete account", err)
 	}
+	if err := h.sessionService.RevokeAll(c.Request().Context(), userID, sessionModel.RevokeUserDeleted); err != nil {
+		return response.InternalServerError(c, "failed to revoke sessions", err)
+	}
 
 	return response.Success(c, http.StatusOK, "Account deleted successfully", nil)
 }
+
+// GetAllUsers mencari user (permission user:read) dengan filter q, role dan status serta paginasi
 func (h *UserHandler) GetAllUsers(c echo.Context) error {
-	users, err := h.userService.GetAllUsers(c.Request().Context())
+	var input model.UserSearchInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+	filter, err := input.Filter()
+	if err != nil {
+		return response.BadRequest(c, err.Error(), err)
+	}
+
+	users, err := h.userService.Search(c.Request().Context(), filter)
 	if err != nil {
 		return response.InternalServerError(c, "failed to get all users", err)
 	}
@@ -127,26 +403,62 @@ func (h *UserHandler) GetAllUsers(c echo.Context) error {
 	return response.Success(c, http.StatusOK, "Users retrieved successfully", users)
 }
 
-// UpdateUserRole mengubah role user oleh superadmin
+// UpdateUserRole mengubah role user (permission user:manage). User tidak bisa memberikan
+// atau mencabut role yang memiliki permission lebih banyak dari role miliknya sendiri.
 func (h *UserHandler) UpdateUserRole(c echo.Context) error {
-	adminID, err := getUserID(c)
-	if err != nil {
-		return response.Unauthorized(c, "unauthorized", err)
+	granted, ok := c.Get("permissions").(roleModel.PermissionSet)
+	if !ok {
+		return response.Unauthorized(c, "unauthorized", nil)
 	}
 
 	targetUserID := c.Param("id")
 	var input struct {
-		Role constants.Role `json:"role" validate:"required"`
+		Role constants.Role `json:"role" validate:"required,max=50"`
 	}
 
 	if err := validate.BindAndValidate(c, &input); err != nil {
 		return err
 	}
 
-	user, err := h.userService.UpdateUserRole(c.Request().Context(), adminID, targetUserID, input.Role)
+	ctx := c.Request().Context()
+	newRole, err := h.roleService.GetByName(ctx, input.Role)
+	if err != nil {
+		if errors.Is(err, role.ErrRoleNotFound) {
+			return response.BadRequest(c, "role not found", err)
+		}
+		return response.InternalServerError(c, "failed to get role", err)
+	}
+
+	target, err := h.userService.GetUserByID(ctx, targetUserID)
+	if err != nil {
+		return response.NotFound(c, "user not found", err)
+	}
+	current, err := h.roleService.Permissions(ctx, target.Role)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get role", err)
+	}
+
+	if !granted.Covers(newRole.PermissionSet()) || !granted.Covers(current) {
+		return response.Forbidden(c, "access denied: role has permissions you do not have", nil)
+	}
+
+	user, err := h.userService.UpdateUserRole(ctx, targetUserID, input.Role)
 	if err != nil {
 		return response.BadRequest(c, "failed to update user role", err)
 	}
+	h.invalidateAccessTokens(c, targetUserID)
 
 	return response.Success(c, http.StatusOK, "User role updated successfully", user)
 }
+
+// UnlockUser membuka kunci login user yang terkunci karena terlalu banyak gagal login
+func (h *UserHandler) UnlockUser(c echo.Context) error {
+	if err := h.userService.Unlock(c.Request().Context(), c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFound(c, "user not found", err)
+		}
+		return response.InternalServerError(c, "failed to unlock user", err)
+	}
+
+	return response.Success(c, http.StatusOK, "User unlocked successfully", nil)
+}
@@ -3,12 +3,13 @@ package user
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
+	"booking/internal/notification"
+	notificationModel "booking/internal/notification/model"
 	"booking/internal/user/model"
-	"booking/pkg/jwt"
 	"booking/pkg/logger"
-	"booking/pkg/redis"
 	"booking/shared/constants"
 	userErr "booking/shared/errors"
 
@@ -20,42 +21,45 @@ import (
 // UserServiceInterface mendefinisikan kontrak untuk UserService
 type UserServiceInterface interface {
 	Register(ctx context.Context, input model.RegisterInput) (*model.User, error)
-	Login(ctx context.Context, input model.LoginInput) (string, error)
+	Login(ctx context.Context, input model.LoginInput, ipAddress string) (*model.User, error)
 	GetUserByID(ctx context.Context, userID string) (*model.User, error)
-	GetStoredToken(ctx context.Context, userID string) (string, error)
-	Logout(ctx context.Context, userID string) error
 	UpdateProfile(ctx context.Context, userID string, input model.UpdateProfileInput) (*model.User, error)
 	DeleteAccount(ctx context.Context, userID string) error
-	GetAllUsers(ctx context.Context) ([]model.User, error)
-	UpdateUserRole(ctx context.Context, adminID string, targetUserID string, newRole constants.Role) (*model.User, error)
+	Search(ctx context.Context, filter *model.UserFilter) (*model.UserSearchResult, error)
+	UpdateUserRole(ctx context.Context, targetUserID string, newRole constants.Role) (*model.User, error)
+	Unlock(ctx context.Context, targetUserID string) error
 }
 
+// dummyPasswordHash dibandingkan saat email tidak terdaftar agar waktu respons login sama
+// dengan email yang terdaftar
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), constants.BcryptCost)
+	return hash
+})
+
 type UserService struct {
-	db          *gorm.DB
-	jwtSecret   string
-	logger      logger.Logger
-	redisClient *redis.RedisClient
+	db     *gorm.DB
+	logger logger.Logger
+	guard  *LoginGuard
+	appURL string
 }
 
-func NewUserService(db *gorm.DB, jwtSecret string, logger logger.Logger, redisClient *redis.RedisClient) *UserService {
+func NewUserService(db *gorm.DB, logger logger.Logger, guard *LoginGuard, appURL string) *UserService {
 	if db == nil {
 		panic("database connection is required")
 	}
 	if logger == nil {
 		panic("logger is required")
 	}
-	if redisClient == nil {
-		panic("redis client is required")
-	}
-	if jwtSecret == "" {
-		panic("jwt secret is required")
+	if guard == nil {
+		panic("login guard is required")
 	}
 
 	return &UserService{
-		db:          db,
-		jwtSecret:   jwtSecret,
-		logger:      logger,
-		redisClient: redisClient,
+		db:     db,
+		logger: logger,
+		guard:  guard,
+		appURL: strings.TrimRight(appURL, "/"),
 	}
 }
 
@@ -89,41 +93,102 @@ func (s *UserService) Register(ctx context.Context, input model.RegisterInput) (
 	return user, nil
 }
 
-func (s *UserService) Login(ctx context.Context, input model.LoginInput) (string, error) {
+// Login memeriksa kredensial user; token dibuat oleh SessionService per perangkat.
+// Percobaan dibatasi per email dan per IP oleh LoginGuard; email yang tidak terdaftar
+// diperlakukan sama agar respons tidak membocorkan akun mana yang ada.
+func (s *UserService) Login(ctx context.Context, input model.LoginInput, ipAddress string) (*model.User, error) {
+	if err := s.guard.Check(ctx, input.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
 	var user model.User
 	if err := s.db.WithContext(ctx).Where("email = ?", input.Email).First(&user).Error; err != nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(input.Password))
 		s.logger.WithFields(logrus.Fields{
-			"email": input.Email,
-			"error": userErr.ErrInvalidCredentials.Error(),
+			"email":      input.Email,
+			"ip_address": ipAddress,
+			"error":      userErr.ErrInvalidCredentials.Error(),
 		}).Error("Kredensial login tidak valid")
-		return "", userErr.ErrInvalidCredentials
+		return nil, s.fail(ctx, nil, input.Email, ipAddress)
 	}
 
 	if err := user.CheckPassword(input.Password); err != nil {
 		s.logger.WithFields(logrus.Fields{
-			"email": input.Email,
-			"error": userErr.ErrInvalidCredentials.Error(),
+			"email":      input.Email,
+			"ip_address": ipAddress,
+			"error":      userErr.ErrInvalidCredentials.Error(),
 		}).Error("Password tidak valid")
-		return "", userErr.ErrInvalidCredentials
+		return nil, s.fail(ctx, &user, input.Email, ipAddress)
 	}
 
-	token, err := jwt.GenerateToken(user.ID.String(), s.jwtSecret)
+	// Dicek setelah password cocok agar status akun tidak bocor ke orang yang tidak tahu password
+	if user.IsSuspended() {
+		return nil, userErr.ErrAccountSuspended
+	}
+	if user.PasswordResetRequired {
+		return nil, userErr.ErrPasswordResetRequired
+	}
+
+	// Dengan 2FA, counter baru direset setelah kode benar agar password yang benar tidak
+	// bisa dipakai untuk mereset jatah tebakan kode
+	if !user.TwoFactorEnabled() {
+		if err := s.guard.Succeed(ctx, input.Email); err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"email": input.Email,
+				"error": err.Error(),
+			}).Warn("Gagal mereset counter login")
+		}
+	}
+	return &user, nil
+}
+
+// fail mencatat login gagal dan memberi tahu pemilik akun jika akunnya baru saja dikunci
+func (s *UserService) fail(ctx context.Context, user *model.User, email string, ipAddress string) error {
+	locked, err := s.guard.Fail(ctx, email, ipAddress)
 	if err != nil {
-		s.logger.WithFields(logrus.Fields{
-			"user_id": user.ID,
-			"error":   err.Error(),
-		}).Error("Gagal generate token")
-		return "", err
+		return err
 	}
-	if err := s.redisClient.SetToken(ctx, user.ID.String(), token, 24*time.Hour); err != nil {
+	if !locked {
+		return userErr.ErrInvalidCredentials
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"email":      email,
+		"ip_address": ipAddress,
+	}).Warn("Akun dikunci sementara karena terlalu banyak login gagal")
+	// Email diantrekan di goroutine terpisah agar email terdaftar dan tidak terdaftar
+	// kembali dalam waktu yang sama
+	if user != nil {
+		go s.notifyLocked(context.WithoutCancel(ctx), *user)
+	}
+	return &LoginThrottledError{RetryAfter: s.guard.LockoutDuration()}
+}
+
+// notifyLocked menulis email pemberitahuan kunci akun ke outbox untuk dikirim dispatcher;
+// kegagalan hanya dicatat
+func (s *UserService) notifyLocked(ctx context.Context, user model.User) {
+	locale := user.Locale
+	if locale == "" {
+		locale = constants.DefaultLocale
+	}
+	subject, body, err := notification.RenderAccount(constants.EventAccountLocked, locale, notificationModel.AccountMessageData{
+		Name:      user.Name,
+		Link:      s.appURL + "/forgot-password",
+		ExpiresIn: formatTTL(locale, s.guard.LockoutDuration()),
+	})
+	var message *notificationModel.OutboxMessage
+	if err == nil {
+		message, err = notificationModel.NewOutboxMessage(constants.EventAccountLocked, user.ID, user.Email, locale, subject, body)
+	}
+	if err == nil {
+		err = s.db.WithContext(ctx).Create(message).Error
+	}
+	if err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"user_id": user.ID,
 			"error":   err.Error(),
-		}).Error("Gagal menyimpan token di Redis")
-		return "", err
+		}).Error("Gagal mengantrekan email pemberitahuan akun dikunci")
 	}
-
-	return token, nil
 }
 
 func (s *UserService) GetUserByID(ctx context.Context, userID string) (*model.User, error) {
@@ -134,24 +199,6 @@ func (s *UserService) GetUserByID(ctx context.Context, userID string) (*model.Us
 	return &user, nil
 }
 
-func (s *UserService) GetStoredToken(ctx context.Context, userID string) (string, error) {
-	if s.redisClient == nil {
-		return "", userErr.ErrInvalidCredentials
-	}
-	return s.redisClient.GetToken(ctx, userID)
-}
-
-func (s *UserService) Logout(ctx context.Context, userID string) error {
-	if err := s.redisClient.DeleteToken(ctx, userID); err != nil {
-		s.logger.WithFields(logrus.Fields{
-			"user_id": userID,
-			"error":   err.Error(),
-		}).Error("Gagal menghapus token dari Redis")
-		return err
-	}
-	return nil
-}
-
 func (s *UserService) UpdateProfile(ctx context.Context, userID string, input model.UpdateProfileInput) (*model.User, error) {
 	var user model.User
 	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
@@ -160,18 +207,30 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID string, input mo
 
 	// Update fields
 	user.Name = strings.TrimSpace(input.Name)
-	if input.Email != "" {
-		user.Email = strings.TrimSpace(input.Email)
+	emailChanged := false
+	if email := strings.TrimSpace(input.Email); email != "" && email != user.Email {
+		// email baru harus diverifikasi ulang
+		user.Email = email
+		user.EmailVerifiedAt = nil
+		emailChanged = true
 	}
-	if input.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(strings.TrimSpace(input.Password)), bcrypt.DefaultCost)
-		if err != nil {
-			return nil, err
-		}
-		user.Password = string(hashedPassword)
+	if input.Locale != "" {
+		user.Locale = constants.Locale(input.Locale)
 	}
 
-	if err := s.db.WithContext(ctx).Save(&user).Error; err != nil {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if !emailChanged {
+			return nil
+		}
+		// link verifikasi untuk alamat lama tidak boleh memverifikasi alamat baru
+		return tx.Model(&model.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, model.PurposeEmailVerification).
+			Update("expires_at", time.Now()).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -182,10 +241,32 @@ func (s *UserService) DeleteAccount(ctx context.Context, userID string) error {
 	return s.db.WithContext(ctx).Delete(&model.User{}, "id = ?", userID).Error
 }
 
-// GetAllUsers mengambil semua user tanpa password
-func (s *UserService) GetAllUsers(ctx context.Context) ([]model.User, error) {
-	var users []model.User
-	if err := s.db.WithContext(ctx).Find(&users).Error; err != nil {
+// Search mencari user berdasarkan nama/email, role dan status dengan paginasi
+func (s *UserService) Search(ctx context.Context, filter *model.UserFilter) (*model.UserSearchResult, error) {
+	query := s.db.WithContext(ctx).Model(&model.User{})
+	if filter.Query != "" {
+		pattern := filter.LikePattern()
+		query = query.Where("users.name LIKE ? OR users.email LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case model.UserStatusActive:
+		query = query.Where("users.suspended_at IS NULL")
+	case model.UserStatusSuspended:
+		query = query.Where("users.suspended_at IS NOT NULL")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	users := []model.User{}
+	if err := query.Order("users.created_at DESC").Order("users.id").
+		Offset(filter.Offset()).Limit(filter.PerPage).
+		Find(&users).Error; err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"error": err.Error(),
 		}).Error("Gagal mengambil daftar user")
@@ -197,25 +278,11 @@ func (s *UserService) GetAllUsers(ctx context.Context) ([]model.User, error) {
 		users[i].Password = ""
 	}
 
-	return users, nil
+	return model.NewUserSearchResult(users, filter, total), nil
 }
 
-// UpdateUserRole mengubah role user oleh superadmin
-func (s *UserService) UpdateUserRole(ctx context.Context, adminID string, targetUserID string, newRole constants.Role) (*model.User, error) {
-	// Cek apakah admin adalah superadmin
-	var admin model.User
-	if err := s.db.WithContext(ctx).Where("id = ?", adminID).First(&admin).Error; err != nil {
-		return nil, err
-	}
-
-	if admin.Role != constants.RoleSuperAdmin {
-		s.logger.WithFields(logrus.Fields{
-			"admin_id": adminID,
-			"error":    "Unauthorized: Only superadmin can change user roles",
-		}).Error("Akses ditolak untuk mengubah role")
-		return nil, userErr.ErrUnauthorized
-	}
-
+// UpdateUserRole mengubah role user; otorisasi dilakukan lewat permission user:manage
+func (s *UserService) UpdateUserRole(ctx context.Context, targetUserID string, newRole constants.Role) (*model.User, error) {
 	// Cek target user
 	var targetUser model.User
 	if err := s.db.WithContext(ctx).Where("id = ?", targetUserID).First(&targetUser).Error; err != nil {
@@ -235,3 +302,12 @@ func (s *UserService) UpdateUserRole(ctx context.Context, adminID string, target
 
 	return &targetUser, nil
 }
+
+// Unlock membuka kunci login user sebelum waktunya habis (permission user:manage)
+func (s *UserService) Unlock(ctx context.Context, targetUserID string) error {
+	var user model.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", targetUserID).Error; err != nil {
+		return err
+	}
+	return s.guard.Unlock(ctx, user.Email)
+}
@@ -0,0 +1,124 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"booking/internal/user/model"
+	"booking/pkg/jwt"
+	"booking/shared/constants"
+	userErr "booking/shared/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// tokenResendInterval adalah jeda minimal antar pengiriman token dengan kegunaan yang sama
+	tokenResendInterval = time.Minute
+	// tokenHourlyLimit adalah jumlah maksimal token yang dikirim per jam per kegunaan
+	tokenHourlyLimit = 5
+)
+
+// accountTokens mengelola token sekali pakai yang dikirim lewat email
+type accountTokens struct {
+	secret string
+}
+
+// issue membuat token baru dan membatalkan token lama yang belum dipakai, sehingga hanya
+// link terakhir yang berlaku
+func (a accountTokens) issue(tx *gorm.DB, user *model.User, purpose model.TokenPurpose, ttl time.Duration) (string, error) {
+	now := time.Now()
+	err := tx.Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", user.ID, purpose, now).
+		Update("expires_at", now).Error
+	if err != nil {
+		return "", err
+	}
+
+	token := model.NewUserToken(user.ID, purpose, ttl, now)
+	token.OrganizationID = user.OrganizationID
+	raw, err := jwt.GenerateActionToken(string(purpose), user.ID.String(), token.ID.String(), token.OrganizationID.String(), a.secret, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	token.TokenHash = model.HashToken(raw)
+	if err := tx.Create(token).Error; err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// parse memvalidasi tanda tangan token; organisasi di claims dipakai untuk scope tenant
+func (a accountTokens) parse(raw string, purpose model.TokenPurpose) (*jwt.ActionClaims, uuid.UUID, error) {
+	claims, err := jwt.ValidateActionToken(raw, string(purpose), a.secret)
+	if err != nil {
+		return nil, uuid.Nil, userErr.ErrInvalidToken
+	}
+	organizationID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		return nil, uuid.Nil, userErr.ErrInvalidToken
+	}
+	return claims, organizationID, nil
+}
+
+// consume menandai token sudah dipakai; token yang sudah dipakai, dibatalkan, kedaluwarsa
+// atau tidak cocok dengan hash yang tersimpan ditolak
+func (a accountTokens) consume(tx *gorm.DB, raw string, claims *jwt.ActionClaims, purpose model.TokenPurpose) (*model.UserToken, error) {
+	var token model.UserToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ? AND purpose = ?", claims.ID, claims.Subject, purpose).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, userErr.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !token.Usable(now) || !token.Matches(raw) {
+		return nil, userErr.ErrInvalidToken
+	}
+	if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+		return nil, err
+	}
+	token.UsedAt = &now
+	return &token, nil
+}
+
+// throttle membatasi pengiriman ulang token agar email user tidak dibanjiri
+func (a accountTokens) throttle(db *gorm.DB, userID uuid.UUID, purpose model.TokenPurpose) error {
+	now := time.Now()
+	var recent []time.Time
+	err := db.Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", userID, purpose, now.Add(-time.Hour)).
+		Order("created_at DESC").
+		Pluck("created_at", &recent).Error
+	if err != nil {
+		return err
+	}
+
+	if len(recent) >= tokenHourlyLimit || (len(recent) > 0 && now.Sub(recent[0]) < tokenResendInterval) {
+		return userErr.ErrTooManyRequests
+	}
+	return nil
+}
+
+// formatTTL menulis masa berlaku token untuk isi email
+func formatTTL(locale constants.Locale, ttl time.Duration) string {
+	hours := int(ttl.Hours())
+	if hours < 1 {
+		if locale == constants.LocaleEnglish {
+			return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+		}
+		return fmt.Sprintf("%d menit", int(ttl.Minutes()))
+	}
+	if locale == constants.LocaleEnglish {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d jam", hours)
+}
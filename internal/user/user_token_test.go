@@ -0,0 +1,83 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"booking/internal/user/model"
+	"booking/pkg/jwt"
+	"booking/shared/constants"
+	userErr "booking/shared/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountTokensTestSuite struct {
+	suite.Suite
+	tokens accountTokens
+}
+
+func TestAccountTokensSuite(t *testing.T) {
+	suite.Run(t, new(AccountTokensTestSuite))
+}
+
+func (s *AccountTokensTestSuite) SetupTest() {
+	s.tokens = accountTokens{secret: "test-secret"}
+}
+
+func (s *AccountTokensTestSuite) TestParse() {
+	userID, tokenID, orgID := uuid.New(), uuid.New(), uuid.New()
+	raw, err := jwt.GenerateActionToken(string(model.PurposeEmailVerification), userID.String(), tokenID.String(), orgID.String(), "test-secret", time.Hour)
+	s.Require().NoError(err)
+
+	claims, organizationID, err := s.tokens.parse(raw, model.PurposeEmailVerification)
+	s.Require().NoError(err)
+	s.Equal(orgID, organizationID)
+	s.Equal(userID.String(), claims.Subject)
+	s.Equal(tokenID.String(), claims.ID)
+
+	// kegunaan berbeda atau tanda tangan salah ditolak
+	_, _, err = s.tokens.parse(raw, model.PurposePasswordReset)
+	s.ErrorIs(err, userErr.ErrInvalidToken)
+	_, _, err = accountTokens{secret: "other"}.parse(raw, model.PurposeEmailVerification)
+	s.ErrorIs(err, userErr.ErrInvalidToken)
+
+	// token aksi tidak bisa dipakai sebagai access token
+	_, err = jwt.ValidateToken(raw, jwt.NewHMACKeySet("test-secret", ""))
+	s.Error(err)
+}
+
+func (s *AccountTokensTestSuite) TestParseExpired() {
+	raw, err := jwt.GenerateActionToken(string(model.PurposeEmailVerification), uuid.NewString(), uuid.NewString(), uuid.NewString(), "test-secret", -time.Minute)
+	s.Require().NoError(err)
+
+	_, _, err = s.tokens.parse(raw, model.PurposeEmailVerification)
+	s.ErrorIs(err, userErr.ErrInvalidToken)
+}
+
+func (s *AccountTokensTestSuite) TestUserTokenUsable() {
+	now := time.Now()
+	token := model.NewUserToken(uuid.New(), model.PurposeEmailVerification, time.Hour, now)
+	s.True(token.Usable(now))
+	s.False(token.Usable(now.Add(time.Hour)))
+
+	token.UsedAt = &now
+	s.False(token.Usable(now))
+}
+
+func (s *AccountTokensTestSuite) TestUserTokenMatches() {
+	token := model.NewUserToken(uuid.New(), model.PurposePasswordReset, time.Hour, time.Now())
+	s.False(token.Matches("raw-token"))
+
+	token.TokenHash = model.HashToken("raw-token")
+	s.NotEqual("raw-token", token.TokenHash)
+	s.True(token.Matches("raw-token"))
+	s.False(token.Matches("other-token"))
+}
+
+func (s *AccountTokensTestSuite) TestFormatTTL() {
+	s.Equal("24 jam", formatTTL(constants.LocaleIndonesian, 24*time.Hour))
+	s.Equal("24 hours", formatTTL(constants.LocaleEnglish, 24*time.Hour))
+	s.Equal("30 menit", formatTTL(constants.LocaleIndonesian, 30*time.Minute))
+}
@@ -0,0 +1,143 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"booking/internal/notification"
+	notificationModel "booking/internal/notification/model"
+	"booking/internal/user/model"
+	"booking/pkg/logger"
+	"booking/pkg/mailer"
+	"booking/pkg/tenant"
+	"booking/shared/constants"
+	userErr "booking/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// emailVerificationTTL adalah masa berlaku link verifikasi email
+const emailVerificationTTL = 24 * time.Hour
+
+// VerificationServiceInterface mendefinisikan kontrak untuk VerificationService
+type VerificationServiceInterface interface {
+	Send(ctx context.Context, user *model.User) error
+	Resend(ctx context.Context, userID string) error
+	Verify(ctx context.Context, token string) (*model.User, error)
+}
+
+type VerificationService struct {
+	db     *gorm.DB
+	logger logger.Logger
+	mailer mailer.Mailer
+	tokens accountTokens
+	appURL string
+}
+
+func NewVerificationService(db *gorm.DB, logger logger.Logger, mailer mailer.Mailer, jwtSecret string, appURL string) *VerificationService {
+	if jwtSecret == "" {
+		panic("jwt secret is required")
+	}
+
+	return &VerificationService{
+		db:     db,
+		logger: logger,
+		mailer: mailer,
+		tokens: accountTokens{secret: jwtSecret},
+		appURL: strings.TrimRight(appURL, "/"),
+	}
+}
+
+// Send mengirim link verifikasi ke email user. Email dikirim langsung lewat Mailer
+// setelah token tersimpan, tidak lewat outbox, agar link tidak tersimpan di database.
+func (s *VerificationService) Send(ctx context.Context, user *model.User) error {
+	if user.IsEmailVerified() {
+		return userErr.ErrEmailAlreadyVerified
+	}
+
+	var raw string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		raw, err = s.tokens.issue(tx, user, model.PurposeEmailVerification, emailVerificationTTL)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	locale := user.Locale
+	if locale == "" {
+		locale = constants.DefaultLocale
+	}
+	subject, body, err := notification.RenderAccount(constants.EventEmailVerification, locale, notificationModel.AccountMessageData{
+		Name:      user.Name,
+		Link:      s.appURL + "/verify-email?token=" + url.QueryEscape(raw),
+		ExpiresIn: formatTTL(locale, emailVerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, mailer.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal mengirim email verifikasi")
+		return err
+	}
+	return nil
+}
+
+// Resend mengirim ulang link verifikasi dengan batas frekuensi
+func (s *VerificationService) Resend(ctx context.Context, userID string) error {
+	var user model.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return userErr.ErrEmailAlreadyVerified
+	}
+	if err := s.tokens.throttle(s.db.WithContext(ctx), user.ID, model.PurposeEmailVerification); err != nil {
+		return err
+	}
+	return s.Send(ctx, &user)
+}
+
+// Verify memakai token verifikasi dan menandai email user terverifikasi. Organisasi diambil
+// dari token yang sudah ditandatangani sehingga endpoint ini tidak butuh konteks tenant.
+func (s *VerificationService) Verify(ctx context.Context, raw string) (*model.User, error) {
+	claims, organizationID, err := s.tokens.parse(raw, model.PurposeEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+	ctx = tenant.WithOrganization(ctx, organizationID)
+
+	var user model.User
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := s.tokens.consume(tx, raw, claims, model.PurposeEmailVerification); err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", claims.Subject).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return userErr.ErrInvalidToken
+			}
+			return err
+		}
+		if user.IsEmailVerified() {
+			return nil
+		}
+
+		user.MarkEmailVerified(time.Now())
+		return tx.Model(&user).Update("email_verified_at", user.EmailVerifiedAt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
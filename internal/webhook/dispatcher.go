@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"booking/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Dispatcher mengirim delivery webhook secara berkala
+type Dispatcher struct {
+	webhookService WebhookServiceInterface
+	logger         logger.Logger
+	interval       time.Duration
+}
+
+func NewDispatcher(webhookService WebhookServiceInterface, logger logger.Logger, interval time.Duration) *Dispatcher {
+	return &Dispatcher{
+		webhookService: webhookService,
+		logger:         logger,
+		interval:       interval,
+	}
+}
+
+// Start berjalan sampai ctx dibatalkan; interval <= 0 menonaktifkan dispatcher
+func (d *Dispatcher) Start(ctx context.Context) {
+	if d.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.webhookService.DispatchPending(ctx); err != nil {
+				d.logger.WithFields(logrus.Fields{
+					"error": err.Error(),
+				}).Error("Gagal menjalankan dispatcher webhook")
+			}
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"booking/pkg/httpclient"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+
+	// MaxDeliveryAttempts adalah batas percobaan kirim satu delivery
+	MaxDeliveryAttempts = 10
+	// MaxConsecutiveFailures adalah jumlah kegagalan beruntun sebelum endpoint dinonaktifkan
+	MaxConsecutiveFailures = 20
+
+	// WildcardEvent berlangganan semua event
+	WildcardEvent = "*"
+
+	backoffBase = time.Minute
+	backoffMax  = 6 * time.Hour
+)
+
+// SupportedEvents adalah event yang bisa dilanggan endpoint webhook
+var SupportedEvents = []constants.EventType{
+	constants.EventBookingCreated,
+	constants.EventBookingPaid,
+	constants.EventBookingCancelled,
+	constants.EventSpaceCreated,
+	constants.EventSpaceUpdated,
+	constants.EventSpaceDeleted,
+	constants.EventSpaceRestored,
+}
+
+// Endpoint adalah URL milik sistem lain yang menerima event
+type Endpoint struct {
+	ID                  uuid.UUID  `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID      uuid.UUID  `json:"organization_id" gorm:"type:char(36);not null;index"`
+	URL                 string     `json:"url" gorm:"size:2048;not null"`
+	Description         string     `json:"description" gorm:"size:255"`
+	Secret              string     `json:"-" gorm:"size:64;not null"`
+	Events              string     `json:"-" gorm:"type:text;not null"`
+	IsActive            bool       `json:"is_active" gorm:"not null;default:true"`
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// Delivery adalah satu event yang harus dikirim ke satu endpoint
+type Delivery struct {
+	ID             uuid.UUID           `json:"id" gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID           `json:"organization_id" gorm:"type:char(36);not null;index"`
+	EndpointID     uuid.UUID           `json:"endpoint_id" gorm:"type:char(36);not null;index"`
+	EventID        uuid.UUID           `json:"event_id" gorm:"type:char(36);not null;index"`
+	EventType      constants.EventType `json:"event_type" gorm:"type:varchar(50);not null"`
+	Payload        string              `json:"payload" gorm:"type:mediumtext;not null"`
+	Status         DeliveryStatus      `json:"status" gorm:"type:varchar(10);not null;index:idx_webhook_delivery_dispatch"`
+	Attempts       int                 `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time           `json:"next_attempt_at" gorm:"not null;index:idx_webhook_delivery_dispatch"`
+	LastError      string              `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt    *time.Time          `json:"delivered_at"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+
+	AttemptLogs []DeliveryAttempt `json:"attempt_logs,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+// DeliveryAttempt mencatat hasil setiap percobaan kirim
+type DeliveryAttempt struct {
+	ID           uuid.UUID `json:"id" gorm:"type:char(36);primary_key"`
+	DeliveryID   uuid.UUID `json:"delivery_id" gorm:"type:char(36);not null;index"`
+	Attempt      int       `json:"attempt" gorm:"not null"`
+	StatusCode   int       `json:"status_code"`
+	ResponseBody string    `json:"response_body,omitempty" gorm:"type:text"`
+	Error        string    `json:"error,omitempty" gorm:"type:text"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Event adalah envelope JSON yang dikirim ke endpoint
+type Event struct {
+	ID        uuid.UUID           `json:"id"`
+	Type      constants.EventType `json:"type"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      interface{}         `json:"data"`
+}
+
+// DTO: Create/update endpoint input
+type EndpointInput struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Description string   `json:"description" validate:"max=255"`
+	Events      []string `json:"events" validate:"required,min=1"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// EndpointResponse menampilkan endpoint; Secret hanya diisi saat dibuat atau dirotasi
+type EndpointResponse struct {
+	Endpoint
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+func NewEndpoint(input EndpointInput) (*Endpoint, error) {
+	endpoint := &Endpoint{
+		ID:       uuid.New(),
+		IsActive: true,
+	}
+	if err := endpoint.Update(input); err != nil {
+		return nil, err
+	}
+	if err := endpoint.RotateSecret(); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (e *Endpoint) Update(input EndpointInput) error {
+	u, err := url.Parse(input.URL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("webhook url must be an https URL")
+	}
+	// alamat IP langsung ditolak lebih awal; hostname dicek lagi saat dispatcher terhubung
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !httpclient.IsPublic(addr) {
+		return errors.New("webhook url must not point to an internal address")
+	}
+
+	events, err := normalizeEvents(input.Events)
+	if err != nil {
+		return err
+	}
+
+	e.URL = input.URL
+	e.Description = input.Description
+	e.Events = strings.Join(events, ",")
+	if input.IsActive != nil {
+		if *input.IsActive {
+			e.Enable()
+		} else {
+			e.IsActive = false
+		}
+	}
+	return nil
+}
+
+func (e *Endpoint) RotateSecret() error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	e.Secret = "whsec_" + hex.EncodeToString(buf)[:58]
+	return nil
+}
+
+func (e *Endpoint) EventList() []string {
+	if e.Events == "" {
+		return nil
+	}
+	return strings.Split(e.Events, ",")
+}
+
+// Subscribes mengecek apakah endpoint berlangganan event tertentu
+func (e *Endpoint) Subscribes(event constants.EventType) bool {
+	for _, ev := range e.EventList() {
+		if ev == WildcardEvent || ev == string(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordSuccess mereset hitungan kegagalan beruntun
+func (e *Endpoint) RecordSuccess() {
+	e.ConsecutiveFailures = 0
+}
+
+// RecordFailure menambah kegagalan beruntun dan menonaktifkan endpoint jika melewati batas.
+// Mengembalikan true jika endpoint baru saja dinonaktifkan.
+func (e *Endpoint) RecordFailure(now time.Time) bool {
+	e.ConsecutiveFailures++
+	if e.IsActive && e.ConsecutiveFailures >= MaxConsecutiveFailures {
+		e.IsActive = false
+		e.DisabledAt = &now
+		return true
+	}
+	return false
+}
+
+// Enable mengaktifkan kembali endpoint dan mereset hitungan kegagalan
+func (e *Endpoint) Enable() {
+	e.IsActive = true
+	e.ConsecutiveFailures = 0
+	e.DisabledAt = nil
+}
+
+func (e *Endpoint) ToResponse(withSecret bool) EndpointResponse {
+	resp := EndpointResponse{
+		Endpoint: *e,
+		Events:   e.EventList(),
+	}
+	if withSecret {
+		resp.Secret = e.Secret
+	}
+	return resp
+}
+
+func NewDelivery(endpointID uuid.UUID, event Event, payload string) *Delivery {
+	return &Delivery{
+		ID:            uuid.New(),
+		EndpointID:    endpointID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+func (d *Delivery) MarkSucceeded(now time.Time) {
+	d.Attempts++
+	d.Status = DeliveryStatusSucceeded
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkFailed menjadwalkan ulang dengan exponential backoff atau menandai gagal permanen
+func (d *Delivery) MarkFailed(reason string, now time.Time) {
+	d.Attempts++
+	d.LastError = reason
+	if d.Attempts >= MaxDeliveryAttempts {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(Backoff(d.Attempts))
+}
+
+// Redeliver menjadwalkan delivery untuk dikirim ulang segera (manual oleh admin)
+func (d *Delivery) Redeliver(now time.Time) {
+	d.Status = DeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+}
+
+// Backoff menghitung jeda sebelum percobaan berikutnya: 1m, 2m, 4m, ... maksimal 6 jam
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := backoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= backoffMax {
+			return backoffMax
+		}
+	}
+	return d
+}
+
+func normalizeEvents(events []string) ([]string, error) {
+	seen := make(map[string]bool, len(events))
+	var result []string
+	for _, ev := range events {
+		ev = strings.TrimSpace(ev)
+		if seen[ev] {
+			continue
+		}
+		if ev != WildcardEvent && !isSupportedEvent(ev) {
+			return nil, errors.New("unsupported event type: " + ev)
+		}
+		seen[ev] = true
+		result = append(result, ev)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	return result, nil
+}
+
+func isSupportedEvent(event string) bool {
+	for _, ev := range SupportedEvents {
+		if string(ev) == event {
+			return true
+		}
+	}
+	return false
+}
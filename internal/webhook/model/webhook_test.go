@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"booking/shared/constants"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookModelTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhookModelSuite(t *testing.T) {
+	suite.Run(t, new(WebhookModelTestSuite))
+}
+
+func (s *WebhookModelTestSuite) TestNewEndpoint() {
+	endpoint, err := NewEndpoint(EndpointInput{
+		URL:    "https://accounting.example.com/hooks",
+		Events: []string{"booking.created", " booking.cancelled", "booking.created"},
+	})
+	s.Require().NoError(err)
+	s.True(endpoint.IsActive)
+	s.Equal("booking.created,booking.cancelled", endpoint.Events)
+	s.Len(endpoint.Secret, 64)
+	s.True(endpoint.Subscribes(constants.EventBookingCancelled))
+	s.False(endpoint.Subscribes(constants.EventSpaceUpdated))
+}
+
+func (s *WebhookModelTestSuite) TestNewEndpointInvalid() {
+	for _, url := range []string{
+		"ftp://example.com",
+		"http://example.com/hooks",
+		"https://127.0.0.1/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]:8443/hooks",
+	} {
+		_, err := NewEndpoint(EndpointInput{URL: url, Events: []string{"booking.created"}})
+		s.Error(err, url)
+	}
+
+	_, err := NewEndpoint(EndpointInput{URL: "https://example.com", Events: []string{"booking.refunded"}})
+	s.Error(err)
+}
+
+func (s *WebhookModelTestSuite) TestWildcardSubscription() {
+	endpoint, err := NewEndpoint(EndpointInput{URL: "https://example.com", Events: []string{WildcardEvent}})
+	s.Require().NoError(err)
+	s.True(endpoint.Subscribes(constants.EventSpaceDeleted))
+}
+
+func (s *WebhookModelTestSuite) TestAutoDisable() {
+	endpoint := &Endpoint{IsActive: true}
+	now := time.Now()
+
+	for i := 1; i < MaxConsecutiveFailures; i++ {
+		s.False(endpoint.RecordFailure(now))
+	}
+	s.True(endpoint.RecordFailure(now))
+	s.False(endpoint.IsActive)
+	s.NotNil(endpoint.DisabledAt)
+
+	endpoint.Enable()
+	s.True(endpoint.IsActive)
+	s.Zero(endpoint.ConsecutiveFailures)
+}
+
+func (s *WebhookModelTestSuite) TestBackoff() {
+	s.Equal(time.Minute, Backoff(1))
+	s.Equal(4*time.Minute, Backoff(3))
+	s.Equal(6*time.Hour, Backoff(20))
+}
+
+func (s *WebhookModelTestSuite) TestDeliveryRetries() {
+	delivery := &Delivery{Status: DeliveryStatusPending}
+	now := time.Now()
+
+	delivery.MarkFailed("timeout", now)
+	s.Equal(DeliveryStatusPending, delivery.Status)
+	s.Equal(now.Add(time.Minute), delivery.NextAttemptAt)
+
+	for delivery.Status == DeliveryStatusPending {
+		delivery.MarkFailed("timeout", now)
+	}
+	s.Equal(DeliveryStatusFailed, delivery.Status)
+	s.Equal(MaxDeliveryAttempts, delivery.Attempts)
+
+	delivery.Redeliver(now)
+	s.Equal(DeliveryStatusPending, delivery.Status)
+	s.Zero(delivery.Attempts)
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"booking/internal/webhook/model"
+)
+
+const (
+	// SendTimeout adalah batas waktu satu request ke endpoint
+	SendTimeout = 10 * time.Second
+
+	// maxResponseBody membatasi body respons yang disimpan di log percobaan
+	maxResponseBody = 2048
+)
+
+type attemptResult struct {
+	statusCode   int
+	responseBody string
+	err          error
+	duration     time.Duration
+}
+
+func (r attemptResult) succeeded() bool {
+	return r.err == nil && r.statusCode >= 200 && r.statusCode < 300
+}
+
+func (r attemptResult) reason() string {
+	if r.err != nil {
+		return r.err.Error()
+	}
+	return fmt.Sprintf("endpoint responded with status %d", r.statusCode)
+}
+
+// send mengirim payload delivery ke endpoint dengan header signature
+func send(ctx context.Context, client *http.Client, endpoint *model.Endpoint, delivery *model.Delivery) attemptResult {
+	start := time.Now()
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return attemptResult{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-booking-webhooks/1.0")
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, start.Unix(), body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return attemptResult{err: err, duration: time.Since(start)}
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return attemptResult{
+		statusCode:   resp.StatusCode,
+		responseBody: string(respBody),
+		duration:     time.Since(start),
+	}
+}
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+
+	"booking/internal/webhook/model"
+	"booking/pkg/response"
+	"booking/shared/validate"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	webhookService WebhookServiceInterface
+}
+
+func NewWebhookHandler(webhookService WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Create mendaftarkan endpoint; secret hanya ditampilkan sekali di respons ini
+func (h *WebhookHandler) Create(c echo.Context) error {
+	var input model.EndpointInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Request().Context(), input)
+	if err != nil {
+		return response.BadRequest(c, "failed to create webhook endpoint", err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Webhook endpoint created successfully", endpoint.ToResponse(true))
+}
+
+func (h *WebhookHandler) GetAll(c echo.Context) error {
+	endpoints, err := h.webhookService.GetEndpoints(c.Request().Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get webhook endpoints", err)
+	}
+
+	result := make([]model.EndpointResponse, 0, len(endpoints))
+	for i := range endpoints {
+		result = append(result, endpoints[i].ToResponse(false))
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook endpoints retrieved successfully", result)
+}
+
+func (h *WebhookHandler) GetByID(c echo.Context) error {
+	endpoint, err := h.webhookService.GetEndpoint(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.notFoundOr(c, "failed to get webhook endpoint", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook endpoint retrieved successfully", endpoint.ToResponse(false))
+}
+
+func (h *WebhookHandler) Update(c echo.Context) error {
+	var input model.EndpointInput
+	if err := validate.BindAndValidate(c, &input); err != nil {
+		return err
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.Request().Context(), c.Param("id"), input)
+	if err != nil {
+		if errors.Is(err, ErrEndpointNotFound) {
+			return response.NotFound(c, "webhook endpoint not found", err)
+		}
+		return response.BadRequest(c, "failed to update webhook endpoint", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook endpoint updated successfully", endpoint.ToResponse(false))
+}
+
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	if err := h.webhookService.DeleteEndpoint(c.Request().Context(), c.Param("id")); err != nil {
+		return h.notFoundOr(c, "failed to delete webhook endpoint", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook endpoint deleted successfully", nil)
+}
+
+func (h *WebhookHandler) RotateSecret(c echo.Context) error {
+	endpoint, err := h.webhookService.RotateSecret(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.notFoundOr(c, "failed to rotate webhook secret", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook secret rotated successfully", endpoint.ToResponse(true))
+}
+
+// GetDeliveries menampilkan delivery milik endpoint; ?status=pending|succeeded|failed
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	deliveries, err := h.webhookService.GetDeliveries(c.Request().Context(), c.Param("id"), c.QueryParam("status"))
+	if err != nil {
+		return response.InternalServerError(c, "failed to get webhook deliveries", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook deliveries retrieved successfully", deliveries)
+}
+
+func (h *WebhookHandler) GetDelivery(c echo.Context) error {
+	delivery, err := h.webhookService.GetDelivery(c.Request().Context(), c.Param("deliveryId"))
+	if err != nil {
+		return h.notFoundOr(c, "failed to get webhook delivery", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook delivery retrieved successfully", delivery)
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	delivery, err := h.webhookService.Redeliver(c.Request().Context(), c.Param("deliveryId"))
+	if err != nil {
+		return h.notFoundOr(c, "failed to redeliver webhook", err)
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook delivery scheduled for redelivery", delivery)
+}
+
+func (h *WebhookHandler) notFoundOr(c echo.Context, message string, err error) error {
+	if errors.Is(err, ErrEndpointNotFound) || errors.Is(err, ErrDeliveryNotFound) {
+		return response.NotFound(c, err.Error(), err)
+	}
+	return response.InternalServerError(c, message, err)
+}
@@ -0,0 +1,342 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"booking/internal/webhook/model"
+	"booking/pkg/logger"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// dispatchBatchSize membatasi jumlah delivery yang diproses dalam satu putaran dispatcher
+	dispatchBatchSize = 50
+	// dispatchLease menyembunyikan delivery yang sudah diklaim dari dispatcher lain selama
+	// dikirim; harus lebih lama dari satu batch (dispatchBatchSize x SendTimeout)
+	dispatchLease = 15 * time.Minute
+)
+
+var (
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// WebhookServiceInterface mendefinisikan kontrak untuk WebhookService
+type WebhookServiceInterface interface {
+	Publish(tx *gorm.DB, event constants.EventType, data interface{}) error
+	CreateEndpoint(ctx context.Context, input model.EndpointInput) (*model.Endpoint, error)
+	GetEndpoints(ctx context.Context) ([]model.Endpoint, error)
+	GetEndpoint(ctx context.Context, id string) (*model.Endpoint, error)
+	UpdateEndpoint(ctx context.Context, id string, input model.EndpointInput) (*model.Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	RotateSecret(ctx context.Context, id string) (*model.Endpoint, error)
+	GetDeliveries(ctx context.Context, endpointID string, status string) ([]model.Delivery, error)
+	GetDelivery(ctx context.Context, id string) (*model.Delivery, error)
+	Redeliver(ctx context.Context, id string) (*model.Delivery, error)
+	DispatchPending(ctx context.Context) (int, error)
+}
+
+type WebhookService struct {
+	db         *gorm.DB
+	logger     logger.Logger
+	httpClient *http.Client
+}
+
+// NewWebhookService menerima httpClient yang dipakai dispatcher; di produksi gunakan
+// httpclient.NewPublic agar endpoint tidak bisa diarahkan ke alamat internal
+func NewWebhookService(db *gorm.DB, logger logger.Logger, httpClient *http.Client) *WebhookService {
+	return &WebhookService{
+		db:         db,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+// Publish membuat delivery untuk setiap endpoint aktif yang berlangganan event, memakai tx
+// milik pemanggil agar event hanya terkirim jika perubahan data ter-commit
+func (s *WebhookService) Publish(tx *gorm.DB, event constants.EventType, data interface{}) error {
+	var endpoints []model.Endpoint
+	if err := tx.Where("is_active = ?", true).Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	envelope := model.Event{
+		ID:        uuid.New(),
+		Type:      event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	var payload []byte
+
+	for i := range endpoints {
+		if !endpoints[i].Subscribes(event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(envelope); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(model.NewDelivery(endpoints[i].ID, envelope, string(payload))).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) CreateEndpoint(ctx context.Context, input model.EndpointInput) (*model.Endpoint, error) {
+	endpoint, err := model.NewEndpoint(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(endpoint).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"url":   input.URL,
+			"error": err.Error(),
+		}).Error("Gagal menyimpan webhook endpoint")
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) GetEndpoints(ctx context.Context) ([]model.Endpoint, error) {
+	var endpoints []model.Endpoint
+	if err := s.db.WithContext(ctx).Order("created_at").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (s *WebhookService) GetEndpoint(ctx context.Context, id string) (*model.Endpoint, error) {
+	var endpoint model.Endpoint
+	if err := s.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEndpointNotFound
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, id string, input model.EndpointInput) (*model.Endpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := endpoint.Update(input); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Save(endpoint).Error; err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id string) error {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deliveryIDs := tx.Model(&model.Delivery{}).Select("id").Where("endpoint_id = ?", endpoint.ID)
+		if err := tx.Where("delivery_id IN (?)", deliveryIDs).Delete(&model.DeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("endpoint_id = ?", endpoint.ID).Delete(&model.Delivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(endpoint).Error
+	})
+}
+
+func (s *WebhookService) RotateSecret(ctx context.Context, id string) (*model.Endpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := endpoint.RotateSecret(); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Save(endpoint).Error; err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, endpointID string, status string) ([]model.Delivery, error) {
+	query := s.db.WithContext(ctx).Where("endpoint_id = ?", endpointID).Order("created_at DESC").Limit(200)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []model.Delivery
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, id string) (*model.Delivery, error) {
+	var delivery model.Delivery
+	err := s.db.WithContext(ctx).
+		Preload("AttemptLogs", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		First(&delivery, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// Redeliver menjadwalkan ulang delivery (termasuk yang sudah berhasil) untuk dikirim segera
+func (s *WebhookService) Redeliver(ctx context.Context, id string) (*model.Delivery, error) {
+	delivery, err := s.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Redeliver(time.Now())
+	if err := s.db.WithContext(ctx).Omit("AttemptLogs").Save(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DispatchPending mengirim delivery yang sudah jatuh tempo, mencatat setiap percobaan,
+// dan menonaktifkan endpoint yang gagal beruntun. Delivery diklaim di transaksi singkat lalu
+// dikirim di luar transaksi, sehingga lock baris tidak ditahan selama request HTTP dan hasil
+// setiap delivery disimpan terpisah tanpa membatalkan delivery lain yang sudah terkirim.
+func (s *WebhookService) DispatchPending(ctx context.Context) (int, error) {
+	deliveries, err := s.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	var errs error
+	endpoints := make(map[uuid.UUID]*model.Endpoint)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			var e model.Endpoint
+			if err := s.db.WithContext(ctx).First(&e, "id = ?", delivery.EndpointID).Error; err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					errs = errors.Join(errs, err)
+					continue
+				}
+			} else {
+				endpoint = &e
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+
+		ok, err := s.deliver(ctx, endpoint, delivery)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"delivery_id": delivery.ID,
+				"error":       err.Error(),
+			}).Error("Gagal menyimpan hasil pengiriman webhook")
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if ok {
+			succeeded++
+		}
+	}
+	return succeeded, errs
+}
+
+// claim mengunci delivery yang jatuh tempo dan memundurkan next_attempt_at sebagai lease;
+// jika proses mati sebelum hasilnya disimpan, delivery dicoba lagi setelah lease habis
+func (s *WebhookService) claim(ctx context.Context) ([]model.Delivery, error) {
+	var deliveries []model.Delivery
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.DeliveryStatusPending, now).
+			Order("next_attempt_at").
+			Limit(dispatchBatchSize).
+			Find(&deliveries).Error; err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&model.Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(dispatchLease)).Error
+	})
+	return deliveries, err
+}
+
+// deliver mengirim satu delivery lalu menyimpan percobaan, delivery dan status endpoint
+// dalam satu transaksi kecil. Mengembalikan true jika endpoint menerima delivery.
+func (s *WebhookService) deliver(ctx context.Context, endpoint *model.Endpoint, delivery *model.Delivery) (bool, error) {
+	if endpoint == nil || !endpoint.IsActive {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.LastError = "endpoint is disabled"
+		return false, s.db.WithContext(ctx).Save(delivery).Error
+	}
+
+	result := send(ctx, s.httpClient, endpoint, delivery)
+	now := time.Now()
+	if result.succeeded() {
+		delivery.MarkSucceeded(now)
+		endpoint.RecordSuccess()
+	} else {
+		delivery.MarkFailed(result.reason(), now)
+		if endpoint.RecordFailure(now) {
+			s.logger.WithFields(logrus.Fields{
+				"endpoint_id": endpoint.ID,
+				"url":         endpoint.URL,
+			}).Warn("Webhook endpoint dinonaktifkan karena gagal beruntun")
+		}
+	}
+
+	attempt := &model.DeliveryAttempt{
+		ID:           uuid.New(),
+		DeliveryID:   delivery.ID,
+		Attempt:      delivery.Attempts,
+		StatusCode:   result.statusCode,
+		ResponseBody: result.responseBody,
+		DurationMs:   result.duration.Milliseconds(),
+	}
+	if result.err != nil {
+		attempt.Error = result.err.Error()
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(delivery).Error; err != nil {
+			return err
+		}
+		// hanya kolom status yang ditulis agar perubahan URL/secret oleh admin selama
+		// pengiriman tidak tertimpa
+		return tx.Model(endpoint).
+			Select("consecutive_failures", "is_active", "disabled_at").
+			Updates(endpoint).Error
+	})
+	return result.succeeded(), err
+}
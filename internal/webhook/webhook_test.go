@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"booking/internal/webhook/model"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhookSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (s *WebhookTestSuite) TestSignAndVerify() {
+	body := []byte(`{"type":"booking.created"}`)
+	now := time.Unix(1790000000, 0)
+	header := Sign("whsec_test", now.Unix(), body)
+
+	s.NoError(VerifySignature("whsec_test", header, body, 5*time.Minute, now))
+	s.ErrorIs(VerifySignature("whsec_other", header, body, 5*time.Minute, now), ErrInvalidSignature)
+	s.ErrorIs(VerifySignature("whsec_test", header, []byte(`{}`), 5*time.Minute, now), ErrInvalidSignature)
+	s.ErrorIs(VerifySignature("whsec_test", header, body, 5*time.Minute, now.Add(10*time.Minute)), ErrInvalidSignature)
+	s.ErrorIs(VerifySignature("whsec_test", "garbage", body, 0, now), ErrInvalidSignature)
+}
+
+func (s *WebhookTestSuite) TestSend() {
+	endpoint := &model.Endpoint{ID: uuid.New(), Secret: "whsec_test", IsActive: true}
+	delivery := model.NewDelivery(endpoint.ID, model.Event{ID: uuid.New(), Type: constants.EventBookingCreated}, `{"id":"1"}`)
+
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = VerifySignature("whsec_test", r.Header.Get(SignatureHeader), body, time.Minute, time.Now())
+		s.Equal(string(constants.EventBookingCreated), r.Header.Get(EventHeader))
+		s.Equal(delivery.ID.String(), r.Header.Get(DeliveryHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	endpoint.URL = server.URL
+
+	result := send(context.Background(), server.Client(), endpoint, delivery)
+	s.NoError(verifyErr)
+	s.True(result.succeeded())
+	s.Equal(http.StatusNoContent, result.statusCode)
+}
+
+func (s *WebhookTestSuite) TestSendNon2xxFails() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	endpoint := &model.Endpoint{ID: uuid.New(), URL: server.URL, Secret: "whsec_test", IsActive: true}
+	delivery := model.NewDelivery(endpoint.ID, model.Event{ID: uuid.New(), Type: constants.EventSpaceUpdated}, `{}`)
+
+	result := send(context.Background(), server.Client(), endpoint, delivery)
+	s.False(result.succeeded())
+	s.Equal("boom", result.responseBody)
+	s.Equal("endpoint responded with status 500", result.reason())
+}
@@ -3,12 +3,21 @@ package database
 import (
 	"fmt"
 
+	apikeyModel "booking/internal/apikey/model"
+	bookingModel "booking/internal/booking/model"
+	calendarModel "booking/internal/calendar/model"
 	categoryModel "booking/internal/category/model"
+	currencyModel "booking/internal/currency/model"
 	facilityModel "booking/internal/facility/model"
+	notificationModel "booking/internal/notification/model"
+	organizationModel "booking/internal/organization/model"
+	roleModel "booking/internal/role/model"
+	sessionModel "booking/internal/session/model"
 	spaceModel "booking/internal/space/model"
 	spaceFacilityModel "booking/internal/space_facility/model"
 	userModel "booking/internal/user/model"
-	bookingModel "booking/internal/booking/model"
+	webhookModel "booking/internal/webhook/model"
+	"booking/pkg/tenant"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -23,15 +32,49 @@ func InitDB(host, port, user, password, dbname string) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Semua query dengan ctx tenant otomatis dibatasi ke organisasi tersebut
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		return nil, err
+	}
+
+	// Slug lama harus unik sebelum unique index kategori dibuat oleh AutoMigrate
+	if err := normalizeCategorySlugs(db); err != nil {
+		return nil, err
+	}
+
+	// User lama dibuat sebelum ada verifikasi email
+	legacyUsers := db.Migrator().HasTable(&userModel.User{}) && !db.Migrator().HasColumn(&userModel.User{}, "EmailVerifiedAt")
+
 	// Auto Migrate
 	err = db.AutoMigrate(
+		&organizationModel.Organization{}, &roleModel.Role{},
 		&userModel.User{}, &categoryModel.Category{},
 		&spaceModel.Space{}, &facilityModel.Facility{},
-		&spaceFacilityModel.SpaceFacility{},&bookingModel.Booking{},
+		&spaceFacilityModel.SpaceFacility{}, &bookingModel.Booking{},
+		&currencyModel.ExchangeRate{}, &spaceModel.Blackout{},
+		&spaceModel.Photo{},
+		&calendarModel.CalendarFeed{}, &calendarModel.ExternalCalendar{},
+		&calendarModel.ExternalBlock{}, &notificationModel.OutboxMessage{},
+		&webhookModel.Endpoint{}, &webhookModel.Delivery{},
+		&webhookModel.DeliveryAttempt{},
+		&sessionModel.Session{}, &sessionModel.RefreshToken{},
+		&sessionModel.SigningKey{},
+		&userModel.UserToken{}, &userModel.RecoveryCode{},
+		&userModel.UserIdentity{}, &apikeyModel.APIKey{}, &userModel.AdminAuditLog{},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if legacyUsers {
+		if err := verifyLegacyUsers(db); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := migrateTenants(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
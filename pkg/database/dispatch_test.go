@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"booking/internal/notification"
+	"booking/internal/webhook"
+	"booking/pkg/logger"
+	"booking/pkg/mailer"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// DispatchTestSuite memastikan dispatcher tidak menahan transaksi selama mengirim ke sistem
+// luar dan menyimpan hasil setiap pesan secara terpisah
+type DispatchTestSuite struct {
+	suite.Suite
+	store *fakeStore
+	db    *gorm.DB
+}
+
+func TestDispatchSuite(t *testing.T) {
+	suite.Run(t, new(DispatchTestSuite))
+}
+
+func (s *DispatchTestSuite) SetupTest() {
+	s.store = newFakeStore()
+	db, err := s.store.open()
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *DispatchTestSuite) TestWebhookSendsOutsideTransaction() {
+	org, endpoint := uuid.NewString(), uuid.NewString()
+	delivered, failing := uuid.NewString(), uuid.NewString()
+
+	inTransaction := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inTransaction = inTransaction || s.store.inTransaction()
+		if r.Header.Get(webhook.DeliveryHeader) == failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s.store.seed("endpoints", fakeRow{"id": endpoint, "organization_id": org, "url": server.URL,
+		"secret": "whsec_test", "events": "*", "is_active": true, "consecutive_failures": int64(0)})
+	for _, id := range []string{delivered, failing} {
+		s.store.seed("deliveries", fakeRow{"id": id, "organization_id": org, "endpoint_id": endpoint,
+			"event_id": uuid.NewString(), "event_type": "booking.created", "payload": "{}",
+			"status": "pending", "attempts": int64(0), "next_attempt_at": time.Now()})
+	}
+
+	service := webhook.NewWebhookService(s.db, logger.NewLogger(), server.Client())
+	succeeded, err := service.DispatchPending(context.Background())
+	s.Require().NoError(err)
+	s.Equal(1, succeeded)
+	s.False(inTransaction, "webhook was sent while a transaction was open")
+	s.False(s.store.inTransaction())
+
+	statuses := map[string]string{}
+	for _, row := range s.store.rows("deliveries") {
+		statuses[fakeString(row["id"])] = fakeString(row["status"])
+	}
+	s.Equal("succeeded", statuses[delivered])
+	s.Equal("pending", statuses[failing])
+	s.Len(s.store.rows("delivery_attempts"), 2)
+
+	endpoints := s.store.rows("endpoints")
+	s.Require().Len(endpoints, 1)
+	s.Equal(server.URL, fakeString(endpoints[0]["url"]))
+	s.Equal("1", fakeString(endpoints[0]["consecutive_failures"]))
+}
+
+// recordingMailer mencatat apakah Send dipanggil saat transaksi masih terbuka
+type recordingMailer struct {
+	store         *fakeStore
+	fail          string
+	inTransaction bool
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.inTransaction = m.inTransaction || m.store.inTransaction()
+	if msg.To == m.fail {
+		return errors.New("mailbox unavailable")
+	}
+	return nil
+}
+
+func (s *DispatchTestSuite) TestNotificationSendsOutsideTransaction() {
+	org := uuid.NewString()
+	sent, failing := uuid.NewString(), uuid.NewString()
+	for _, id := range []string{sent, failing} {
+		s.store.seed("outbox_messages", fakeRow{"id": id, "organization_id": org, "event_type": "booking.created",
+			"aggregate_id": uuid.NewString(), "recipient": id + "@example.com", "locale": "id",
+			"subject": "Booking", "body": "Halo", "status": "pending", "attempts": int64(0), "next_attempt_at": time.Now()})
+	}
+
+	m := &recordingMailer{store: s.store, fail: failing + "@example.com"}
+	service := notification.NewNotificationService(s.db, logger.NewLogger(), m)
+	count, err := service.DispatchPending(context.Background())
+	s.Require().NoError(err)
+	s.Equal(1, count)
+	s.False(m.inTransaction, "email was sent while a transaction was open")
+	s.False(s.store.inTransaction())
+
+	statuses := map[string]string{}
+	for _, row := range s.store.rows("outbox_messages") {
+		statuses[fakeString(row["id"])] = fakeString(row["status"])
+	}
+	s.Equal("sent", statuses[sent])
+	s.Equal("pending", statuses[failing])
+}
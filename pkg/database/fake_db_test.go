@@ -0,0 +1,444 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"booking/pkg/tenant"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeStore adalah database di memori untuk test isolasi tenant. SQL tidak benar-benar
+// dieksekusi: kondisi "kolom = ?", "kolom IN (?)" dan "kolom IS [NOT] NULL" pada tabel utama
+// dicocokkan dengan baris yang di-seed, kondisi lain diabaikan (dianggap cocok). Karena
+// longgar, query yang lupa dibatasi tenant akan menemukan baris organisasi lain sehingga
+// kebocoran ikut terdeteksi.
+type fakeStore struct {
+	mu       sync.Mutex
+	tables   map[string][]fakeRow
+	written  []fakeRow
+	inserted []fakeRow
+	openTx   int
+}
+
+// fakeRow adalah satu baris; key berawalan "_" tidak dikembalikan sebagai kolom
+type fakeRow map[string]driver.Value
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{tables: map[string][]fakeRow{}}
+}
+
+// open membuat koneksi GORM ke store dengan plugin tenant seperti di InitDB
+func (s *fakeStore) open() (*gorm.DB, error) {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(fakeConnector{store: s}),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (s *fakeStore) seed(table string, row fakeRow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables[table] = append(s.tables[table], row)
+}
+
+// touched mengembalikan baris yang diubah, dihapus atau dibuat dan berisi salah satu ids
+func (s *fakeStore) touched(ids ...string) []fakeRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []fakeRow
+	for _, row := range append(append([]fakeRow{}, s.written...), s.inserted...) {
+		for _, value := range row {
+			for _, id := range ids {
+				if fakeString(value) == id {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+var (
+	fakeTableRe   = regexp.MustCompile("(?i)^(?:SELECT .*? FROM|UPDATE|DELETE FROM|INSERT(?: IGNORE)? INTO)\\s+`?(\\w+)`?")
+	fakeEqRe      = regexp.MustCompile("(?:`?(\\w+)`?\\.)?`?(\\w+)`?\\s*=\\s*\\?")
+	fakeInRe      = regexp.MustCompile("(?i)(?:`?(\\w+)`?\\.)?`?(\\w+)`?\\s+IN\\s*\\(([?,\\s]+)\\)")
+	fakeNullRe    = regexp.MustCompile("(?i)(?:`?(\\w+)`?\\.)?`?(\\w+)`?\\s+IS\\s+(NOT\\s+)?NULL")
+	fakeColumnsRe = regexp.MustCompile("(?i)^INSERT(?: IGNORE)? INTO\\s+`?\\w+`?\\s*\\(([^)]*)\\)\\s*VALUES")
+	fakeSingleRe  = regexp.MustCompile("(?i)^SELECT\\s+(?:`?\\w+`?\\.)?`?(\\w+)`?\\s+FROM ")
+	fakeEndRe     = regexp.MustCompile("(?i) (ORDER BY|GROUP BY|LIMIT|FOR UPDATE|ON DUPLICATE KEY)")
+)
+
+// fakeCondition adalah satu kondisi WHERE yang dipahami fakeStore
+type fakeCondition struct {
+	column string
+	values []driver.Value
+	null   *bool
+}
+
+func (c fakeCondition) match(row fakeRow) bool {
+	value, ok := row[c.column]
+	if c.null != nil {
+		return (!ok || value == nil) == *c.null
+	}
+	if !ok {
+		return true
+	}
+	for _, want := range c.values {
+		if fakeString(value) == fakeString(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse mengambil tabel utama, kondisi WHERE dan assignment SET dari query
+func (s *fakeStore) parse(query string, args []driver.Value) (string, []fakeCondition, map[string]driver.Value) {
+	query = strings.Join(strings.Fields(query), " ")
+	match := fakeTableRe.FindStringSubmatch(query)
+	if match == nil {
+		return "", nil, nil
+	}
+	table := match[1]
+
+	// posisi setiap placeholder menentukan argumen yang dipakai
+	argAt := func(offset int) driver.Value {
+		index := strings.Count(query[:offset], "?")
+		if index < len(args) {
+			return args[index]
+		}
+		return nil
+	}
+	ownColumn := func(qualifier, column string) bool {
+		return (qualifier == "" || qualifier == table) && !strings.EqualFold(column, "NOT")
+	}
+
+	set := map[string]driver.Value{}
+	where := strings.Index(query, " WHERE ")
+	if strings.HasPrefix(strings.ToUpper(query), "UPDATE") {
+		end := len(query)
+		if where >= 0 {
+			end = where
+		}
+		start := strings.Index(query, " SET ")
+		for _, m := range fakeEqRe.FindAllStringSubmatchIndex(query[start:end], -1) {
+			column := query[start+m[4] : start+m[5]]
+			set[column] = argAt(start + m[1] - 1)
+		}
+	}
+	if where < 0 {
+		return table, nil, set
+	}
+
+	end := len(query)
+	if loc := fakeEndRe.FindStringIndex(query[where:]); loc != nil {
+		end = where + loc[0]
+	}
+	clause := query[where:end]
+	// kondisi OR tidak dievaluasi; hanya filter tenant dan soft delete yang tetap dipakai
+	or := strings.Contains(strings.ToUpper(clause), " OR ")
+
+	var conditions []fakeCondition
+	add := func(qualifier, column string, condition fakeCondition) {
+		if !ownColumn(qualifier, column) {
+			return
+		}
+		if or && column != tenant.ColumnName && column != "deleted_at" {
+			return
+		}
+		condition.column = column
+		conditions = append(conditions, condition)
+	}
+	for _, m := range fakeEqRe.FindAllStringSubmatchIndex(clause, -1) {
+		add(fakeGroup(clause, m, 1), fakeGroup(clause, m, 2), fakeCondition{values: []driver.Value{argAt(where + m[1] - 1)}})
+	}
+	for _, m := range fakeInRe.FindAllStringSubmatchIndex(clause, -1) {
+		var values []driver.Value
+		for i := m[6]; i < m[7]; i++ {
+			if clause[i] == '?' {
+				values = append(values, argAt(where+i))
+			}
+		}
+		add(fakeGroup(clause, m, 1), fakeGroup(clause, m, 2), fakeCondition{values: values})
+	}
+	for _, m := range fakeNullRe.FindAllStringSubmatchIndex(clause, -1) {
+		null := m[6] < 0
+		add(fakeGroup(clause, m, 1), fakeGroup(clause, m, 2), fakeCondition{null: &null})
+	}
+	return table, conditions, set
+}
+
+func fakeGroup(s string, m []int, group int) string {
+	if m[2*group] < 0 {
+		return ""
+	}
+	return s[m[2*group]:m[2*group+1]]
+}
+
+func (s *fakeStore) matching(table string, conditions []fakeCondition) []int {
+	var indexes []int
+	for i, row := range s.tables[table] {
+		ok := true
+		for _, condition := range conditions {
+			if !condition.match(row) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (s *fakeStore) query(query string, args []driver.Value) (driver.Rows, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, conditions, _ := s.parse(query, args)
+	indexes := s.matching(table, conditions)
+
+	upper := strings.ToUpper(query)
+	if strings.Contains(upper[:strings.Index(upper, " FROM ")+1], "COUNT(") {
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(len(indexes))}}}, nil
+	}
+	if strings.Contains(upper, "LIMIT 1") && len(indexes) > 1 {
+		indexes = indexes[:1]
+	}
+
+	columnSet := map[string]bool{}
+	for _, i := range indexes {
+		for column := range s.tables[table][i] {
+			if !strings.HasPrefix(column, "_") {
+				columnSet[column] = true
+			}
+		}
+	}
+	columns := make([]string, 0, len(columnSet))
+	for column := range columnSet {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	// select satu kolom (misalnya Pluck) hanya mengembalikan kolom tersebut
+	if m := fakeSingleRe.FindStringSubmatch(query); m != nil {
+		columns = []string{m[1]}
+	}
+
+	rows := &fakeRows{columns: columns}
+	for _, i := range indexes {
+		values := make([]driver.Value, len(columns))
+		for j, column := range columns {
+			values[j] = s.tables[table][i][column]
+		}
+		rows.values = append(rows.values, values)
+	}
+	return rows, nil
+}
+
+func (s *fakeStore) exec(query string, args []driver.Value) (driver.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, conditions, set := s.parse(query, args)
+	switch strings.ToUpper(strings.Fields(query)[0]) {
+	case "INSERT":
+		return s.insert(table, query, args), nil
+	case "UPDATE":
+		indexes := s.matching(table, conditions)
+		for _, i := range indexes {
+			for column, value := range set {
+				s.tables[table][i][column] = value
+			}
+			s.written = append(s.written, s.tables[table][i])
+		}
+		return driver.RowsAffected(len(indexes)), nil
+	case "DELETE":
+		indexes := s.matching(table, conditions)
+		kept := s.tables[table][:0]
+		removed := map[int]bool{}
+		for _, i := range indexes {
+			removed[i] = true
+			s.written = append(s.written, s.tables[table][i])
+		}
+		for i, row := range s.tables[table] {
+			if !removed[i] {
+				kept = append(kept, row)
+			}
+		}
+		s.tables[table] = kept
+		return driver.RowsAffected(len(indexes)), nil
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeStore) insert(table, query string, args []driver.Value) driver.Result {
+	match := fakeColumnsRe.FindStringSubmatch(strings.Join(strings.Fields(query), " "))
+	if match == nil {
+		return fakeResult(0)
+	}
+	var columns []string
+	for _, column := range strings.Split(match[1], ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), "`"))
+	}
+
+	count := 0
+	for start := 0; start+len(columns) <= len(args); start += len(columns) {
+		row := fakeRow{}
+		for i, column := range columns {
+			row[column] = args[start+i]
+		}
+		s.tables[table] = append(s.tables[table], row)
+		s.inserted = append(s.inserted, row)
+		count++
+	}
+	return fakeResult(count)
+}
+
+// fakeResult seperti driver.RowsAffected tetapi mendukung LastInsertId yang dipanggil GORM
+// setelah INSERT; semua primary key di sini UUID sehingga nilainya tidak dipakai
+type fakeResult int64
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func fakeString(v driver.Value) string {
+	switch value := v.(type) {
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+type fakeConnector struct {
+	store *fakeStore
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{store: c.store}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, fmt.Errorf("fake driver hanya bisa dibuka lewat connector")
+}
+
+type fakeConn struct {
+	store *fakeStore
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepared statement tidak didukung")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+// Begin tidak mendukung rollback; test hanya memeriksa baris yang disentuh dan jumlah
+// transaksi yang sedang terbuka
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	c.store.openTx++
+	return fakeTx{store: c.store}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.store.query(query, fakeValues(args))
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.store.exec(query, fakeValues(args))
+}
+
+func fakeValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+type fakeTx struct {
+	store *fakeStore
+}
+
+func (t fakeTx) Commit() error   { return t.end() }
+func (t fakeTx) Rollback() error { return t.end() }
+
+func (t fakeTx) end() error {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	t.store.openTx--
+	return nil
+}
+
+// inTransaction mengecek apakah ada transaksi yang belum di-commit atau di-rollback
+func (s *fakeStore) inTransaction() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openTx > 0
+}
+
+// rows mengembalikan salinan baris tabel
+func (s *fakeStore) rows(table string) []fakeRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeRow{}, s.tables[table]...)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,279 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"booking/internal/booking"
+	"booking/internal/calendar"
+	"booking/internal/category"
+	categoryModel "booking/internal/category/model"
+	"booking/internal/facility"
+	facilityModel "booking/internal/facility/model"
+	"booking/internal/space"
+	spaceModel "booking/internal/space/model"
+	spacefacility "booking/internal/space_facility"
+	"booking/internal/user"
+	"booking/internal/webhook"
+	"booking/pkg/logger"
+	"booking/pkg/storage"
+	"booking/pkg/tenant"
+	"booking/shared/constants"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// tenantData adalah data satu organisasi yang di-seed ke fakeStore
+type tenantData struct {
+	org        uuid.UUID
+	user       uuid.UUID
+	category   uuid.UUID
+	space      uuid.UUID
+	facility   uuid.UUID
+	photo      uuid.UUID
+	blackout   uuid.UUID
+	calendar   uuid.UUID
+	upload     uuid.UUID
+	booking    uuid.UUID
+	externalID uuid.UUID
+}
+
+func newTenantData() tenantData {
+	return tenantData{
+		org: uuid.New(), user: uuid.New(), category: uuid.New(), space: uuid.New(),
+		facility: uuid.New(), photo: uuid.New(), blackout: uuid.New(), calendar: uuid.New(),
+		upload: uuid.New(), booking: uuid.New(), externalID: uuid.New(),
+	}
+}
+
+// ids adalah semua id milik organisasi; dipakai untuk mendeteksi baris yang ikut tersentuh
+func (d tenantData) ids() []string {
+	ids := []string{}
+	for _, id := range []uuid.UUID{d.org, d.user, d.category, d.space, d.facility, d.photo,
+		d.blackout, d.calendar, d.upload, d.booking, d.externalID} {
+		ids = append(ids, id.String())
+	}
+	return ids
+}
+
+func (d tenantData) seed(store *fakeStore) {
+	org := d.org.String()
+	store.seed("users", fakeRow{"id": d.user.String(), "organization_id": org, "email": d.user.String() + "@example.com", "role": "user"})
+	store.seed("categories", fakeRow{"id": d.category.String(), "organization_id": org, "name": "Villa", "slug": "villa"})
+	store.seed("spaces", fakeRow{"id": d.space.String(), "organization_id": org, "category_id": d.category.String(), "name": "Villa", "is_active": true})
+	store.seed("facilities", fakeRow{"id": d.facility.String(), "organization_id": org, "name": "Pool"})
+	store.seed("space_facilities", fakeRow{"space_id": d.space.String(), "facility_id": d.facility.String()})
+	store.seed("photos", fakeRow{"id": d.photo.String(), "space_id": d.space.String(), "storage_key": "spaces/" + d.photo.String(), "is_cover": true})
+	store.seed("blackouts", fakeRow{"id": d.blackout.String(), "space_id": d.space.String()})
+	store.seed("external_calendars", fakeRow{"id": d.calendar.String(), "space_id": d.space.String(), "source": "url", "url": "http://127.0.0.1:1/cal.ics"})
+	store.seed("external_calendars", fakeRow{"id": d.upload.String(), "space_id": d.space.String(), "source": "upload"})
+	store.seed("external_blocks", fakeRow{"id": d.externalID.String(), "space_id": d.space.String(), "external_calendar_id": d.calendar.String()})
+	store.seed("bookings", fakeRow{"id": d.booking.String(), "organization_id": org, "space_id": d.space.String(), "user_id": d.user.String(), "status": "pending"})
+}
+
+// TenantIsolationTestSuite menjalankan service asli dengan ctx organisasi A terhadap id milik
+// organisasi B: hasilnya harus not found dan tidak ada baris B yang berubah
+type TenantIsolationTestSuite struct {
+	suite.Suite
+	store *fakeStore
+	db    *gorm.DB
+	a, b  tenantData
+	ctx   context.Context
+
+	spaces        *space.SpaceService
+	photos        *space.PhotoService
+	calendars     *calendar.CalendarService
+	spaceFacility *spacefacility.SpaceFacilityService
+	categories    *category.CategoryService
+	facilities    *facility.FacilityService
+	bookings      *booking.BookingService
+	users         *user.UserService
+}
+
+func TestTenantIsolationSuite(t *testing.T) {
+	suite.Run(t, new(TenantIsolationTestSuite))
+}
+
+func (s *TenantIsolationTestSuite) SetupTest() {
+	s.store = newFakeStore()
+	s.a, s.b = newTenantData(), newTenantData()
+	s.a.seed(s.store)
+	s.b.seed(s.store)
+
+	db, err := s.store.open()
+	s.Require().NoError(err)
+	s.db = db
+	s.ctx = tenant.WithOrganization(context.Background(), s.a.org)
+
+	log := logger.NewLogger()
+	files, err := storage.NewLocalStorage(s.T().TempDir(), "http://localhost/files")
+	s.Require().NoError(err)
+
+	s.spaces = space.NewSpaceService(db, webhook.NewWebhookService(db, log, http.DefaultClient), files)
+	s.photos = space.NewPhotoService(db, log, files, s.spaces, 1<<20)
+	s.calendars = calendar.NewCalendarService(db, log, s.spaces, http.DefaultClient)
+	s.spaceFacility = spacefacility.NewSpaceFacilityService(db, log)
+	s.categories = category.NewCategoryService(db)
+	s.facilities = facility.NewFacilityService(db, log)
+	s.bookings = booking.NewBookingService(db, log, nil, s.spaces, nil, nil, nil, nil)
+	s.users = user.NewUserService(db, log, user.NewLoginGuard(user.NewMemoryAttemptStore(), user.LoginGuardConfig{}), "http://localhost")
+}
+
+// TearDownTest memastikan tidak ada baris organisasi B yang diubah, dihapus, atau dirujuk
+// oleh baris baru selama test
+func (s *TenantIsolationTestSuite) TearDownTest() {
+	s.Empty(s.store.touched(s.b.ids()...), "rows of another organization were modified")
+}
+
+func (s *TenantIsolationTestSuite) TestOwnDataIsVisible() {
+	// memastikan fakeStore memang mengembalikan data sehingga test lain tidak lolos dengan sia-sia
+	found, err := s.spaces.GetByID(s.ctx, s.a.space.String())
+	s.Require().NoError(err)
+	s.Equal(s.a.space, found.ID)
+
+	photos, err := s.photos.GetAll(s.ctx, s.a.space.String())
+	s.Require().NoError(err)
+	s.Len(photos, 1)
+
+	blackouts, err := s.spaces.GetBlackouts(s.ctx, s.a.space.String())
+	s.Require().NoError(err)
+	s.Len(blackouts, 1)
+
+	calendars, err := s.calendars.GetExternalCalendars(s.ctx, s.a.space.String())
+	s.Require().NoError(err)
+	s.Len(calendars, 2)
+
+	_, err = s.categories.GetByID(s.ctx, s.a.category.String())
+	s.NoError(err)
+	_, err = s.facilities.GetByID(s.ctx, s.a.facility.String())
+	s.NoError(err)
+	_, err = s.bookings.GetByID(s.ctx, s.a.booking.String())
+	s.NoError(err)
+	_, err = s.users.GetUserByID(s.ctx, s.a.user.String())
+	s.NoError(err)
+}
+
+func (s *TenantIsolationTestSuite) TestUnscopedQueryIsDetected() {
+	// tanpa tenant di ctx query tidak dibatasi dan data organisasi B terlihat
+	found, err := s.spaces.GetByID(context.Background(), s.b.space.String())
+	s.Require().NoError(err)
+	s.Equal(s.b.space, found.ID)
+}
+
+func (s *TenantIsolationTestSuite) TestSpaces() {
+	id := s.b.space.String()
+
+	_, err := s.spaces.GetByID(s.ctx, id)
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	_, err = s.spaces.Update(s.ctx, id, spaceModel.CreateSpaceInput{Name: "Taken", CategoryID: s.a.category})
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	s.ErrorIs(s.spaces.Delete(s.ctx, id), space.ErrSpaceNotFound)
+	_, err = s.spaces.GetPublicByID(s.ctx, id)
+	s.Error(err)
+}
+
+func (s *TenantIsolationTestSuite) TestPhotos() {
+	id := s.b.space.String()
+
+	_, err := s.photos.GetAll(s.ctx, id)
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	_, err = s.photos.Reorder(s.ctx, id, []uuid.UUID{s.b.photo})
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	_, err = s.photos.SetCover(s.ctx, id, s.b.photo.String())
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	s.ErrorIs(s.photos.Delete(s.ctx, id, s.b.photo.String()), space.ErrSpaceNotFound)
+	// foto B juga tidak bisa dihapus lewat space milik A
+	s.ErrorIs(s.photos.Delete(s.ctx, s.a.space.String(), s.b.photo.String()), space.ErrPhotoNotFound)
+}
+
+func (s *TenantIsolationTestSuite) TestBlackouts() {
+	id := s.b.space.String()
+
+	_, err := s.spaces.GetBlackouts(s.ctx, id)
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	_, err = s.spaces.CreateBlackout(s.ctx, id, spaceModel.CreateBlackoutInput{})
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	s.ErrorIs(s.spaces.DeleteBlackout(s.ctx, id, s.b.blackout.String()), space.ErrSpaceNotFound)
+	s.Error(s.spaces.DeleteBlackout(s.ctx, s.a.space.String(), s.b.blackout.String()))
+}
+
+func (s *TenantIsolationTestSuite) TestExternalCalendars() {
+	id := s.b.space.String()
+
+	_, err := s.calendars.GetExternalCalendars(s.ctx, id)
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	_, err = s.calendars.GetExternalBlocks(s.ctx, id)
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	_, err = s.calendars.SyncExternalCalendar(s.ctx, id, s.b.calendar.String())
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+	s.ErrorIs(s.calendars.DeleteExternalCalendar(s.ctx, id, s.b.calendar.String()), space.ErrSpaceNotFound)
+	_, err = s.calendars.UploadExternalCalendar(s.ctx, id, s.b.upload.String(), "Channel", strings.NewReader(""))
+	s.ErrorIs(err, space.ErrSpaceNotFound)
+
+	// kalender B tidak bisa diakses lewat space milik A
+	s.ErrorIs(s.calendars.DeleteExternalCalendar(s.ctx, s.a.space.String(), s.b.calendar.String()), calendar.ErrExternalCalendarNotFound)
+	_, err = s.calendars.UploadExternalCalendar(s.ctx, s.a.space.String(), s.b.upload.String(), "Channel",
+		strings.NewReader("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n"))
+	s.ErrorIs(err, calendar.ErrExternalCalendarNotFound)
+}
+
+func (s *TenantIsolationTestSuite) TestSpaceFacilities() {
+	id := s.b.space.String()
+
+	_, err := s.spaceFacility.GetBySpace(s.ctx, id)
+	s.ErrorIs(err, spacefacility.ErrSpaceNotFound)
+	_, err = s.spaceFacility.Replace(s.ctx, id, nil)
+	s.ErrorIs(err, spacefacility.ErrSpaceNotFound)
+	s.ErrorIs(s.spaceFacility.Remove(s.ctx, id, s.b.facility.String()), spacefacility.ErrSpaceNotFound)
+	// fasilitas B tidak bisa dipasang ke space milik A
+	_, err = s.spaceFacility.Replace(s.ctx, s.a.space.String(), []uuid.UUID{s.b.facility})
+	s.ErrorIs(err, spacefacility.ErrFacilityNotFound)
+}
+
+func (s *TenantIsolationTestSuite) TestCategories() {
+	id := s.b.category.String()
+
+	_, err := s.categories.GetByID(s.ctx, id)
+	s.ErrorIs(err, category.ErrCategoryNotFound)
+	_, err = s.categories.Update(s.ctx, id, categoryModel.CreateCategoryInput{Name: "Taken"})
+	s.ErrorIs(err, category.ErrCategoryNotFound)
+	s.ErrorIs(s.categories.Delete(s.ctx, id, nil), category.ErrCategoryNotFound)
+}
+
+func (s *TenantIsolationTestSuite) TestFacilities() {
+	id := s.b.facility.String()
+
+	_, err := s.facilities.GetByID(s.ctx, id)
+	s.ErrorIs(err, facility.ErrFacilityNotFound)
+	_, err = s.facilities.Update(s.ctx, id, facilityModel.CreateFacilityInput{Name: "Taken"})
+	s.ErrorIs(err, facility.ErrFacilityNotFound)
+	s.ErrorIs(s.facilities.Delete(s.ctx, id, nil), facility.ErrFacilityNotFound)
+}
+
+func (s *TenantIsolationTestSuite) TestBookings() {
+	id := s.b.booking.String()
+
+	_, err := s.bookings.GetByID(s.ctx, id)
+	s.Error(err)
+	_, err = s.bookings.MarkPaid(s.ctx, id)
+	s.Error(err)
+	s.Error(s.bookings.Cancel(s.ctx, id, s.b.user))
+	_, err = s.bookings.CancelForOwner(s.ctx, id, s.b.user)
+	s.Error(err)
+}
+
+func (s *TenantIsolationTestSuite) TestUsers() {
+	id := s.b.user.String()
+
+	_, err := s.users.GetUserByID(s.ctx, id)
+	s.True(errors.Is(err, gorm.ErrRecordNotFound))
+	_, err = s.users.UpdateUserRole(s.ctx, id, constants.RoleAdmin)
+	s.True(errors.Is(err, gorm.ErrRecordNotFound))
+	s.True(errors.Is(s.users.Unlock(s.ctx, id), gorm.ErrRecordNotFound))
+	s.NoError(s.users.DeleteAccount(s.ctx, id))
+}
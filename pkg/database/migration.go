@@ -1,9 +1,44 @@
 package database
 
 import (
+	apikeyModel "booking/internal/apikey/model"
+	bookingModel "booking/internal/booking/model"
+	categoryModel "booking/internal/category/model"
+	facilityModel "booking/internal/facility/model"
+	notificationModel "booking/internal/notification/model"
+	organizationModel "booking/internal/organization/model"
+	roleModel "booking/internal/role/model"
+	sessionModel "booking/internal/session/model"
+	spaceModel "booking/internal/space/model"
+	userModel "booking/internal/user/model"
+	webhookModel "booking/internal/webhook/model"
+	"booking/pkg/tenant"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// tenantModels adalah model yang datanya dipisah per organisasi (punya kolom organization_id)
+var tenantModels = []interface{}{
+	&userModel.User{}, &categoryModel.Category{},
+	&spaceModel.Space{}, &facilityModel.Facility{},
+	&bookingModel.Booking{}, &notificationModel.OutboxMessage{},
+	&webhookModel.Endpoint{}, &webhookModel.Delivery{},
+	&roleModel.Role{}, &sessionModel.Session{},
+	&sessionModel.RefreshToken{}, &userModel.UserToken{},
+	&userModel.RecoveryCode{}, &userModel.UserIdentity{},
+	&apikeyModel.APIKey{}, &userModel.AdminAuditLog{},
+}
+
+// legacyUniqueIndexes adalah unique index global yang diganti unique index per organisasi
+var legacyUniqueIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{&userModel.User{}, "uni_users_email"},
+	{&facilityModel.Facility{}, "uni_facilities_name"},
+}
+
 func Migrate(db *gorm.DB) error {
 	// Ubah tipe kolom ID di tabel users
 	if err := db.Exec("ALTER TABLE users MODIFY COLUMN id CHAR(36)").Error; err != nil {
@@ -28,3 +63,103 @@ func Migrate(db *gorm.DB) error {
 
 	return nil
 }
+
+// migrateTenants membuat organisasi default lalu memindahkan data lama (tanpa organisasi) ke sana
+func migrateTenants(db *gorm.DB) error {
+	for _, idx := range legacyUniqueIndexes {
+		if db.Migrator().HasIndex(idx.model, idx.name) {
+			if err := db.Migrator().DropIndex(idx.model, idx.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	org := organizationModel.Organization{
+		ID:       uuid.New(),
+		Name:     "Default",
+		Slug:     organizationModel.DefaultSlug,
+		IsActive: true,
+	}
+	if err := db.Where("slug = ?", org.Slug).FirstOrCreate(&org).Error; err != nil {
+		return err
+	}
+
+	for _, m := range tenantModels {
+		err := db.Model(m).
+			Where(tenant.ColumnName+" = ? OR "+tenant.ColumnName+" IS NULL", "").
+			UpdateColumn(tenant.ColumnName, org.ID).Error
+		if err != nil {
+			return err
+		}
+	}
+	return seedSystemRoles(db)
+}
+
+// seedSystemRoles membuat role bawaan yang belum ada di setiap organisasi
+func seedSystemRoles(db *gorm.DB) error {
+	var orgs []organizationModel.Organization
+	if err := db.Find(&orgs).Error; err != nil {
+		return err
+	}
+
+	for _, org := range orgs {
+		for _, role := range roleModel.SystemRoles() {
+			role.OrganizationID = org.ID
+			err := db.Where("organization_id = ? AND name = ?", org.ID, role.Name).
+				FirstOrCreate(&role).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeCategorySlugs membuat ulang slug kategori lama dengan GenerateSlug dan menambahkan
+// akhiran -2, -3, ... untuk slug yang bentrok dalam organisasi yang sama
+func normalizeCategorySlugs(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("categories") || migrator.HasIndex(&categoryModel.Category{}, "idx_categories_org_slug") {
+		return nil
+	}
+
+	columns := "id, name, slug"
+	if migrator.HasColumn("categories", tenant.ColumnName) {
+		columns += ", " + tenant.ColumnName
+	}
+
+	var rows []struct {
+		ID             string
+		OrganizationID string
+		Name           string
+		Slug           string
+	}
+	if err := db.Table("categories").Select(columns).Order("created_at, id").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	taken := make(map[string]map[string]bool)
+	for _, row := range rows {
+		if taken[row.OrganizationID] == nil {
+			taken[row.OrganizationID] = make(map[string]bool)
+		}
+		slug := categoryModel.NextSlug(categoryModel.GenerateSlug(row.Name), taken[row.OrganizationID])
+		taken[row.OrganizationID][slug] = true
+
+		if slug == row.Slug {
+			continue
+		}
+		if err := db.Table("categories").Where("id = ?", row.ID).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyLegacyUsers menandai email user yang terdaftar sebelum ada verifikasi email sebagai
+// terverifikasi, agar mereka tetap bisa membuat booking
+func verifyLegacyUsers(db *gorm.DB) error {
+	return db.Model(&userModel.User{}).
+		Where("email_verified_at IS NULL").
+		UpdateColumn("email_verified_at", gorm.Expr("created_at")).Error
+}
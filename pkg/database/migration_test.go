@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	categoryModel "booking/internal/category/model"
+	facilityModel "booking/internal/facility/model"
+	spaceModel "booking/internal/space/model"
+	"booking/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type TenantModelsTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+}
+
+func TestTenantModelsSuite(t *testing.T) {
+	suite.Run(t, new(TenantModelsTestSuite))
+}
+
+func (s *TenantModelsTestSuite) SetupTest() {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	s.Require().NoError(err)
+	s.Require().NoError(db.Use(tenant.Plugin{}))
+	s.db = db
+}
+
+// Setiap model tenant harus otomatis dibatasi ke organisasi aktif untuk query, update dan delete
+func (s *TenantModelsTestSuite) TestTenantModelsAreScoped() {
+	org := uuid.New()
+	ctx := tenant.WithOrganization(context.Background(), org)
+
+	for _, m := range tenantModels {
+		stmt := &gorm.Statement{DB: s.db}
+		s.Require().NoError(stmt.Parse(m))
+		s.Require().NotNil(stmt.Schema.LookUpField(tenant.FieldName), stmt.Schema.Name)
+
+		column := "`" + stmt.Schema.Table + "`.`" + tenant.ColumnName + "` = ?"
+
+		query := s.db.WithContext(ctx).Model(m).Where("id = ?", uuid.New()).Find(m).Statement
+		s.Contains(query.SQL.String(), column, stmt.Schema.Name)
+		s.Contains(query.Vars, org, stmt.Schema.Name)
+
+		update := s.db.WithContext(ctx).Model(m).Where("id = ?", uuid.New()).UpdateColumn("updated_at", nil).Statement
+		s.Contains(update.SQL.String(), column, stmt.Schema.Name)
+
+		del := s.db.WithContext(ctx).Where("id = ?", uuid.New()).Delete(m).Statement
+		s.Contains(del.SQL.String(), column, stmt.Schema.Name)
+	}
+}
+
+// Kategori, fasilitas dan space memakai soft delete; query restore (Unscoped) tetap
+// dibatasi ke organisasi aktif
+func (s *TenantModelsTestSuite) TestCatalogModelsSoftDelete() {
+	org := uuid.New()
+	ctx := tenant.WithOrganization(context.Background(), org)
+
+	for _, m := range []interface{}{&categoryModel.Category{}, &facilityModel.Facility{}, &spaceModel.Space{}} {
+		stmt := &gorm.Statement{DB: s.db}
+		s.Require().NoError(stmt.Parse(m))
+		deletedAt := "`" + stmt.Schema.Table + "`.`deleted_at` IS NULL"
+
+		query := s.db.WithContext(ctx).Where("id = ?", uuid.New()).Find(m).Statement
+		s.Contains(query.SQL.String(), deletedAt, stmt.Schema.Name)
+
+		del := s.db.WithContext(ctx).Where("id = ?", uuid.New()).Delete(m).Statement
+		s.Contains(del.SQL.String(), "UPDATE", stmt.Schema.Name)
+		s.Contains(del.SQL.String(), "SET `deleted_at`", stmt.Schema.Name)
+
+		restore := s.db.WithContext(ctx).Unscoped().Model(m).Where("id = ?", uuid.New()).Update("deleted_at", nil).Statement
+		s.NotContains(restore.SQL.String(), deletedAt, stmt.Schema.Name)
+		s.Contains(restore.SQL.String(), tenant.ColumnName, stmt.Schema.Name)
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"booking/internal/role"
+	roleModel "booking/internal/role/model"
+	"booking/internal/session"
+	"booking/internal/user"
+	userModel "booking/internal/user/model"
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+	"booking/pkg/tenant"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryRevocationStore menyimpan daftar pencabutan di memori sebagai pengganti Redis
+type memoryRevocationStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func (m *memoryRevocationStore) AddRevocations(ctx context.Context, members []string, at time.Time, retention time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, member := range members {
+		m.entries[member] = at
+	}
+	return nil
+}
+
+func (m *memoryRevocationStore) RevocationsSince(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := map[string]time.Time{}
+	for member, at := range m.entries {
+		result[member] = at
+	}
+	return result, nil
+}
+
+// stubPasswordService tidak mengirim email reset
+type stubPasswordService struct {
+	user.PasswordServiceInterface
+}
+
+func (stubPasswordService) SendReset(ctx context.Context, user *userModel.User) error {
+	return nil
+}
+
+// AccessTokenRevocationTestSuite memastikan tindakan yang mengubah hak akses user membuat
+// access token yang sudah diterbitkan ditolak, bukan hanya mencabut session
+type AccessTokenRevocationTestSuite struct {
+	suite.Suite
+	store       *fakeStore
+	ctx         context.Context
+	org         uuid.UUID
+	admin       uuid.UUID
+	target      uuid.UUID
+	revocations *session.RevocationList
+	sessions    *session.SessionService
+	admins      *user.AdminService
+	roles       *role.RoleService
+	editorRole  uuid.UUID
+	otherUser   uuid.UUID
+}
+
+func TestAccessTokenRevocationSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenRevocationTestSuite))
+}
+
+func (s *AccessTokenRevocationTestSuite) SetupTest() {
+	s.store = newFakeStore()
+	s.org, s.admin, s.target, s.otherUser, s.editorRole = uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	s.ctx = tenant.WithOrganization(context.Background(), s.org)
+	s.store.seed("users", fakeRow{"id": s.target.String(), "organization_id": s.org.String(),
+		"email": "guest@example.com", "role": "editor"})
+	s.store.seed("users", fakeRow{"id": s.otherUser.String(), "organization_id": s.org.String(),
+		"email": "other@example.com", "role": "user"})
+	s.store.seed("roles", fakeRow{"id": s.editorRole.String(), "organization_id": s.org.String(),
+		"name": "editor", "permissions": "space:write", "is_system": false})
+
+	db, err := s.store.open()
+	s.Require().NoError(err)
+
+	log := logger.NewLogger()
+	s.revocations = session.NewRevocationList(&memoryRevocationStore{entries: map[string]time.Time{}}, log, time.Minute, time.Second)
+	s.Require().NoError(s.revocations.Sync(context.Background()))
+	s.sessions = session.NewSessionService(db, log, jwt.NewHMACKeySet("test-secret", ""), s.revocations, time.Minute, time.Hour)
+	s.admins = user.NewAdminService(db, log, s.sessions, stubPasswordService{})
+	s.roles = role.NewRoleService(db, s.sessions)
+}
+
+// claims adalah access token user target yang diterbitkan sebelum tindakan admin
+func (s *AccessTokenRevocationTestSuite) claims(actor *jwt.Actor) *jwt.Claims {
+	return s.claimsFor(s.target, actor)
+}
+
+func (s *AccessTokenRevocationTestSuite) claimsFor(userID uuid.UUID, actor *jwt.Actor) *jwt.Claims {
+	return &jwt.Claims{
+		UserID:    userID.String(),
+		SessionID: uuid.NewString(),
+		Actor:     actor,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:       uuid.NewString(),
+			IssuedAt: gojwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}
+}
+
+func (s *AccessTokenRevocationTestSuite) TestForcePasswordResetRejectsIssuedTokens() {
+	token := s.claims(nil)
+	impersonation := s.claims(&jwt.Actor{Subject: s.admin.String()})
+	s.Require().NoError(s.revocations.Check(token))
+	s.Require().NoError(s.revocations.Check(impersonation))
+
+	actor := &userModel.Principal{UserID: s.admin, OrganizationID: s.org, Role: "superadmin"}
+	s.Require().NoError(s.admins.ForcePasswordReset(s.ctx, actor, s.target.String(), "compromised", "127.0.0.1"))
+
+	s.ErrorIs(s.revocations.Check(token), session.ErrSessionRevoked)
+	s.ErrorIs(s.revocations.Check(impersonation), session.ErrSessionRevoked)
+}
+
+func (s *AccessTokenRevocationTestSuite) TestRoleRenameRejectsTokensWithOldName() {
+	editor := s.claims(nil)
+	other := s.claimsFor(s.otherUser, nil)
+
+	renamed, err := s.roles.Update(s.ctx, s.editorRole.String(), roleModel.RoleInput{
+		Name:        "content-editor",
+		Permissions: []string{"space:write"},
+	})
+	s.Require().NoError(err)
+	s.Equal("content-editor", renamed.Name)
+
+	// claim role token editor masih "editor" yang tidak lagi punya permission
+	s.ErrorIs(s.revocations.Check(editor), session.ErrSessionRevoked)
+	s.NoError(s.revocations.Check(other))
+
+	for _, row := range s.store.rows("users") {
+		if fakeString(row["id"]) == s.target.String() {
+			s.Equal("content-editor", fakeString(row["role"]))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress dikembalikan saat koneksi mengarah ke alamat internal
+var ErrForbiddenAddress = errors.New("destination address is not allowed")
+
+// blockedPrefixes melengkapi pengecekan netip untuk rentang yang tidak boleh dihubungi
+// dari sisi server (this-network dan carrier-grade NAT)
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// NewPublic membuat http.Client untuk URL yang diisi pengguna (webhook, kalender eksternal).
+// Alamat dicek di Dialer.Control setelah DNS di-resolve, sehingga DNS rebinding dan redirect
+// ke alamat internal ikut tertolak. Proxy dari environment tidak dipakai karena koneksi ke
+// proxy internal akan ikut tertolak.
+func NewPublic(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: control,
+	}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return ErrForbiddenAddress
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// IsPublic mengecek apakah alamat boleh dihubungi: bukan loopback, private (RFC 1918/ULA),
+// link-local (termasuk metadata cloud 169.254.169.254), multicast atau unspecified
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
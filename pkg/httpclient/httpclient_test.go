@@ -0,0 +1,43 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPClientTestSuite struct {
+	suite.Suite
+}
+
+func TestHTTPClientSuite(t *testing.T) {
+	suite.Run(t, new(HTTPClientTestSuite))
+}
+
+func (s *HTTPClientTestSuite) TestIsPublic() {
+	blocked := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"0.0.0.0", "100.64.0.1", "224.0.0.1", "::1", "fd00::1", "fe80::1", "::ffff:127.0.0.1",
+	}
+	for _, ip := range blocked {
+		s.False(IsPublic(netip.MustParseAddr(ip)), ip)
+	}
+
+	for _, ip := range []string{"93.184.216.34", "2606:4700::1111"} {
+		s.True(IsPublic(netip.MustParseAddr(ip)), ip)
+	}
+}
+
+func (s *HTTPClientTestSuite) TestPublicClientRejectsLoopback() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := NewPublic(time.Second).Get(server.URL)
+	s.ErrorIs(err, ErrForbiddenAddress)
+}
@@ -0,0 +1,127 @@
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event adalah satu VEVENT dalam kalender
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Status       string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	LastModified time.Time
+}
+
+// Calendar adalah satu VCALENDAR (RFC 5545)
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Encode menulis kalender dalam format iCalendar dengan baris CRLF dan line folding
+func (c *Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	now := time.Now().UTC()
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:"+escapeText(c.ProdID))
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(bw, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escapeText(e.UID))
+		writeLine(bw, "DTSTAMP:"+now.Format(dateTimeFormat))
+		if e.AllDay {
+			writeLine(bw, "DTSTART;VALUE=DATE:"+e.Start.Format(dateFormat))
+			writeLine(bw, "DTEND;VALUE=DATE:"+e.End.Format(dateFormat))
+		} else {
+			writeLine(bw, "DTSTART:"+e.Start.UTC().Format(dateTimeFormat))
+			writeLine(bw, "DTEND:"+e.End.UTC().Format(dateTimeFormat))
+		}
+		writeLine(bw, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(bw, "LOCATION:"+escapeText(e.Location))
+		}
+		if e.Status != "" {
+			writeLine(bw, "STATUS:"+e.Status)
+		}
+		if !e.LastModified.IsZero() {
+			writeLine(bw, "LAST-MODIFIED:"+e.LastModified.UTC().Format(dateTimeFormat))
+		}
+		writeLine(bw, "TRANSP:OPAQUE")
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// Bytes mengembalikan hasil Encode sebagai byte slice
+func (c *Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = c.Encode(&buf)
+	return buf.Bytes()
+}
+
+// writeLine menulis satu content line, dilipat setiap 75 oktet sesuai RFC 5545 3.1
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		// Jangan memotong di tengah karakter UTF-8
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Baris lanjutan diawali spasi yang ikut dihitung
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,143 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ICSTestSuite struct {
+	suite.Suite
+}
+
+func TestICSSuite(t *testing.T) {
+	suite.Run(t, new(ICSTestSuite))
+}
+
+func (s *ICSTestSuite) TestEncode() {
+	cal := &Calendar{
+		ProdID: "-//test//EN",
+		Name:   "Villa, Bali; Indonesia",
+		Events: []Event{
+			{
+				UID:     "booking-1@test",
+				Summary: "Reserved",
+				Status:  StatusConfirmed,
+				Start:   time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 11, 3, 5, 0, 0, 0, time.UTC),
+			},
+			{
+				UID:     "blackout-1@test",
+				Summary: "Blocked",
+				Start:   time.Date(2026, 12, 24, 14, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 12, 26, 12, 0, 0, 0, time.UTC),
+				AllDay:  true,
+			},
+		},
+	}
+
+	out := string(cal.Bytes())
+
+	s.True(strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	s.True(strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	s.Contains(out, `X-WR-CALNAME:Villa\, Bali\; Indonesia`+"\r\n")
+	s.Contains(out, "DTSTART:20261101T070000Z\r\n")
+	s.Contains(out, "DTEND:20261103T050000Z\r\n")
+	s.Contains(out, "STATUS:CONFIRMED\r\n")
+	s.Contains(out, "DTSTART;VALUE=DATE:20261224\r\n")
+	s.Contains(out, "DTEND;VALUE=DATE:20261226\r\n")
+	s.Equal(2, strings.Count(out, "BEGIN:VEVENT"))
+}
+
+func (s *ICSTestSuite) TestLineFolding() {
+	cal := &Calendar{
+		ProdID: "-//test//EN",
+		Events: []Event{{
+			UID:         "long@test",
+			Summary:     "Long",
+			Description: strings.Repeat("kamar ", 40),
+			Start:       time.Now(),
+			End:         time.Now().Add(time.Hour),
+		}},
+	}
+
+	for _, line := range strings.Split(string(cal.Bytes()), "\r\n") {
+		s.LessOrEqual(len(line), maxLineOctets)
+	}
+}
+
+func (s *ICSTestSuite) TestParse() {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//Airbnb Inc//Hosting Calendar//EN\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:abc-1@airbnb.com\r\n" +
+		"DTSTART;VALUE=DATE:20261224\r\n" +
+		"DTEND;VALUE=DATE:20261226\r\n" +
+		"SUMMARY:Reserved\\, guest\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"SUMMARY:ignored\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:abc-2@airbnb.com\r\n" +
+		"DTSTART:20261101T070000Z\r\n" +
+		"DURATION:P1DT2H\r\n" +
+		"DESCRIPTION:folded \r\n" +
+		" line\r\n" +
+		"STATUS:cancelled\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:abc-3@airbnb.com\r\n" +
+		"DTSTART;VALUE=DATE:20261231\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := Parse(strings.NewReader(data))
+	s.Require().NoError(err)
+	s.Require().Len(cal.Events, 3)
+
+	first := cal.Events[0]
+	s.Equal("abc-1@airbnb.com", first.UID)
+	s.Equal("Reserved, guest", first.Summary)
+	s.True(first.AllDay)
+	s.Equal(time.Date(2026, 12, 24, 0, 0, 0, 0, time.Local), first.Start)
+	s.Equal(time.Date(2026, 12, 26, 0, 0, 0, 0, time.Local), first.End)
+
+	second := cal.Events[1]
+	s.False(second.AllDay)
+	s.Equal(time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC), second.End)
+	s.Equal("folded line", second.Description)
+	s.Equal(StatusCancelled, second.Status)
+
+	third := cal.Events[2]
+	s.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local), third.End)
+}
+
+func (s *ICSTestSuite) TestParseRoundTrip() {
+	cal := &Calendar{
+		ProdID: "-//test//EN",
+		Events: []Event{{
+			UID:     "booking-1@test",
+			Summary: "Stay; with, escapes",
+			Start:   time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC),
+			End:     time.Date(2026, 11, 3, 5, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	parsed, err := Parse(strings.NewReader(string(cal.Bytes())))
+	s.Require().NoError(err)
+	s.Require().Len(parsed.Events, 1)
+	s.Equal(cal.Events[0].Summary, parsed.Events[0].Summary)
+	s.True(cal.Events[0].Start.Equal(parsed.Events[0].Start))
+	s.True(cal.Events[0].End.Equal(parsed.Events[0].End))
+}
+
+func (s *ICSTestSuite) TestParseInvalidEvent() {
+	data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:no uid\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	_, err := Parse(strings.NewReader(data))
+	s.ErrorIs(err, ErrInvalidEvent)
+}
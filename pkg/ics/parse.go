@@ -0,0 +1,281 @@
+package ics
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCalendar = errors.New("invalid iCalendar data")
+	ErrInvalidEvent    = errors.New("invalid VEVENT")
+)
+
+// Parse membaca data iCalendar dan mengembalikan VEVENT di dalamnya.
+// Hanya properti yang dipakai aplikasi yang di-parse (UID, DTSTART, DTEND, DURATION,
+// SUMMARY, DESCRIPTION, LOCATION, STATUS, LAST-MODIFIED).
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{}
+	var (
+		inCalendar bool
+		event      *Event
+		duration   string
+		// depth komponen lain di dalam VEVENT (misalnya VALARM) yang diabaikan
+		nested int
+	)
+
+	for _, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			inCalendar = true
+			continue
+		case name == "END" && value == "VCALENDAR":
+			inCalendar = false
+			continue
+		case !inCalendar:
+			continue
+		case name == "BEGIN" && value == "VEVENT":
+			event = &Event{}
+			duration = ""
+			continue
+		case event == nil:
+			if name == "PRODID" {
+				cal.ProdID = value
+			} else if name == "X-WR-CALNAME" {
+				cal.Name = unescapeText(value)
+			}
+			continue
+		case name == "BEGIN":
+			nested++
+			continue
+		case name == "END" && value != "VEVENT":
+			nested--
+			continue
+		case nested > 0:
+			continue
+		case name == "END" && value == "VEVENT":
+			if err := finishEvent(event, duration); err != nil {
+				return nil, err
+			}
+			cal.Events = append(cal.Events, *event)
+			event = nil
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.UID = value
+		case "SUMMARY":
+			event.Summary = unescapeText(value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(value)
+		case "LOCATION":
+			event.Location = unescapeText(value)
+		case "STATUS":
+			event.Status = strings.ToUpper(value)
+		case "DTSTART":
+			t, allDay, err := parseTime(value, params)
+			if err != nil {
+				return nil, err
+			}
+			event.Start, event.AllDay = t, allDay
+		case "DTEND":
+			t, _, err := parseTime(value, params)
+			if err != nil {
+				return nil, err
+			}
+			event.End = t
+		case "DURATION":
+			duration = value
+		case "LAST-MODIFIED":
+			if t, _, err := parseTime(value, params); err == nil {
+				event.LastModified = t
+			}
+		}
+	}
+
+	if event != nil {
+		return nil, ErrInvalidCalendar
+	}
+	return cal, nil
+}
+
+func finishEvent(event *Event, duration string) error {
+	if event.UID == "" || event.Start.IsZero() {
+		return ErrInvalidEvent
+	}
+
+	if event.End.IsZero() {
+		switch {
+		case duration != "":
+			d, err := parseDuration(duration)
+			if err != nil {
+				return err
+			}
+			event.End = event.Start.Add(d)
+		case event.AllDay:
+			// RFC 5545: tanpa DTEND, event DATE berlangsung satu hari
+			event.End = event.Start.AddDate(0, 0, 1)
+		default:
+			event.End = event.Start
+		}
+	}
+
+	if event.End.Before(event.Start) {
+		return ErrInvalidEvent
+	}
+	return nil
+}
+
+// unfold menggabungkan baris lanjutan (diawali spasi atau tab) sesuai RFC 5545 3.1
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitLine memecah "NAME;PARAM=VAL:value" menjadi nama, parameter dan nilai
+func splitLine(line string) (string, map[string]string, string, bool) {
+	inQuote := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, time.Local)
+		if err != nil {
+			return time.Time{}, false, ErrInvalidEvent
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, value)
+		if err != nil {
+			return time.Time{}, false, ErrInvalidEvent
+		}
+		return t, false, nil
+	}
+
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, ErrInvalidEvent
+	}
+	return t, false, nil
+}
+
+// parseDuration mem-parsing DURATION RFC 5545, misalnya P1D, P2W, PT3H30M
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	if s == value || s == "" || strings.HasPrefix(value, "-") {
+		return 0, ErrInvalidEvent
+	}
+
+	var (
+		total  time.Duration
+		number string
+		inTime bool
+	)
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, ErrInvalidEvent
+		}
+		number = ""
+
+		switch {
+		case r == 'W' && !inTime:
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			total += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, ErrInvalidEvent
+		}
+	}
+	if number != "" {
+		return 0, ErrInvalidEvent
+	}
+	return total, nil
+}
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
@@ -0,0 +1,83 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	_ "image/png" // registrasi decoder PNG
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registrasi decoder WebP
+)
+
+// AllowedContentTypes adalah tipe gambar yang boleh diupload
+var AllowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// MaxPixels membatasi dimensi gambar yang didecode; file kecil bisa mendeklarasikan dimensi
+// sangat besar dan menghabiskan memori saat didecode penuh
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type, use JPEG, PNG or WebP")
+	ErrImageTooLarge    = errors.New("image dimensions exceed 40 megapixels")
+)
+
+// DetectContentType menentukan tipe gambar dari isi file (bukan dari header upload)
+func DetectContentType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if !AllowedContentTypes[contentType] {
+		return "", ErrUnsupportedImage
+	}
+	return contentType, nil
+}
+
+// Decode membaca header gambar lebih dulu dan menolak gambar di atas MaxPixels sebelum
+// pixel-nya dialokasikan
+func Decode(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	return img, nil
+}
+
+// Resize memperkecil gambar agar lebar maksimal maxWidth dengan rasio tetap; gambar
+// yang sudah lebih kecil tidak diperbesar
+func Resize(img image.Image, maxWidth int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= maxWidth {
+		return img
+	}
+	height := bounds.Dy() * maxWidth / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, maxWidth, height))
+	// Latar putih agar area transparan PNG tidak menjadi hitam di JPEG
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// EncodeJPEG meng-encode gambar sebagai JPEG untuk thumbnail
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ImagingTestSuite struct {
+	suite.Suite
+}
+
+func TestImagingSuite(t *testing.T) {
+	suite.Run(t, new(ImagingTestSuite))
+}
+
+func (s *ImagingTestSuite) pngBytes(w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	s.Require().NoError(png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func (s *ImagingTestSuite) TestDetectContentType() {
+	contentType, err := DetectContentType(s.pngBytes(4, 4))
+	s.NoError(err)
+	s.Equal("image/png", contentType)
+
+	_, err = DetectContentType([]byte("<html>not an image</html>"))
+	s.ErrorIs(err, ErrUnsupportedImage)
+}
+
+func (s *ImagingTestSuite) TestResizeKeepsAspectRatio() {
+	img, err := Decode(s.pngBytes(2000, 1000))
+	s.Require().NoError(err)
+
+	thumb := Resize(img, 480)
+	s.Equal(480, thumb.Bounds().Dx())
+	s.Equal(240, thumb.Bounds().Dy())
+
+	data, err := EncodeJPEG(thumb)
+	s.Require().NoError(err)
+	contentType, err := DetectContentType(data)
+	s.NoError(err)
+	s.Equal("image/jpeg", contentType)
+}
+
+func (s *ImagingTestSuite) TestResizeDoesNotUpscale() {
+	img, err := Decode(s.pngBytes(100, 50))
+	s.Require().NoError(err)
+	s.Equal(100, Resize(img, 480).Bounds().Dx())
+}
+
+func (s *ImagingTestSuite) TestDecodeRejectsOversizedDimensions() {
+	// PNG 1x1 yang header IHDR-nya diubah menjadi 50000x50000; file tetap beberapa byte
+	data := s.pngBytes(1, 1)
+	binary.BigEndian.PutUint32(data[16:20], 50000)
+	binary.BigEndian.PutUint32(data[20:24], 50000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	s.Less(len(data), 100)
+
+	_, err := Decode(data)
+	s.ErrorIs(err, ErrImageTooLarge)
+}
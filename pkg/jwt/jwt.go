@@ -5,21 +5,79 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Claims adalah claims access token. Organisasi, role dan status 2FA ikut disimpan agar
+// request bisa diautentikasi tanpa memuat user dari database; claims ini diperbarui saat
+// token di-refresh. Actor terisi pada token impersonasi dan berisi admin yang bertindak
+// sebagai user tersebut.
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID         string `json:"user_id"`
+	SessionID      string `json:"sid"`
+	OrganizationID string `json:"org"`
+	Role           string `json:"role"`
+	TwoFactor      bool   `json:"tfa,omitempty"`
+	Actor          *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken menghasilkan JWT token untuk user
-func GenerateToken(userID string, secretKey string) (string, error) {
-	claims := Claims{
-		UserID: userID,
+// Actor adalah claim "act" (RFC 8693) yang menandai token impersonasi
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// GenerateToken menghasilkan access token berumur pendek untuk session user; ID token (jti),
+// issuer dan masa berlaku diisi di sini
+func GenerateToken(claims Claims, keys *KeySet, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    keys.issuer,
+		Subject:   claims.UserID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	return keys.sign(claims)
+}
+
+// ValidateToken memvalidasi JWT token dan mengembalikan claims jika valid
+func ValidateToken(tokenString string, keys *KeySet) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.keyfunc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// token dengan audience adalah token aksi (misalnya verifikasi email), bukan access token
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && len(claims.Audience) == 0 && claims.ID != "" {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token claims")
+}
+
+// ActionClaims adalah claims token sekali pakai yang dikirim lewat email. Audience berisi
+// kegunaan token sehingga token untuk satu aksi tidak bisa dipakai untuk aksi lain.
+// Token aksi hanya diverifikasi service ini sehingga tetap memakai HMAC dengan JWT_SECRET.
+type ActionClaims struct {
+	OrganizationID string `json:"org"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken membuat token aksi untuk user; tokenID merujuk ke baris penanda sekali pakai
+func GenerateActionToken(purpose, userID, tokenID, organizationID, secretKey string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := ActionClaims{
+		OrganizationID: organizationID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token berlaku 24 jam
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -27,22 +85,21 @@ func GenerateToken(userID string, secretKey string) (string, error) {
 	return token.SignedString([]byte(secretKey))
 }
 
-// ValidateToken memvalidasi JWT token dan mengembalikan claims jika valid
-func ValidateToken(tokenString string, secretKey string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+// ValidateActionToken memvalidasi tanda tangan, masa berlaku dan kegunaan token aksi
+func ValidateActionToken(tokenString, purpose, secretKey string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return []byte(secretKey), nil
-	})
-
+	}, jwt.WithAudience(purpose), jwt.WithExpirationRequired())
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid || claims.ID == "" || claims.Subject == "" {
+		return nil, errors.New("invalid token claims")
 	}
-
-	return nil, errors.New("invalid token claims")
+	return claims, nil
 }
@@ -0,0 +1,321 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritma tanda tangan access token yang didukung
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const rsaKeyBits = 2048
+
+var ErrNoSigningKey = errors.New("no active signing key")
+
+// SigningKey adalah satu key penanda tangan access token. Key mulai dipakai menandatangani
+// pada ActivatesAt dan tetap dipakai memverifikasi (serta dipublikasikan di JWKS) sampai
+// ExpiresAt, sehingga token yang ditandatangani key lama tetap valid selama masa tenggang.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	ActivatesAt time.Time
+	ExpiresAt   time.Time
+
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// GenerateSigningKey membuat key baru untuk algoritma asimetris
+func GenerateSigningKey(id, algorithm string, activatesAt, expiresAt time.Time) (*SigningKey, error) {
+	key := &SigningKey{ID: id, Algorithm: algorithm, ActivatesAt: activatesAt, ExpiresAt: expiresAt}
+	switch algorithm {
+	case AlgRS256:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		key.private, key.public = private, &private.PublicKey
+	case AlgEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key.private, key.public = private, public
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	return key, nil
+}
+
+// ParseSigningKey membaca key dari private key PEM (PKCS#8)
+func ParseSigningKey(id, algorithm string, privatePEM []byte, activatesAt, expiresAt time.Time) (*SigningKey, error) {
+	block, _ := pem.Decode(privatePEM)
+	if block == nil {
+		return nil, errors.New("invalid private key pem")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{ID: id, Algorithm: algorithm, ActivatesAt: activatesAt, ExpiresAt: expiresAt, private: parsed}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if algorithm != AlgRS256 {
+			return nil, fmt.Errorf("key %s is RSA but algorithm is %s", id, algorithm)
+		}
+		key.public = &private.PublicKey
+	case ed25519.PrivateKey:
+		if algorithm != AlgEdDSA {
+			return nil, fmt.Errorf("key %s is Ed25519 but algorithm is %s", id, algorithm)
+		}
+		key.public = private.Public()
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+	return key, nil
+}
+
+// MarshalPrivateKey mengubah private key menjadi PEM (PKCS#8)
+func (k *SigningKey) MarshalPrivateKey() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgRS256:
+		return jwt.SigningMethodRS256
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// KeySet menyimpan key penanda tangan access token. Mode HMAC memakai satu secret tanpa
+// kid (perilaku lama); mode asimetris memakai beberapa key yang dirotasi dan dipublikasikan
+// lewat JWKS agar service lain bisa memverifikasi token tanpa mengetahui secret.
+type KeySet struct {
+	algorithm string
+	issuer    string
+	secret    []byte
+	now       func() time.Time
+
+	mu   sync.RWMutex
+	keys map[string]*SigningKey
+}
+
+func NewHMACKeySet(secret, issuer string) *KeySet {
+	if secret == "" {
+		panic("jwt secret is required")
+	}
+	return &KeySet{
+		algorithm: AlgHS256,
+		issuer:    issuer,
+		secret:    []byte(secret),
+		now:       time.Now,
+		keys:      map[string]*SigningKey{},
+	}
+}
+
+// NewKeySet membuat key set asimetris; key diisi lewat Replace
+func NewKeySet(algorithm, issuer string) (*KeySet, error) {
+	if algorithm != AlgRS256 && algorithm != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	return &KeySet{
+		algorithm: algorithm,
+		issuer:    issuer,
+		now:       time.Now,
+		keys:      map[string]*SigningKey{},
+	}, nil
+}
+
+func (s *KeySet) Algorithm() string {
+	return s.algorithm
+}
+
+// Asymmetric mengecek apakah access token ditandatangani key asimetris
+func (s *KeySet) Asymmetric() bool {
+	return s.algorithm != AlgHS256
+}
+
+// Replace mengganti seluruh key asimetris, misalnya setelah rotasi
+func (s *KeySet) Replace(keys []*SigningKey) {
+	byID := make(map[string]*SigningKey, len(keys))
+	for _, k := range keys {
+		byID[k.ID] = k
+	}
+	s.mu.Lock()
+	s.keys = byID
+	s.mu.Unlock()
+}
+
+// signingKey memilih key terbaru dengan algoritma yang dikonfigurasi yang sudah aktif
+func (s *KeySet) signingKey() (*SigningKey, error) {
+	now := s.now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var current *SigningKey
+	for _, k := range s.keys {
+		if k.Algorithm != s.algorithm || now.Before(k.ActivatesAt) || !now.Before(k.ExpiresAt) {
+			continue
+		}
+		if current == nil || k.ActivatesAt.After(current.ActivatesAt) {
+			current = k
+		}
+	}
+	if current == nil {
+		return nil, ErrNoSigningKey
+	}
+	return current, nil
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	if !s.Asymmetric() {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+
+	key, err := s.signingKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// keyfunc mencari key verifikasi dari kid; algoritma token harus sama dengan algoritma key
+// agar public key tidak bisa dipakai sebagai secret HMAC
+func (s *KeySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	if !s.Asymmetric() {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return s.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	s.mu.RUnlock()
+	if !ok || !s.now().Before(key.ExpiresAt) {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.public, nil
+}
+
+// JWK adalah public key dalam format JSON Web Key (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS mengembalikan public key yang belum kedaluwarsa, termasuk key berikutnya yang sudah
+// dipublikasikan sebelum dipakai; kosong pada mode HMAC
+func (s *KeySet) JWKS() JWKS {
+	now := s.now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range s.keys {
+		if !now.Before(k.ExpiresAt) {
+			continue
+		}
+		jwk := JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Algorithm}
+		switch public := k.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// EncryptPrivateKey mengenkripsi private key PEM dengan AES-GCM (key dari SHA-256 secret)
+// sebelum disimpan ke database
+func EncryptPrivateKey(privatePEM []byte, secret string) (string, error) {
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, privatePEM, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptPrivateKey membuka private key yang dienkripsi EncryptPrivateKey
+func DecryptPrivateKey(encrypted, secret string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted private key is too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+func newGCM(secret string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
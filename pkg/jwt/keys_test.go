@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeySetTestSuite struct {
+	suite.Suite
+	now    time.Time
+	claims Claims
+}
+
+func TestKeySetSuite(t *testing.T) {
+	suite.Run(t, new(KeySetTestSuite))
+}
+
+func (s *KeySetTestSuite) SetupTest() {
+	s.now = time.Now()
+	s.claims = Claims{UserID: "user-1", SessionID: "session-1", OrganizationID: "org-1", Role: "admin"}
+}
+
+func (s *KeySetTestSuite) keySet(algorithm string, keys ...*SigningKey) *KeySet {
+	set, err := NewKeySet(algorithm, "https://booking.example.com")
+	s.Require().NoError(err)
+	set.now = func() time.Time { return s.now }
+	set.Replace(keys)
+	return set
+}
+
+func (s *KeySetTestSuite) key(id, algorithm string, activatesAt, expiresAt time.Time) *SigningKey {
+	key, err := GenerateSigningKey(id, algorithm, activatesAt, expiresAt)
+	s.Require().NoError(err)
+	return key
+}
+
+func (s *KeySetTestSuite) TestSignAndValidate() {
+	for _, algorithm := range []string{AlgRS256, AlgEdDSA} {
+		keys := s.keySet(algorithm, s.key("k1", algorithm, s.now.Add(-time.Hour), s.now.Add(time.Hour)))
+
+		raw, err := GenerateToken(s.claims, keys, time.Minute)
+		s.Require().NoError(err, algorithm)
+
+		claims, err := ValidateToken(raw, keys)
+		s.Require().NoError(err, algorithm)
+		s.Equal("user-1", claims.UserID)
+		s.Equal("session-1", claims.SessionID)
+		s.Equal("org-1", claims.OrganizationID)
+		s.Equal("admin", claims.Role)
+		s.NotEmpty(claims.ID)
+		s.Equal("https://booking.example.com", claims.Issuer)
+	}
+}
+
+func (s *KeySetTestSuite) TestRotationKeepsOldKeyDuringGrace() {
+	old := s.key("k1", AlgRS256, s.now.Add(-2*time.Hour), s.now.Add(time.Hour))
+	keys := s.keySet(AlgRS256, old)
+	oldToken, err := GenerateToken(s.claims, keys, time.Hour)
+	s.Require().NoError(err)
+
+	// key baru sudah dipublikasikan tapi belum aktif: masih ditandatangani key lama
+	next := s.key("k2", AlgRS256, s.now.Add(time.Minute), s.now.Add(3*time.Hour))
+	keys.Replace([]*SigningKey{old, next})
+	s.Len(keys.JWKS().Keys, 2)
+	current, err := keys.signingKey()
+	s.Require().NoError(err)
+	s.Equal("k1", current.ID)
+
+	// setelah key baru aktif, token lama tetap valid sampai key lama kedaluwarsa
+	s.now = s.now.Add(2 * time.Minute)
+	current, err = keys.signingKey()
+	s.Require().NoError(err)
+	s.Equal("k2", current.ID)
+	_, err = ValidateToken(oldToken, keys)
+	s.NoError(err)
+
+	s.now = s.now.Add(time.Hour)
+	_, err = ValidateToken(oldToken, keys)
+	s.Error(err)
+	s.Len(keys.JWKS().Keys, 1)
+}
+
+func (s *KeySetTestSuite) TestRejectsForeignAndConfusedTokens() {
+	keys := s.keySet(AlgRS256, s.key("k1", AlgRS256, s.now.Add(-time.Hour), s.now.Add(time.Hour)))
+
+	// token HMAC (termasuk yang ditandatangani dengan JWT_SECRET lama) ditolak
+	hmacToken, err := GenerateToken(s.claims, NewHMACKeySet("secret", ""), time.Minute)
+	s.Require().NoError(err)
+	_, err = ValidateToken(hmacToken, keys)
+	s.Error(err)
+
+	// kid tidak dikenal ditolak
+	other := s.keySet(AlgRS256, s.key("k9", AlgRS256, s.now.Add(-time.Hour), s.now.Add(time.Hour)))
+	foreign, err := GenerateToken(s.claims, other, time.Minute)
+	s.Require().NoError(err)
+	_, err = ValidateToken(foreign, keys)
+	s.Error(err)
+
+	// kid yang sama dengan algoritma berbeda ditolak
+	edKeys := s.keySet(AlgEdDSA, s.key("k1", AlgEdDSA, s.now.Add(-time.Hour), s.now.Add(time.Hour)))
+	confused, err := GenerateToken(s.claims, edKeys, time.Minute)
+	s.Require().NoError(err)
+	_, err = ValidateToken(confused, keys)
+	s.Error(err)
+}
+
+func (s *KeySetTestSuite) TestNoSigningKey() {
+	keys := s.keySet(AlgEdDSA, s.key("k1", AlgEdDSA, s.now.Add(time.Hour), s.now.Add(2*time.Hour)))
+	_, err := GenerateToken(s.claims, keys, time.Minute)
+	s.ErrorIs(err, ErrNoSigningKey)
+}
+
+func (s *KeySetTestSuite) TestJWKS() {
+	keys := s.keySet(AlgRS256,
+		s.key("rs", AlgRS256, s.now.Add(-time.Hour), s.now.Add(time.Hour)),
+		s.key("ed", AlgEdDSA, s.now.Add(-time.Hour), s.now.Add(time.Hour)),
+	)
+
+	set := keys.JWKS()
+	s.Require().Len(set.Keys, 2)
+	s.Equal("OKP", set.Keys[0].KeyType)
+	s.Equal("Ed25519", set.Keys[0].Curve)
+	s.Equal("RSA", set.Keys[1].KeyType)
+	s.Equal("AQAB", set.Keys[1].E)
+
+	s.Empty(NewHMACKeySet("secret", "").JWKS().Keys)
+}
+
+func (s *KeySetTestSuite) TestPrivateKeyStorage() {
+	for _, algorithm := range []string{AlgRS256, AlgEdDSA} {
+		key := s.key("k1", algorithm, s.now, s.now.Add(time.Hour))
+		privatePEM, err := key.MarshalPrivateKey()
+		s.Require().NoError(err)
+
+		encrypted, err := EncryptPrivateKey(privatePEM, "secret")
+		s.Require().NoError(err)
+		s.NotContains(encrypted, "PRIVATE KEY")
+
+		_, err = DecryptPrivateKey(encrypted, "other-secret")
+		s.Error(err)
+
+		decrypted, err := DecryptPrivateKey(encrypted, "secret")
+		s.Require().NoError(err)
+		parsed, err := ParseSigningKey("k1", algorithm, decrypted, key.ActivatesAt, key.ExpiresAt)
+		s.Require().NoError(err)
+
+		// token yang ditandatangani key hasil parse bisa diverifikasi key asli
+		raw, err := GenerateToken(s.claims, s.keySet(algorithm, parsed), time.Minute)
+		s.Require().NoError(err)
+		_, err = ValidateToken(raw, s.keySet(algorithm, key))
+		s.NoError(err)
+	}
+
+	_, err := ParseSigningKey("k1", AlgEdDSA, []byte(strings.Repeat("x", 10)), s.now, s.now)
+	s.Error(err)
+}
+
+func (s *KeySetTestSuite) TestHMACKeepsLegacyBehaviour() {
+	keys := NewHMACKeySet("secret", "")
+	raw, err := GenerateToken(s.claims, keys, time.Minute)
+	s.Require().NoError(err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(raw, &Claims{})
+	s.Require().NoError(err)
+	s.Equal("HS256", token.Method.Alg())
+	s.Nil(token.Header["kid"])
+
+	_, err = ValidateToken(raw, NewHMACKeySet("other", ""))
+	s.Error(err)
+}
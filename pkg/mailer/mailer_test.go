@@ -0,0 +1,132 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MailerTestSuite struct {
+	suite.Suite
+}
+
+func TestMailerSuite(t *testing.T) {
+	suite.Run(t, new(MailerTestSuite))
+}
+
+func (s *MailerTestSuite) TestMemoryMailer() {
+	m := NewMemoryMailer()
+	s.NoError(m.Send(context.Background(), Message{To: "a@example.com", Subject: "Hi", Body: "Hello"}))
+	s.Len(m.Messages(), 1)
+
+	m.Err = errors.New("smtp down")
+	s.Error(m.Send(context.Background(), Message{To: "b@example.com"}))
+	s.Len(m.Messages(), 1)
+}
+
+func (s *MailerTestSuite) TestFileMailer() {
+	dir := s.T().TempDir()
+	m, err := NewFileMailer(dir, "noreply@example.com")
+	s.Require().NoError(err)
+
+	s.NoError(m.Send(context.Background(), Message{To: "a@example.com", Subject: "Konfirmasi booking", Body: "Halo\nTerima kasih"}))
+
+	entries, err := os.ReadDir(dir)
+	s.Require().NoError(err)
+	s.Require().Len(entries, 1)
+
+	data, err := os.ReadFile(dir + "/" + entries[0].Name())
+	s.Require().NoError(err)
+	content := string(data)
+	s.Contains(content, "To: a@example.com\r\n")
+	s.Contains(content, "From: noreply@example.com\r\n")
+	s.True(strings.HasSuffix(content, "Halo\r\nTerima kasih"))
+}
+
+func (s *MailerTestSuite) TestSMTPMailer() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+			case "EHLO":
+				reply("250 localhost")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	s.Require().NoError(err)
+	portNumber, err := strconv.Atoi(port)
+	s.Require().NoError(err)
+
+	m := NewSMTPMailer(host, portNumber, "", "", "noreply@example.com")
+	s.Require().NoError(m.Send(context.Background(), Message{To: "a@example.com", Subject: "Hi", Body: "Halo"}))
+	s.Contains(<-received, "To: a@example.com\r\n")
+}
+
+func (s *MailerTestSuite) TestSMTPMailerStopsAtContextDeadline() {
+	// server menerima koneksi tetapi tidak pernah mengirim greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	s.Require().NoError(err)
+	portNumber, err := strconv.Atoi(port)
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	s.Error(NewSMTPMailer(host, portNumber, "", "", "noreply@example.com").Send(ctx, Message{To: "a@example.com"}))
+	s.Less(time.Since(start), 2*time.Second)
+}
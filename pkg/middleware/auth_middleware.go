@@ -1,17 +1,32 @@
 package middleware
 
 import (
-	"context"
+	"errors"
+	"net/http"
 	"strings"
 
+	"booking/internal/apikey"
+	apikeyModel "booking/internal/apikey/model"
+	"booking/internal/session"
 	service "booking/internal/user"
+	"booking/internal/user/model"
 	"booking/pkg/jwt"
 	"booking/pkg/response"
+	"booking/pkg/tenant"
+	"booking/shared/constants"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-func AuthMiddleware(userService service.UserServiceInterface, jwtSecret string) echo.MiddlewareFunc {
+// ImpersonatedByHeader berisi ID admin pada response untuk request dengan token impersonasi
+const ImpersonatedByHeader = "X-Impersonated-By"
+
+// AuthMiddleware menerima bearer token berupa JWT dari login atau API key (prefix bk_).
+// Access token divalidasi tanpa akses Redis/database: identitas, organisasi, role dan status
+// 2FA diambil dari claims, sedangkan pencabutan dicek di daftar pencabutan lokal. Identitas
+// disimpan di context ("principal"); request dengan API key juga menyimpan key ("api_key").
+func AuthMiddleware(userService service.UserServiceInterface, sessionService session.SessionServiceInterface, apiKeyService apikey.APIKeyServiceInterface, keys *jwt.KeySet) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get("Authorization")
@@ -25,42 +40,104 @@ func AuthMiddleware(userService service.UserServiceInterface, jwtSecret string)
 			}
 
 			tokenString := parts[1]
-			claims, err := jwt.ValidateToken(tokenString, jwtSecret)
-			if err != nil {
-				return response.Unauthorized(c, "invalid token", err)
+			if apikeyModel.IsAPIKey(tokenString) {
+				return authenticateAPIKey(c, next, userService, apiKeyService, tokenString)
 			}
 
-			// Periksa token di Redis
-			ctx := context.Background()
-			storedToken, err := userService.GetStoredToken(ctx, claims.UserID)
+			claims, err := jwt.ValidateToken(tokenString, keys)
 			if err != nil {
-				// Jika token tidak ditemukan di Redis, berarti user sudah logout
-				return response.Unauthorized(c, "token has been revoked or expired", nil)
+				return response.Unauthorized(c, "invalid token", err)
 			}
 
-			if storedToken != tokenString {
-				// Jika token berbeda, berarti user menggunakan token yang tidak valid
-				return response.Unauthorized(c, "token is invalid or has been revoked", nil)
+			principal, err := principalFromClaims(claims)
+			if err != nil {
+				return response.Unauthorized(c, "invalid token", err)
 			}
 
-			user, err := userService.GetUserByID(ctx, claims.UserID)
-			if err != nil {
-				return response.Unauthorized(c, "user not found", err)
+			// Token hanya berlaku di organisasi tempat user login
+			ctx := c.Request().Context()
+			if organizationID, ok := tenant.FromContext(ctx); !ok || organizationID != principal.OrganizationID {
+				return response.Unauthorized(c, "invalid token", nil)
 			}
 
-			if user == nil {
-				return response.Unauthorized(c, "user not found", nil)
+			if err := sessionService.Validate(ctx, claims); err != nil {
+				if errors.Is(err, session.ErrRevocationListStale) {
+					return response.Error(c, http.StatusServiceUnavailable, "unable to verify token, please try again", err)
+				}
+				return response.Unauthorized(c, "token has been revoked or expired", nil)
 			}
 
-			// Pastikan user adalah pointer yang valid sebelum disimpan ke context
-			if user != nil {
-				c.Set("user", user)
-				c.Set("user_id", claims.UserID)
-			} else {
-				return response.Unauthorized(c, "invalid user data", nil)
+			// Token impersonasi ditandai di response agar client bisa menampilkannya
+			if principal.Impersonated() {
+				c.Response().Header().Set(ImpersonatedByHeader, principal.ImpersonatorID.String())
 			}
 
+			c.Set("principal", principal)
+			c.Set("user_id", claims.UserID)
+			c.Set("session_id", claims.SessionID)
 			return next(c)
 		}
 	}
 }
+
+func principalFromClaims(claims *jwt.Claims) (*model.Principal, error) {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	organizationID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	principal := &model.Principal{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Role:           constants.Role(claims.Role),
+		TwoFactor:      claims.TwoFactor,
+		SessionID:      claims.SessionID,
+		TokenID:        claims.ID,
+	}
+	if claims.Actor != nil {
+		if principal.ImpersonatorID, err = uuid.Parse(claims.Actor.Subject); err != nil {
+			return nil, err
+		}
+	}
+	return principal, nil
+}
+
+func authenticateAPIKey(c echo.Context, next echo.HandlerFunc, userService service.UserServiceInterface, apiKeyService apikey.APIKeyServiceInterface, rawKey string) error {
+	ctx := c.Request().Context()
+	key, err := apiKeyService.Authenticate(ctx, rawKey, c.RealIP())
+	if err != nil {
+		return response.Unauthorized(c, "invalid, revoked or expired api key", nil)
+	}
+
+	user, err := userService.GetUserByID(ctx, key.UserID.String())
+	if err != nil || user == nil {
+		return response.Unauthorized(c, "user not found", err)
+	}
+	if user.IsSuspended() {
+		return response.Unauthorized(c, "account has been suspended", nil)
+	}
+
+	c.Set("principal", user.Principal())
+	c.Set("user_id", key.UserID.String())
+	c.Set("api_key", key)
+	return next(c)
+}
+
+// RequireSession menolak request yang memakai API key atau token impersonasi; dipasang di
+// endpoint yang mengubah kredensial (password, 2FA, pembuatan API key) atau tindakan admin
+// agar key yang bocor tidak bisa dipakai untuk mengambil alih akun dan admin yang sedang
+// impersonasi tidak bisa bertindak atas nama user di endpoint tersebut
+func RequireSession(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, ok := c.Get("api_key").(*apikeyModel.APIKey); ok {
+			return response.Forbidden(c, "access denied: this endpoint requires an interactive login", nil)
+		}
+		if principal, ok := c.Get("principal").(*model.Principal); ok && principal.Impersonated() {
+			return response.Forbidden(c, "access denied: this endpoint is not available while impersonating", nil)
+		}
+		return next(c)
+	}
+}
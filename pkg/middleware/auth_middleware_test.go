@@ -0,0 +1,296 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"booking/internal/session"
+	"booking/internal/user/model"
+	"booking/pkg/jwt"
+	"booking/pkg/logger"
+	"booking/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryRevocationStore menggantikan Redis untuk daftar pencabutan
+type memoryRevocationStore struct {
+	entries map[string]time.Time
+}
+
+func (m *memoryRevocationStore) AddRevocations(ctx context.Context, members []string, at time.Time, retention time.Duration) error {
+	for _, member := range members {
+		m.entries[member] = at
+	}
+	return nil
+}
+
+func (m *memoryRevocationStore) RevocationsSince(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	return m.entries, nil
+}
+
+// authFixture menyiapkan AuthMiddleware dengan key HMAC dan daftar pencabutan di memori
+type authFixture struct {
+	echo           *echo.Echo
+	keys           *jwt.KeySet
+	revocations    *session.RevocationList
+	sessionService *session.SessionService
+	organizationID uuid.UUID
+	userID         uuid.UUID
+	sessionID      string
+}
+
+func newAuthFixture() (*authFixture, error) {
+	log := logger.NewLogger()
+	keys := jwt.NewHMACKeySet("test-secret", "")
+	revocations := session.NewRevocationList(&memoryRevocationStore{entries: map[string]time.Time{}}, log, time.Minute, time.Second)
+	if err := revocations.Sync(context.Background()); err != nil {
+		return nil, err
+	}
+	return &authFixture{
+		echo:           echo.New(),
+		keys:           keys,
+		revocations:    revocations,
+		sessionService: session.NewSessionService(nil, log, keys, revocations, time.Minute, time.Hour),
+		organizationID: uuid.New(),
+		userID:         uuid.New(),
+		sessionID:      uuid.NewString(),
+	}, nil
+}
+
+func (f *authFixture) token() (string, error) {
+	return jwt.GenerateToken(jwt.Claims{
+		UserID:         f.userID.String(),
+		SessionID:      f.sessionID,
+		OrganizationID: f.organizationID.String(),
+		Role:           "admin",
+		TwoFactor:      true,
+	}, f.keys, time.Minute)
+}
+
+func (f *authFixture) request(token string, organizationID uuid.UUID) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/booking", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req = req.WithContext(tenant.WithOrganization(req.Context(), organizationID))
+	rec := httptest.NewRecorder()
+	return f.echo.NewContext(req, rec), rec
+}
+
+func (f *authFixture) middleware() echo.MiddlewareFunc {
+	return AuthMiddleware(nil, f.sessionService, nil, f.keys)
+}
+
+type AuthMiddlewareTestSuite struct {
+	suite.Suite
+	fixture *authFixture
+	token   string
+}
+
+func TestAuthMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(AuthMiddlewareTestSuite))
+}
+
+func (s *AuthMiddlewareTestSuite) SetupTest() {
+	fixture, err := newAuthFixture()
+	s.Require().NoError(err)
+	s.fixture = fixture
+	s.token, err = fixture.token()
+	s.Require().NoError(err)
+}
+
+func (s *AuthMiddlewareTestSuite) serve(organizationID uuid.UUID) (*model.Principal, int) {
+	var principal *model.Principal
+	c, rec := s.fixture.request(s.token, organizationID)
+	err := s.fixture.middleware()(func(c echo.Context) error {
+		principal, _ = c.Get("principal").(*model.Principal)
+		return c.NoContent(http.StatusNoContent)
+	})(c)
+	s.Require().NoError(err)
+	return principal, rec.Code
+}
+
+func (s *AuthMiddlewareTestSuite) TestPrincipalFromClaims() {
+	principal, code := s.serve(s.fixture.organizationID)
+	s.Equal(http.StatusNoContent, code)
+	s.Require().NotNil(principal)
+	s.Equal(s.fixture.userID, principal.UserID)
+	s.Equal(s.fixture.organizationID, principal.OrganizationID)
+	s.Equal("admin", string(principal.Role))
+	s.True(principal.TwoFactorEnabled())
+	s.Equal(s.fixture.sessionID, principal.SessionID)
+}
+
+func (s *AuthMiddlewareTestSuite) TestRejectsOtherOrganization() {
+	principal, code := s.serve(uuid.New())
+	s.Equal(http.StatusUnauthorized, code)
+	s.Nil(principal)
+}
+
+func (s *AuthMiddlewareTestSuite) TestRejectsRevokedSession() {
+	s.Require().NoError(s.fixture.revocations.RevokeSessions(context.Background(), s.fixture.sessionID))
+	_, code := s.serve(s.fixture.organizationID)
+	s.Equal(http.StatusUnauthorized, code)
+}
+
+func (s *AuthMiddlewareTestSuite) TestRejectsTokensIssuedBeforeUserInvalidation() {
+	s.Require().NoError(s.fixture.sessionService.InvalidateAccessTokens(context.Background(), s.fixture.userID.String()))
+	_, code := s.serve(s.fixture.organizationID)
+	s.Equal(http.StatusUnauthorized, code)
+}
+
+func (s *AuthMiddlewareTestSuite) TestImpersonationTokenIsMarked() {
+	adminID := uuid.New()
+	token, err := jwt.GenerateToken(jwt.Claims{
+		UserID:         s.fixture.userID.String(),
+		SessionID:      s.fixture.sessionID,
+		OrganizationID: s.fixture.organizationID.String(),
+		Role:           "user",
+		Actor:          &jwt.Actor{Subject: adminID.String()},
+	}, s.fixture.keys, time.Minute)
+	s.Require().NoError(err)
+
+	c, rec := s.fixture.request(token, s.fixture.organizationID)
+	handler := s.fixture.middleware()(RequireSession(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}))
+	s.Require().NoError(handler(c))
+
+	// token impersonasi ditandai di response dan ditolak di endpoint yang butuh login asli
+	s.Equal(adminID.String(), rec.Header().Get(ImpersonatedByHeader))
+	s.Equal(http.StatusForbidden, rec.Code)
+	principal, ok := c.Get("principal").(*model.Principal)
+	s.Require().True(ok)
+	s.True(principal.Impersonated())
+	s.Equal(s.fixture.userID, principal.UserID)
+}
+
+// fakeRedis adalah server RESP minimal di loopback agar baseline benchmark mengukur round
+// trip jaringan yang sebenarnya; GET selalu mengembalikan value, perintah lain ditolak
+func fakeRedis(b *testing.B, value string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveRESP(conn, value)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveRESP(conn net.Conn, value string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(reader)
+		if err != nil {
+			return
+		}
+		reply := "-ERR unknown command\r\n"
+		if len(args) > 0 && strings.EqualFold(args[0], "GET") {
+			reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPArray(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, errors.New("expected array")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// BenchmarkAuthMiddleware membandingkan validasi stateless dengan cara lama yang membaca
+// session dari Redis di setiap request (cara lama juga membaca user dari database, yang
+// tidak ikut diukur di sini sehingga selisih sebenarnya lebih besar)
+func BenchmarkAuthMiddleware(b *testing.B) {
+	fixture, err := newAuthFixture()
+	if err != nil {
+		b.Fatal(err)
+	}
+	token, err := fixture.token()
+	if err != nil {
+		b.Fatal(err)
+	}
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+
+	run := func(b *testing.B, handler echo.HandlerFunc) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c, rec := fixture.request(token, fixture.organizationID)
+			if err := handler(c); err != nil || rec.Code != http.StatusNoContent {
+				b.Fatalf("unexpected response %d: %v", rec.Code, err)
+			}
+		}
+	}
+
+	b.Run("stateless", func(b *testing.B) {
+		run(b, fixture.middleware()(ok))
+	})
+
+	b.Run("redis_session_lookup", func(b *testing.B) {
+		client := goredis.NewClient(&goredis.Options{Addr: fakeRedis(b, fixture.userID.String())})
+		defer client.Close()
+
+		baseline := func(c echo.Context) error {
+			claims, err := jwt.ValidateToken(strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer "), fixture.keys)
+			if err != nil {
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			owner, err := client.Get(c.Request().Context(), "session:"+claims.SessionID).Result()
+			if err != nil || owner != claims.UserID {
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			return ok(c)
+		}
+		run(b, baseline)
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	roleModel "booking/internal/role/model"
+	"booking/internal/space"
+	"booking/internal/user/model"
+	"booking/pkg/response"
+	"booking/shared/constants"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SpaceOwnerMiddleware memastikan space pada param :id dimiliki user yang login.
+// User dengan permission space:write boleh mengelola semua space.
+// Harus dipasang setelah RequirePermission agar permission user sudah ada di context.
+func SpaceOwnerMiddleware(spaceService space.SpaceServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("principal").(*model.Principal)
+			if !ok || user == nil {
+				return response.Unauthorized(c, "unauthorized", nil)
+			}
+
+			if perms, ok := c.Get("permissions").(roleModel.PermissionSet); ok && perms.Has(constants.PermissionSpaceWrite) {
+				return next(c)
+			}
+
+			s, err := spaceService.GetByID(c.Request().Context(), c.Param("id"))
+			if err != nil {
+				return response.NotFound(c, "space not found", err)
+			}
+
+			if !s.IsOwnedBy(user.UserID) {
+				return response.Forbidden(c, "access denied: you do not own this space", nil)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	apikeyModel "booking/internal/apikey/model"
+	"booking/internal/role"
+	roleModel "booking/internal/role/model"
+	"booking/internal/user/model"
+	"booking/pkg/response"
+	"booking/shared/constants"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PermissionFunc membuat middleware RequirePermission untuk daftar permission tertentu
+type PermissionFunc func(permissions ...constants.Permission) echo.MiddlewareFunc
+
+func NewPermissionFunc(roleService role.RoleServiceInterface) PermissionFunc {
+	return func(permissions ...constants.Permission) echo.MiddlewareFunc {
+		return RequirePermission(roleService, permissions...)
+	}
+}
+
+// RequirePermission mengizinkan request hanya jika role user memiliki semua permission.
+// Permission efektif disimpan di context ("permissions") untuk dipakai handler berikutnya.
+// Jika role mewajibkan 2FA, permission baru bisa dipakai setelah user mengaktifkan 2FA.
+// Request dengan API key hanya mendapat permission role yang juga ada di scope key.
+func RequirePermission(roleService role.RoleServiceInterface, permissions ...constants.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("principal").(*model.Principal)
+			if !ok || user == nil {
+				return response.Unauthorized(c, "unauthorized", nil)
+			}
+
+			granted, ok := c.Get("permissions").(roleModel.PermissionSet)
+			if !ok {
+				var err error
+				granted, err = roleService.Permissions(c.Request().Context(), user.Role)
+				if err != nil {
+					return response.InternalServerError(c, "failed to load permissions", err)
+				}
+				if key, ok := c.Get("api_key").(*apikeyModel.APIKey); ok {
+					granted = granted.Restrict(key.ScopeSet())
+				}
+				c.Set("permissions", granted)
+			}
+
+			if !granted.HasAll(permissions...) {
+				return response.Forbidden(c, "access denied: missing required permission", nil)
+			}
+
+			if !user.TwoFactorEnabled() {
+				required, err := roleService.RequiresTwoFactor(c.Request().Context(), user.Role)
+				if err != nil {
+					return response.InternalServerError(c, "failed to load role", err)
+				}
+				if required {
+					return response.Forbidden(c, "access denied: two-factor authentication is required for your role", nil)
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"booking/internal/organization"
+	"booking/pkg/response"
+	"booking/pkg/tenant"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OrganizationHeader dipakai klien yang tidak bisa memakai subdomain (misalnya aplikasi mobile)
+const OrganizationHeader = "X-Organization"
+
+// PlatformKeyHeader berisi API key operator platform untuk mengelola organisasi
+const PlatformKeyHeader = "X-Platform-Key"
+
+// TenantMiddleware menentukan organisasi aktif dari header X-Organization, subdomain dari
+// baseDomain, atau defaultSlug, lalu menaruhnya di ctx request sehingga semua query dibatasi
+func TenantMiddleware(organizationService organization.OrganizationServiceInterface, baseDomain, defaultSlug string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			slug := ResolveTenantSlug(c.Request().Header.Get(OrganizationHeader), c.Request().Host, baseDomain, defaultSlug)
+			if slug == "" {
+				return response.BadRequest(c, "organization is required", nil)
+			}
+
+			org, err := organizationService.GetBySlug(c.Request().Context(), slug)
+			if err != nil {
+				if errors.Is(err, organization.ErrOrganizationNotFound) {
+					return response.NotFound(c, "organization not found", err)
+				}
+				return response.InternalServerError(c, "failed to resolve organization", err)
+			}
+
+			ctx := tenant.WithOrganization(c.Request().Context(), org.ID)
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Set("organization", org)
+
+			return next(c)
+		}
+	}
+}
+
+// ResolveTenantSlug mengambil slug organisasi: header lebih dulu, lalu subdomain, lalu default
+func ResolveTenantSlug(header, host, baseDomain, defaultSlug string) string {
+	if slug := strings.TrimSpace(header); slug != "" {
+		return strings.ToLower(slug)
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	baseDomain = strings.ToLower(strings.Trim(baseDomain, "."))
+	if baseDomain != "" && strings.HasSuffix(host, "."+baseDomain) {
+		sub := strings.TrimSuffix(host, "."+baseDomain)
+		// Hanya satu level subdomain yang dianggap slug organisasi
+		if sub != "" && !strings.Contains(sub, ".") && sub != "www" {
+			return sub
+		}
+	}
+
+	return defaultSlug
+}
+
+// PlatformMiddleware melindungi endpoint operator platform dengan API key; nonaktif jika key kosong
+func PlatformMiddleware(apiKey string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if apiKey == "" {
+				return response.Error(c, http.StatusNotFound, "not found", nil)
+			}
+
+			key := c.Request().Header.Get(PlatformKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+				return response.Unauthorized(c, "invalid platform key", nil)
+			}
+
+			return next(c)
+		}
+	}
+}
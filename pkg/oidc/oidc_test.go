@@ -0,0 +1,213 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockProvider adalah provider OIDC lokal: discovery, JWKS dan token endpoint
+type mockProvider struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	clientID string
+	// idToken dibuat ulang per request agar test bisa mengubah claims
+	claims   func() jwt.MapClaims
+	verifier string
+}
+
+func newMockProvider(clientID string) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	m := &mockProvider{key: key, kid: "key-1", clientID: clientID}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": m.kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("code") != "good-code" || CodeChallenge(r.PostForm.Get("code_verifier")) != CodeChallenge(m.verifier) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     m.sign(m.claims()),
+		})
+	})
+	m.server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockProvider) sign(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	signed, err := token.SignedString(m.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (m *mockProvider) baseClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            m.clientID,
+		"sub":            "google-123",
+		"email":          "ana@example.com",
+		"email_verified": "true",
+		"name":           "Ana",
+		"nonce":          nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+type OIDCTestSuite struct {
+	suite.Suite
+	mock     *mockProvider
+	provider *Provider
+	ctx      context.Context
+}
+
+func TestOIDCSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
+
+func (s *OIDCTestSuite) SetupTest() {
+	s.mock = newMockProvider("client-1")
+	s.provider = NewProvider(Config{
+		Name:        "mock",
+		Issuer:      s.mock.server.URL + "/",
+		ClientID:    "client-1",
+		RedirectURL: "http://localhost/auth/oidc/mock/callback",
+	}, s.mock.server.Client())
+	s.ctx = context.Background()
+}
+
+func (s *OIDCTestSuite) TearDownTest() {
+	s.mock.server.Close()
+}
+
+func (s *OIDCTestSuite) TestAuthURLUsesPKCE() {
+	raw, err := s.provider.AuthURL(s.ctx, "state-1", "nonce-1", "verifier-1")
+	s.Require().NoError(err)
+
+	u, err := url.Parse(raw)
+	s.Require().NoError(err)
+	s.Equal("/authorize", u.Path)
+	q := u.Query()
+	s.Equal("code", q.Get("response_type"))
+	s.Equal("state-1", q.Get("state"))
+	s.Equal("nonce-1", q.Get("nonce"))
+	s.Equal("S256", q.Get("code_challenge_method"))
+	s.Equal(CodeChallenge("verifier-1"), q.Get("code_challenge"))
+	s.Equal("openid email profile", q.Get("scope"))
+}
+
+func (s *OIDCTestSuite) TestExchangeAndVerify() {
+	s.mock.verifier = "verifier-1"
+	s.mock.claims = func() jwt.MapClaims { return s.mock.baseClaims("nonce-1") }
+
+	token, err := s.provider.Exchange(s.ctx, "good-code", "verifier-1")
+	s.Require().NoError(err)
+
+	claims, err := s.provider.Verify(s.ctx, token.IDToken, "nonce-1")
+	s.Require().NoError(err)
+	s.Equal("google-123", claims.Subject)
+	s.Equal("ana@example.com", claims.Email)
+	s.True(bool(claims.EmailVerified))
+
+	_, err = s.provider.Verify(s.ctx, token.IDToken, "other-nonce")
+	s.ErrorIs(err, ErrNonceMismatch)
+}
+
+func (s *OIDCTestSuite) TestExchangeRejectsWrongVerifier() {
+	s.mock.verifier = "verifier-1"
+	_, err := s.provider.Exchange(s.ctx, "good-code", "verifier-2")
+	s.Error(err)
+}
+
+func (s *OIDCTestSuite) TestVerifyRejectsInvalidTokens() {
+	tests := map[string]func(jwt.MapClaims){
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "client-2" },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"foreign azp": func(c jwt.MapClaims) {
+			c["aud"] = []string{"client-1", "client-2"}
+			c["azp"] = "client-2"
+		},
+	}
+	for name, mutate := range tests {
+		claims := s.mock.baseClaims("nonce-1")
+		mutate(claims)
+		_, err := s.provider.Verify(s.ctx, s.mock.sign(claims), "nonce-1")
+		s.ErrorIs(err, ErrInvalidIDToken, name)
+	}
+
+	// tanda tangan dari key lain ditolak
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, s.mock.baseClaims("nonce-1"))
+	forged.Header["kid"] = s.mock.kid
+	raw, err := forged.SignedString(other)
+	s.Require().NoError(err)
+	_, err = s.provider.Verify(s.ctx, raw, "nonce-1")
+	s.ErrorIs(err, ErrInvalidIDToken)
+
+	// algoritma simetris tidak diterima
+	hs, err := jwt.NewWithClaims(jwt.SigningMethodHS256, s.mock.baseClaims("nonce-1")).SignedString([]byte("secret"))
+	s.Require().NoError(err)
+	_, err = s.provider.Verify(s.ctx, hs, "nonce-1")
+	s.ErrorIs(err, ErrInvalidIDToken)
+}
+
+func (s *OIDCTestSuite) TestLoadConfigs() {
+	path := filepath.Join(s.T().TempDir(), "providers.json")
+	s.Require().NoError(os.WriteFile(path, []byte(`[{"name":"google","issuer":"https://accounts.google.com","client_id":"abc"}]`), 0o600))
+
+	configs, err := LoadConfigs(path)
+	s.Require().NoError(err)
+	s.Len(configs, 1)
+	s.Equal("google", configs[0].Name)
+
+	s.Require().NoError(os.WriteFile(path, []byte(`[{"name":"google","issuer":"x","client_id":"a"},{"name":"google","issuer":"y","client_id":"b"}]`), 0o600))
+	_, err = LoadConfigs(path)
+	s.Error(err)
+}
@@ -2,6 +2,11 @@ package redis
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -33,30 +38,165 @@ func NewRedisClient(addr, password string, db int) *RedisClient {
 	}
 }
 
-func (r *RedisClient) SetToken(ctx context.Context, userID string, token string, expiration time.Duration) error {
+// revocationsKey adalah sorted set daftar pencabutan access token; skor adalah waktu
+// pencabutan (milidetik) sehingga instance lain bisa mengambil perubahan terbaru saja
+const revocationsKey = "auth:revocations"
+
+// AddRevocations mencatat entri pencabutan dan membuang entri yang lebih tua dari retention
+// (access token yang lebih tua dari itu sudah kedaluwarsa)
+func (r *RedisClient) AddRevocations(ctx context.Context, members []string, at time.Time, retention time.Duration) error {
 	if r.client == nil {
 		return redis.ErrClosed
 	}
-	key := getTokenKey(userID)
-	return r.client.Set(ctx, key, token, expiration).Err()
+	if len(members) == 0 {
+		return nil
+	}
+
+	entries := make([]redis.Z, len(members))
+	for i, member := range members {
+		entries[i] = redis.Z{Score: float64(at.UnixMilli()), Member: member}
+	}
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, revocationsKey, entries...)
+	pipe.ZRemRangeByScore(ctx, revocationsKey, "-inf", fmt.Sprintf("(%d", at.Add(-retention).UnixMilli()))
+	pipe.Expire(ctx, revocationsKey, retention)
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
-func (r *RedisClient) GetToken(ctx context.Context, userID string) (string, error) {
+// RevocationsSince mengambil entri pencabutan yang dicatat sejak waktu tertentu
+func (r *RedisClient) RevocationsSince(ctx context.Context, since time.Time) (map[string]time.Time, error) {
 	if r.client == nil {
-		return "", redis.ErrClosed
+		return nil, redis.ErrClosed
+	}
+
+	entries, err := r.client.ZRangeByScoreWithScores(ctx, revocationsKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		member, ok := entry.Member.(string)
+		if !ok {
+			continue
+		}
+		result[member] = time.UnixMilli(int64(entry.Score))
+	}
+	return result, nil
+}
+
+// RecordAttempt mencatat satu percobaan pada sliding window (sorted set dengan skor waktu)
+// dan mengembalikan jumlah percobaan dalam window tersebut
+func (r *RedisClient) RecordAttempt(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	if r.client == nil {
+		return 0, redis.ErrClosed
+	}
+
+	var card *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", now.Add(-window).UnixMilli()))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: attemptMember(now)})
+		card = pipe.ZCard(ctx, key)
+		pipe.PExpire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(card.Val()), nil
+}
+
+// RecentAttempts mengembalikan jumlah percobaan dalam window dan waktu percobaan terakhir
+func (r *RedisClient) RecentAttempts(ctx context.Context, key string, now time.Time, window time.Duration) (int, time.Time, error) {
+	if r.client == nil {
+		return 0, time.Time{}, redis.ErrClosed
+	}
+
+	var (
+		card *redis.IntCmd
+		last *redis.ZSliceCmd
+	)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", now.Add(-window).UnixMilli()))
+		card = pipe.ZCard(ctx, key)
+		last = pipe.ZRevRangeWithScores(ctx, key, 0, 0)
+		return nil
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var lastAt time.Time
+	if entries := last.Val(); len(entries) > 0 {
+		lastAt = time.UnixMilli(int64(entries[0].Score))
 	}
-	key := getTokenKey(userID)
-	return r.client.Get(ctx, key).Result()
+	return int(card.Val()), lastAt, nil
 }
 
-func (r *RedisClient) DeleteToken(ctx context.Context, userID string) error {
+func (r *RedisClient) ClearAttempts(ctx context.Context, keys ...string) error {
 	if r.client == nil {
 		return redis.ErrClosed
 	}
-	key := getTokenKey(userID)
-	return r.client.Del(ctx, key).Err()
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// Lock menyimpan waktu berakhirnya kunci; key hilang sendiri setelah waktu itu lewat
+func (r *RedisClient) Lock(ctx context.Context, key string, until time.Time) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, key, until.UnixMilli(), ttl).Err()
+}
+
+// LockedUntil mengembalikan waktu berakhirnya kunci; waktu nol jika tidak terkunci
+func (r *RedisClient) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	if r.client == nil {
+		return time.Time{}, redis.ErrClosed
+	}
+	millis, err := r.client.Get(ctx, key).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.UnixMilli(millis), nil
+}
+
+// attemptMember membuat member unik agar percobaan pada milidetik yang sama tetap terhitung
+func attemptMember(now time.Time) string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%d-%s", now.UnixNano(), hex.EncodeToString(buf))
+}
+
+// SetOAuthState menyimpan data login OIDC yang sedang berjalan (nonce, verifier PKCE)
+func (r *RedisClient) SetOAuthState(ctx context.Context, state string, value string, expiration time.Duration) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	return r.client.Set(ctx, getOAuthStateKey(state), value, expiration).Err()
+}
+
+// TakeOAuthState mengambil sekaligus menghapus state sehingga state hanya bisa dipakai sekali
+func (r *RedisClient) TakeOAuthState(ctx context.Context, state string) (string, error) {
+	if r.client == nil {
+		return "", redis.ErrClosed
+	}
+	return r.client.GetDel(ctx, getOAuthStateKey(state)).Result()
 }
 
-func getTokenKey(userID string) string {
-	return "user_token:" + userID
+func getOAuthStateKey(state string) string {
+	return "oauth_state:" + state
 }
@@ -51,6 +51,19 @@ func Forbidden(c echo.Context, message string, err error) error {
 	return Error(c, http.StatusForbidden, message, err)
 }
 
+// Conflict mengirim 409 beserta data yang menjelaskan penyebab konflik
+func Conflict(c echo.Context, message string, err error, data any) error {
+	response := Response{
+		Status:  "error",
+		Message: message,
+		Data:    data,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	return c.JSON(http.StatusConflict, response)
+}
+
 func Ok(c echo.Context, message string, data any) error {
 	return Success(c, http.StatusOK, message, data)
 }
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage menyimpan file berdasarkan key (path relatif, misalnya "spaces/<id>/photo.jpg")
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL mengembalikan URL publik untuk key
+	URL(key string) string
+}
+
+// Options berisi konfigurasi semua driver storage
+type Options struct {
+	Driver    string
+	LocalDir  string
+	PublicURL string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+func New(opts Options) (Storage, error) {
+	switch opts.Driver {
+	case "local", "":
+		dir := opts.LocalDir
+		if dir == "" {
+			dir = "uploads"
+		}
+		return NewLocalStorage(dir, opts.PublicURL)
+	case "s3":
+		if opts.S3Endpoint == "" || opts.S3Bucket == "" {
+			return nil, errors.New("s3 endpoint and bucket are required")
+		}
+		return NewS3Storage(opts.S3Endpoint, opts.S3Region, opts.S3Bucket, opts.S3AccessKey, opts.S3SecretKey, opts.PublicURL), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", opts.Driver)
+	}
+}
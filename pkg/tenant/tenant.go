@@ -0,0 +1,116 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FieldName adalah field yang menandai model milik sebuah organisasi
+const (
+	FieldName  = "OrganizationID"
+	ColumnName = "organization_id"
+)
+
+var ErrCrossTenant = errors.New("record belongs to another organization")
+
+type contextKey struct{}
+
+// WithOrganization menandai ctx dengan organisasi aktif; semua query GORM yang memakai
+// ctx ini otomatis dibatasi ke organisasi tersebut
+func WithOrganization(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, organizationID)
+}
+
+// FromContext mengambil organisasi aktif; false jika ctx tidak punya tenant (misalnya worker)
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	id, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
+
+// Plugin adalah plugin GORM yang menambahkan filter organization_id pada query, update dan
+// delete, serta mengisi organization_id saat create, untuk model yang memiliki field OrganizationID
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scope); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", assign)
+}
+
+func scope(db *gorm.DB) {
+	organizationID, ok := FromContext(db.Statement.Context)
+	if !ok || db.Statement.Schema == nil || db.Statement.Schema.LookUpField(FieldName) == nil {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: ColumnName}, Value: organizationID},
+	}})
+}
+
+func assign(db *gorm.DB) {
+	organizationID, ok := FromContext(db.Statement.Context)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(FieldName)
+	if field == nil {
+		return
+	}
+
+	// Upsert (misalnya fallback Save) bisa menimpa baris organisasi lain dengan primary key
+	// yang sama, sehingga hanya diizinkan untuk record yang sudah jelas milik tenant ini
+	_, upsert := db.Statement.Clauses["ON CONFLICT"]
+
+	set := func(rv reflect.Value) {
+		current, isZero := field.ValueOf(db.Statement.Context, rv)
+		if isZero && upsert {
+			_ = db.AddError(ErrCrossTenant)
+			return
+		}
+		if !isZero {
+			if id, ok := current.(uuid.UUID); ok && id != organizationID {
+				_ = db.AddError(ErrCrossTenant)
+			}
+			return
+		}
+		if err := field.Set(db.Statement.Context, rv, organizationID); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := reflect.Indirect(rv.Index(i))
+			if elem.Kind() == reflect.Struct {
+				set(elem)
+			}
+		}
+	case reflect.Struct:
+		set(rv)
+	}
+}
@@ -0,0 +1,161 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type record struct {
+	ID             uuid.UUID `gorm:"type:char(36);primary_key"`
+	OrganizationID uuid.UUID `gorm:"type:char(36)"`
+	Name           string
+}
+
+type globalRecord struct {
+	ID   uuid.UUID `gorm:"type:char(36);primary_key"`
+	Name string
+}
+
+type TenantTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	orgA uuid.UUID
+	orgB uuid.UUID
+}
+
+func TestTenantSuite(t *testing.T) {
+	suite.Run(t, new(TenantTestSuite))
+}
+
+func (s *TenantTestSuite) SetupTest() {
+	// DryRun: SQL hanya dibangun tanpa koneksi database sehingga filter tenant bisa diperiksa
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	s.Require().NoError(err)
+	s.Require().NoError(db.Use(Plugin{}))
+
+	s.db = db
+	s.orgA = uuid.New()
+	s.orgB = uuid.New()
+}
+
+func (s *TenantTestSuite) ctx(org uuid.UUID) context.Context {
+	return WithOrganization(context.Background(), org)
+}
+
+func (s *TenantTestSuite) TestFromContext() {
+	_, ok := FromContext(context.Background())
+	s.False(ok)
+
+	_, ok = FromContext(WithOrganization(context.Background(), uuid.Nil))
+	s.False(ok)
+
+	id, ok := FromContext(s.ctx(s.orgA))
+	s.True(ok)
+	s.Equal(s.orgA, id)
+}
+
+func (s *TenantTestSuite) TestQueryIsScopedToTenant() {
+	var rows []record
+	stmt := s.db.WithContext(s.ctx(s.orgA)).Where("name = ?", "x").Find(&rows).Statement
+
+	s.Contains(stmt.SQL.String(), "`records`.`organization_id` = ?")
+	s.Contains(stmt.Vars, s.orgA)
+	s.NotContains(stmt.Vars, s.orgB)
+}
+
+func (s *TenantTestSuite) TestFirstByIDCannotReachOtherTenant() {
+	id := uuid.New()
+	var row record
+	stmt := s.db.WithContext(s.ctx(s.orgB)).First(&row, "id = ?", id).Statement
+
+	s.Contains(stmt.SQL.String(), "id = ?")
+	s.Contains(stmt.SQL.String(), "`records`.`organization_id` = ?")
+	s.Equal([]interface{}{id, s.orgB}, stmt.Vars[:2])
+}
+
+func (s *TenantTestSuite) TestCountAndJoinAreScoped() {
+	var count int64
+	stmt := s.db.WithContext(s.ctx(s.orgA)).Model(&record{}).
+		Joins("JOIN other ON other.id = records.id").Count(&count).Statement
+
+	s.Contains(stmt.SQL.String(), "`records`.`organization_id` = ?")
+	s.Contains(stmt.Vars, s.orgA)
+}
+
+func (s *TenantTestSuite) TestUpdateIsScoped() {
+	stmt := s.db.WithContext(s.ctx(s.orgA)).Model(&record{}).
+		Where("id = ?", uuid.New()).Update("name", "y").Statement
+
+	s.Contains(stmt.SQL.String(), "UPDATE `records`")
+	s.Contains(stmt.SQL.String(), "`records`.`organization_id` = ?")
+	s.Contains(stmt.Vars, s.orgA)
+}
+
+func (s *TenantTestSuite) TestSaveLoadedRecordIsScoped() {
+	row := record{ID: uuid.New(), OrganizationID: s.orgA, Name: "y"}
+	stmt := s.db.WithContext(s.ctx(s.orgA)).Save(&row).Statement
+
+	s.Contains(stmt.SQL.String(), "`records`.`organization_id` = ?")
+}
+
+func (s *TenantTestSuite) TestDeleteIsScoped() {
+	stmt := s.db.WithContext(s.ctx(s.orgA)).Delete(&record{}, "id = ?", uuid.New()).Statement
+
+	s.Contains(stmt.SQL.String(), "DELETE FROM `records`")
+	s.Contains(stmt.SQL.String(), "`records`.`organization_id` = ?")
+	s.Contains(stmt.Vars, s.orgA)
+}
+
+func (s *TenantTestSuite) TestCreateAssignsTenant() {
+	row := record{ID: uuid.New(), Name: "x"}
+	s.NoError(s.db.WithContext(s.ctx(s.orgA)).Create(&row).Error)
+	s.Equal(s.orgA, row.OrganizationID)
+
+	rows := []record{{ID: uuid.New()}, {ID: uuid.New()}}
+	s.NoError(s.db.WithContext(s.ctx(s.orgB)).Create(&rows).Error)
+	for _, r := range rows {
+		s.Equal(s.orgB, r.OrganizationID)
+	}
+}
+
+func (s *TenantTestSuite) TestCreateForOtherTenantIsRejected() {
+	row := record{ID: uuid.New(), OrganizationID: s.orgB}
+	err := s.db.WithContext(s.ctx(s.orgA)).Create(&row).Error
+	s.ErrorIs(err, ErrCrossTenant)
+	s.Equal(s.orgB, row.OrganizationID)
+
+	rows := []record{{ID: uuid.New()}, {ID: uuid.New(), OrganizationID: s.orgB}}
+	s.ErrorIs(s.db.WithContext(s.ctx(s.orgA)).Create(&rows).Error, ErrCrossTenant)
+}
+
+func (s *TenantTestSuite) TestUpsertWithoutTenantIsRejected() {
+	// Upsert record tanpa organisasi bisa menimpa baris tenant lain dengan id yang sama
+	row := record{ID: uuid.New()}
+	err := s.db.WithContext(s.ctx(s.orgA)).Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error
+	s.ErrorIs(err, ErrCrossTenant)
+}
+
+func (s *TenantTestSuite) TestNoTenantMeansNoFilter() {
+	var rows []record
+	stmt := s.db.WithContext(context.Background()).Find(&rows).Statement
+	s.NotContains(stmt.SQL.String(), "organization_id")
+
+	row := record{ID: uuid.New()}
+	s.NoError(s.db.WithContext(context.Background()).Create(&row).Error)
+	s.Equal(uuid.Nil, row.OrganizationID)
+}
+
+func (s *TenantTestSuite) TestModelWithoutOrganizationIsNotScoped() {
+	var rows []globalRecord
+	stmt := s.db.WithContext(s.ctx(s.orgA)).Find(&rows).Statement
+	s.NotContains(stmt.SQL.String(), "organization_id")
+}
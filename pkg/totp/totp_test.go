@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPTestSuite struct {
+	suite.Suite
+}
+
+func TestTOTPSuite(t *testing.T) {
+	suite.Run(t, new(TOTPTestSuite))
+}
+
+// secret uji dari RFC 6238 lampiran B ("12345678901234567890")
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func (s *TOTPTestSuite) TestCodeMatchesRFCVectors() {
+	// 6 digit terakhir dari vektor uji SHA1 8 digit
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		s.Require().NoError(err)
+		s.Equal(expected, code, "time %d", unix)
+	}
+}
+
+func (s *TOTPTestSuite) TestValidateAllowsSkew() {
+	now := time.Unix(1111111111, 0)
+	previous, err := Code(rfcSecret, Step(now)-1)
+	s.Require().NoError(err)
+
+	step, ok := Validate(rfcSecret, previous, now)
+	s.True(ok)
+	s.Equal(Step(now)-1, step)
+
+	old, err := Code(rfcSecret, Step(now)-2)
+	s.Require().NoError(err)
+	_, ok = Validate(rfcSecret, old, now)
+	s.False(ok)
+
+	_, ok = Validate(rfcSecret, "12345", now)
+	s.False(ok)
+	_, ok = Validate("not base32!", "123456", now)
+	s.False(ok)
+}
+
+func (s *TOTPTestSuite) TestGenerateSecretAndURI() {
+	secret, err := GenerateSecret()
+	s.Require().NoError(err)
+	s.Len(secret, 32)
+
+	uri := URI("Acme Spaces", "ana@example.com", secret)
+	s.True(strings.HasPrefix(uri, "otpauth://totp/Acme%20Spaces:ana@example.com?"))
+	s.Contains(uri, "secret="+secret)
+	s.Contains(uri, "issuer=Acme+Spaces")
+}
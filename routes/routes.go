@@ -1,12 +1,22 @@
 package routes
 
 import (
+	apiKeyHandler "booking/internal/apikey"
 	bookingHandler "booking/internal/booking"
+	calendarHandler "booking/internal/calendar"
 	categoryHandler "booking/internal/category"
+	currencyHandler "booking/internal/currency"
 	facilityHandler "booking/internal/facility"
+	notificationHandler "booking/internal/notification"
+	organizationHandler "booking/internal/organization"
+	roleHandler "booking/internal/role"
+	sessionHandler "booking/internal/session"
 	spaceHandler "booking/internal/space"
 	spaceFacilityHandler "booking/internal/space_facility"
 	userHandler "booking/internal/user"
+	webhookHandler "booking/internal/webhook"
+	"booking/pkg/middleware"
+	"booking/shared/constants"
 
 	"github.com/labstack/echo/v4"
 )
@@ -16,69 +26,260 @@ func SetupRoutes(
 	userHandler *userHandler.UserHandler,
 	categoryHandler *categoryHandler.CategoryHandler,
 	spaceHandler *spaceHandler.SpaceHandler,
+	photoHandler *spaceHandler.PhotoHandler,
 	facilityHandler *facilityHandler.FacilityHandler,
 	spaceFacilityHandler *spaceFacilityHandler.SpaceFacilityHandler,
 	bookingHandler *bookingHandler.BookingHandler,
+	currencyHandler *currencyHandler.CurrencyHandler,
+	calendarHandler *calendarHandler.CalendarHandler,
+	notificationHandler *notificationHandler.NotificationHandler,
+	webhookHandler *webhookHandler.WebhookHandler,
+	organizationHandler *organizationHandler.OrganizationHandler,
+	roleHandler *roleHandler.RoleHandler,
+	sessionHandler *sessionHandler.SessionHandler,
+	oidcHandler *userHandler.OIDCHandler,
+	adminHandler *userHandler.AdminHandler,
+	apiKeyHandler *apiKeyHandler.APIKeyHandler,
 	authMiddleware echo.MiddlewareFunc,
-	adminMiddleware echo.MiddlewareFunc,
+	requirePermission middleware.PermissionFunc,
+	spaceOwnerMiddleware echo.MiddlewareFunc,
+	tenantMiddleware echo.MiddlewareFunc,
+	platformMiddleware echo.MiddlewareFunc,
 ) {
+	// Platform routes: pengelolaan organisasi oleh operator platform (di luar tenant)
+	platform := e.Group("/platform/v1/organizations")
+	platform.Use(platformMiddleware)
+	{
+		platform.POST("", organizationHandler.Create)
+		platform.GET("", organizationHandler.GetAll)
+		platform.GET("/:id", organizationHandler.GetByID)
+		platform.PUT("/:id/active", organizationHandler.SetActive)
+	}
+	// Nilai tukar berlaku untuk semua organisasi (dipakai saat quote dan pembayaran booking),
+	// sehingga hanya bisa diubah operator platform
+	exchangeRates := e.Group("/platform/v1/exchange-rates")
+	exchangeRates.Use(platformMiddleware)
+	{
+		exchangeRates.GET("", currencyHandler.GetAll)
+		exchangeRates.POST("/import", currencyHandler.Import)
+		exchangeRates.GET("/:currency", currencyHandler.GetByCurrency)
+		exchangeRates.PUT("/:currency", currencyHandler.Upsert)
+		exchangeRates.DELETE("/:currency", currencyHandler.Delete)
+	}
+
+	// Calendar feeds (diamankan dengan token rahasia di query string)
+	e.GET("/spaces/:id/calendar.ics", calendarHandler.SpaceFeed)
+	e.GET("/users/:id/calendar.ics", calendarHandler.UserFeed)
+
+	// Verifikasi email dan reset password; organisasi diambil dari token yang ditandatangani
+	e.POST("/verify-email", userHandler.VerifyEmail)
+	e.POST("/password/reset", userHandler.ResetPassword)
+	// Callback login OIDC; organisasi diambil dari state yang disimpan saat login dimulai
+	e.GET("/auth/oidc/:provider/callback", oidcHandler.Callback)
+	// Public key untuk memverifikasi access token; key berlaku untuk semua organisasi
+	e.GET("/.well-known/jwks.json", sessionHandler.JWKS)
+
+	// Semua route di bawah ini dibatasi ke organisasi dari subdomain / header X-Organization
+	tenant := e.Group("")
+	tenant.Use(tenantMiddleware)
+
 	// Public routes
-	e.POST("/register", userHandler.Register)
-	e.POST("/login", userHandler.Login)
+	tenant.POST("/register", userHandler.Register)
+	tenant.POST("/login", userHandler.Login)
+	tenant.POST("/login/2fa", userHandler.LoginTwoFactor)
+	tenant.GET("/auth/oidc", oidcHandler.Providers)
+	tenant.GET("/auth/oidc/:provider", oidcHandler.Begin)
+	tenant.POST("/auth/refresh", sessionHandler.Refresh)
+	tenant.POST("/password/forgot", userHandler.ForgotPassword)
 	// e.POST("/booking", bookingHandler.Create)
+	// Katalog space publik
+	catalog := tenant.Group("/v1")
+	{
+		catalog.GET("/spaces", spaceHandler.GetPublicAll)
+		catalog.GET("/spaces/search", spaceHandler.Search)
+		catalog.GET("/spaces/:id", spaceHandler.GetPublicByID)
+		catalog.GET("/categories", categoryHandler.GetTree)
+		catalog.GET("/categories/:slug", categoryHandler.GetBySlug)
+		catalog.GET("/categories/:slug/spaces", spaceHandler.GetByCategory)
+	}
 
 	// Protected routes
-	protected := e.Group("")
+	protected := tenant.Group("")
 	protected.Use(authMiddleware)
 	{
 		protected.POST("/booking", bookingHandler.Create)
+		protected.GET("/booking", bookingHandler.GetAll)
+		protected.GET("/booking/quote", bookingHandler.Quote)
+		protected.GET("/booking/:id", bookingHandler.GetByID)
+		protected.PUT("/booking/:id/cancel", bookingHandler.Cancel)
 		// User routes
-		protected.POST("/logout", userHandler.Logout)
+		protected.POST("/logout", sessionHandler.Logout, middleware.RequireSession)
+		protected.POST("/verify-email/resend", userHandler.ResendVerification)
+		protected.PUT("/password", userHandler.ChangePassword, middleware.RequireSession)
+		protected.POST("/2fa/setup", userHandler.SetupTwoFactor, middleware.RequireSession)
+		protected.POST("/2fa/confirm", userHandler.ConfirmTwoFactor, middleware.RequireSession)
+		protected.POST("/2fa/disable", userHandler.DisableTwoFactor, middleware.RequireSession)
+		protected.POST("/logout/all", sessionHandler.LogoutAll, middleware.RequireSession)
+		protected.GET("/sessions", sessionHandler.GetAll)
+		protected.DELETE("/sessions/:id", sessionHandler.Revoke)
+		// API key untuk klien mesin; key baru hanya bisa dibuat dari login biasa
+		protected.GET("/api-keys", apiKeyHandler.GetAll)
+		protected.POST("/api-keys", apiKeyHandler.Create, middleware.RequireSession)
+		protected.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+		protected.GET("/calendar/feed", calendarHandler.GetMyFeed)
+		protected.POST("/calendar/feed/rotate", calendarHandler.RotateMyFeed)
 		// users routes
 		users := protected.Group("/admin/v1/user")
 		{
 			users.GET("/me", userHandler.GetMe)
 			users.PUT("/update", userHandler.UpdateProfile)
-			users.DELETE("/delete", userHandler.DeleteAccount)
-			users.GET("", userHandler.GetAllUsers)
-			// Endpoint untuk update role (hanya superadmin)
-			users.PUT("/:id/update", userHandler.UpdateUserRole)
+			users.DELETE("/delete", userHandler.DeleteAccount, middleware.RequireSession)
+			users.GET("", userHandler.GetAllUsers, requirePermission(constants.PermissionUserRead))
+			// Endpoint untuk update role
+			users.PUT("/:id/update", userHandler.UpdateUserRole, requirePermission(constants.PermissionUserManage))
+			users.POST("/:id/unlock", userHandler.UnlockUser, requirePermission(constants.PermissionUserManage))
+			users.GET("/:id/api-keys", apiKeyHandler.GetByUser, requirePermission(constants.PermissionUserManage))
+			users.DELETE("/:id/api-keys/:keyId", apiKeyHandler.RevokeForUser, requirePermission(constants.PermissionUserManage))
+			// Tindakan admin dicatat di audit log; tidak bisa dilakukan lewat API key atau token impersonasi
+			users.POST("/:id/suspend", adminHandler.Suspend, requirePermission(constants.PermissionUserManage), middleware.RequireSession)
+			users.POST("/:id/unsuspend", adminHandler.Unsuspend, requirePermission(constants.PermissionUserManage), middleware.RequireSession)
+			users.POST("/:id/password-reset", adminHandler.ForcePasswordReset, requirePermission(constants.PermissionUserManage), middleware.RequireSession)
+			users.POST("/:id/impersonate", adminHandler.Impersonate, requirePermission(constants.PermissionUserManage), middleware.RequireSession)
+			users.GET("/:id/audit", adminHandler.AuditLogs, requirePermission(constants.PermissionUserManage))
 		}
 		// Category routes
 		categories := protected.Group("/admin/v1/categories")
-		categories.Use(adminMiddleware)
+		categories.Use(requirePermission(constants.PermissionCategoryWrite))
 		{
 			categories.POST("", categoryHandler.Create)
 			categories.GET("", categoryHandler.GetAll)
+			categories.GET("/tree", categoryHandler.GetTree)
+			categories.GET("/trash", categoryHandler.GetDeleted)
 			categories.GET("/:id", categoryHandler.GetByID)
 			categories.PUT("/:id", categoryHandler.Update)
 			categories.DELETE("/:id", categoryHandler.Delete)
+			categories.POST("/:id/restore", categoryHandler.Restore)
+			categories.GET("/:id/spaces", categoryHandler.GetSpaces)
 		}
 		// Space routes
 		spaces := protected.Group("/admin/v1/spaces")
-		spaces.Use(adminMiddleware)
+		spaces.Use(requirePermission(constants.PermissionSpaceWrite))
 		{
 			spaces.POST("", spaceHandler.Create)
 			spaces.GET("", spaceHandler.GetAll)
+			spaces.GET("/trash", spaceHandler.GetDeleted)
 			spaces.GET("/:id", spaceHandler.GetByID)
 			spaces.PUT("/:id", spaceHandler.Update)
 			spaces.DELETE("/:id", spaceHandler.Delete)
+			spaces.POST("/:id/restore", spaceHandler.Restore)
+			spaces.GET("/:id/photos", photoHandler.GetAll)
+			spaces.POST("/:id/photos", photoHandler.Upload)
+			spaces.PUT("/:id/photos/order", photoHandler.Reorder)
+			spaces.PUT("/:id/photos/:photoId/cover", photoHandler.SetCover)
+			spaces.DELETE("/:id/photos/:photoId", photoHandler.Delete)
+			spaces.GET("/:id/facilities", spaceFacilityHandler.GetBySpace)
+			spaces.PUT("/:id/facilities", spaceFacilityHandler.Replace)
+			spaces.DELETE("/:id/facilities/:facilityId", spaceFacilityHandler.Remove)
+			spaces.GET("/:id/blackouts", spaceHandler.GetBlackouts)
+			spaces.POST("/:id/blackouts", spaceHandler.CreateBlackout)
+			spaces.DELETE("/:id/blackouts/:blackoutId", spaceHandler.DeleteBlackout)
+			spaces.GET("/:id/calendar-feed", calendarHandler.GetSpaceFeed)
+			spaces.POST("/:id/calendar-feed/rotate", calendarHandler.RotateSpaceFeed)
+			spaces.GET("/:id/external-calendars", calendarHandler.GetExternalCalendars)
+			spaces.POST("/:id/external-calendars", calendarHandler.CreateExternalCalendar)
+			spaces.POST("/:id/external-calendars/upload", calendarHandler.UploadExternalCalendar)
+			spaces.POST("/:id/external-calendars/:calendarId/upload", calendarHandler.UploadExternalCalendar)
+			spaces.POST("/:id/external-calendars/:calendarId/sync", calendarHandler.SyncExternalCalendar)
+			spaces.DELETE("/:id/external-calendars/:calendarId", calendarHandler.DeleteExternalCalendar)
+			spaces.GET("/:id/external-blocks", calendarHandler.GetExternalBlocks)
+		}
+		// Host routes: host hanya bisa mengelola space miliknya sendiri
+		host := protected.Group("/host/v1")
+		host.Use(requirePermission(constants.PermissionSpaceHost))
+		{
+			host.GET("/spaces", spaceHandler.GetMine)
+			host.POST("/spaces", spaceHandler.Create)
+			host.GET("/bookings", bookingHandler.GetForOwner)
+			host.PUT("/bookings/:id/cancel", bookingHandler.CancelForOwner)
+
+			ownedSpace := host.Group("/spaces/:id")
+			ownedSpace.Use(spaceOwnerMiddleware)
+			{
+				ownedSpace.GET("", spaceHandler.GetByID)
+				ownedSpace.PUT("", spaceHandler.Update)
+				ownedSpace.DELETE("", spaceHandler.Delete)
+				ownedSpace.GET("/bookings", bookingHandler.GetForOwner)
+				ownedSpace.GET("/blackouts", spaceHandler.GetBlackouts)
+				ownedSpace.POST("/blackouts", spaceHandler.CreateBlackout)
+				ownedSpace.DELETE("/blackouts/:blackoutId", spaceHandler.DeleteBlackout)
+				ownedSpace.GET("/photos", photoHandler.GetAll)
+				ownedSpace.POST("/photos", photoHandler.Upload)
+				ownedSpace.PUT("/photos/order", photoHandler.Reorder)
+				ownedSpace.PUT("/photos/:photoId/cover", photoHandler.SetCover)
+				ownedSpace.DELETE("/photos/:photoId", photoHandler.Delete)
+				ownedSpace.GET("/facilities", spaceFacilityHandler.GetBySpace)
+				ownedSpace.PUT("/facilities", spaceFacilityHandler.Replace)
+				ownedSpace.DELETE("/facilities/:facilityId", spaceFacilityHandler.Remove)
+				ownedSpace.GET("/calendar-feed", calendarHandler.GetSpaceFeed)
+				ownedSpace.POST("/calendar-feed/rotate", calendarHandler.RotateSpaceFeed)
+			}
 		}
 		// Facility routes
 		facilities := protected.Group("/admin/v1/facilities")
-		facilities.Use(adminMiddleware)
+		facilities.Use(requirePermission(constants.PermissionFacilityWrite))
 		{
 			facilities.POST("", facilityHandler.Create)
 			facilities.GET("", facilityHandler.GetAll)
+			facilities.GET("/trash", facilityHandler.GetDeleted)
 			facilities.GET("/:id", facilityHandler.GetByID)
 			facilities.PUT("/:id", facilityHandler.Update)
 			facilities.DELETE("/:id", facilityHandler.Delete)
+			facilities.POST("/:id/restore", facilityHandler.Restore)
+			facilities.GET("/:id/spaces", spaceFacilityHandler.GetSpaces)
 		}
 		// Space Facility routes
 		spaceFacilities := protected.Group("/admin/v1/space-facilities")
-		spaceFacilities.Use(adminMiddleware)
+		spaceFacilities.Use(requirePermission(constants.PermissionSpaceWrite))
 		{
 			spaceFacilities.POST("", spaceFacilityHandler.Create)
 		}
+		// Booking admin routes
+		adminBookings := protected.Group("/admin/v1/bookings")
+		adminBookings.Use(requirePermission(constants.PermissionBookingManage))
+		{
+			adminBookings.PUT("/:id/pay", bookingHandler.MarkPaid)
+		}
+		// Notification outbox routes
+		notifications := protected.Group("/admin/v1/notifications")
+		notifications.Use(requirePermission(constants.PermissionNotificationManage))
+		{
+			notifications.GET("", notificationHandler.GetAll)
+			notifications.POST("/:id/retry", notificationHandler.Retry)
+		}
+		// Webhook routes
+		webhooks := protected.Group("/admin/v1/webhooks")
+		webhooks.Use(requirePermission(constants.PermissionWebhookManage))
+		{
+			webhooks.POST("", webhookHandler.Create)
+			webhooks.GET("", webhookHandler.GetAll)
+			webhooks.GET("/deliveries/:deliveryId", webhookHandler.GetDelivery)
+			webhooks.POST("/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+			webhooks.GET("/:id", webhookHandler.GetByID)
+			webhooks.PUT("/:id", webhookHandler.Update)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+			webhooks.POST("/:id/rotate-secret", webhookHandler.RotateSecret)
+			webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+		}
+		// Role & permission routes
+		roles := protected.Group("/admin/v1/roles")
+		roles.Use(requirePermission(constants.PermissionRoleManage))
+		{
+			roles.GET("/permissions", roleHandler.GetPermissions)
+			roles.POST("", roleHandler.Create)
+			roles.GET("", roleHandler.GetAll)
+			roles.GET("/:id", roleHandler.GetByID)
+			roles.PUT("/:id", roleHandler.Update)
+			roles.DELETE("/:id", roleHandler.Delete)
+		}
 	}
 }
@@ -5,11 +5,15 @@ import "golang.org/x/crypto/bcrypt"
 type (
 	Role          string
 	BookingStatus string
+	Currency      string
+	EventType     string
+	Locale        string
 )
 
 const (
 	RoleSuperAdmin Role = "superadmin"
 	RoleAdmin      Role = "admin"
+	RoleHost       Role = "host"
 	RoleUser       Role = "user"
 
 	PasswordMinLength = 6
@@ -18,4 +22,24 @@ const (
 	BookingStatusPending   BookingStatus = "pending"
 	BookingStatusPaid      BookingStatus = "paid"
 	BookingStatusCancelled BookingStatus = "cancelled"
+
+	// BaseCurrency adalah mata uang yang dipakai untuk menyimpan harga dan menagih pembayaran
+	BaseCurrency Currency = "IDR"
+
+	EventBookingCreated   EventType = "booking.created"
+	EventBookingPaid      EventType = "booking.paid"
+	EventBookingCancelled EventType = "booking.cancelled"
+	EventSpaceCreated     EventType = "space.created"
+	EventSpaceUpdated     EventType = "space.updated"
+	EventSpaceDeleted     EventType = "space.deleted"
+	EventSpaceRestored    EventType = "space.restored"
+
+	// Event email akun; tidak dikirim sebagai webhook
+	EventEmailVerification EventType = "user.email_verification"
+	EventPasswordReset     EventType = "user.password_reset"
+	EventAccountLocked     EventType = "user.account_locked"
+
+	LocaleIndonesian Locale = "id"
+	LocaleEnglish    Locale = "en"
+	DefaultLocale           = LocaleIndonesian
 )
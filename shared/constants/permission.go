@@ -0,0 +1,33 @@
+package constants
+
+type Permission string
+
+const (
+	// PermissionAll memberikan semua permission (dipakai role superadmin)
+	PermissionAll Permission = "*"
+
+	PermissionUserRead           Permission = "user:read"
+	PermissionUserManage         Permission = "user:manage"
+	PermissionRoleManage         Permission = "role:manage"
+	PermissionCategoryWrite      Permission = "category:write"
+	PermissionFacilityWrite      Permission = "facility:write"
+	PermissionSpaceWrite         Permission = "space:write"
+	PermissionSpaceHost          Permission = "space:host"
+	PermissionBookingManage      Permission = "booking:manage"
+	PermissionNotificationManage Permission = "notification:manage"
+	PermissionWebhookManage      Permission = "webhook:manage"
+)
+
+// Permissions adalah daftar permission yang bisa diberikan ke sebuah role
+var Permissions = []Permission{
+	PermissionUserRead,
+	PermissionUserManage,
+	PermissionRoleManage,
+	PermissionCategoryWrite,
+	PermissionFacilityWrite,
+	PermissionSpaceWrite,
+	PermissionSpaceHost,
+	PermissionBookingManage,
+	PermissionNotificationManage,
+	PermissionWebhookManage,
+}
@@ -10,4 +10,21 @@ var (
 	ErrEmailAlreadyRegistered = errors.New("email already registered")
 	ErrInvalidCredentials     = errors.New("invalid email or password")
 	ErrUnauthorized           = errors.New("unauthorized access")
+	ErrUnsupportedCurrency    = errors.New("unsupported currency")
+	ErrInvalidToken           = errors.New("invalid or expired token")
+	ErrEmailNotVerified       = errors.New("email address has not been verified")
+	ErrEmailAlreadyVerified   = errors.New("email address is already verified")
+	ErrTooManyRequests        = errors.New("too many requests, please try again later")
+	ErrSamePassword           = errors.New("new password must be different from the current password")
+	ErrTwoFactorEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled    = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetup      = errors.New("two-factor authentication has not been set up")
+	ErrInvalidTwoFactorCode   = errors.New("invalid two-factor authentication code")
+	ErrUnknownProvider        = errors.New("unknown login provider")
+	ErrInvalidOAuthState      = errors.New("invalid or expired login state")
+	ErrProviderEmailInvalid   = errors.New("email address is not verified by the login provider")
+	ErrAccountSuspended       = errors.New("account has been suspended")
+	ErrPasswordResetRequired  = errors.New("password reset is required, check your email for the reset link")
+	ErrSelfAdminAction        = errors.New("this action cannot be performed on your own account")
+	ErrImpersonationDenied    = errors.New("this user cannot be impersonated")
 )